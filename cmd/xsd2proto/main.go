@@ -1,43 +1,122 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/i-icc/xsd2proto"
-	"github.com/i-icc/xsd2proto/internal/converter"
-	"github.com/i-icc/xsd2proto/internal/generator"
-	"github.com/i-icc/xsd2proto/internal/parser"
 )
 
 const usageText = `xsd2proto - Convert XSD files to Protocol Buffer definitions
 
 Usage:
   xsd2proto [options] <input.xsd>
+  xsd2proto --merge [options] <input.xsd>...
+  xsd2proto --stdin [options] (-o <output.proto> | --stdout)
+  xsd2proto --manifest <files.txt> [options]
 
 Options:
   -o, --output string     Output file path (default: input filename with .proto extension)
   -p, --package string    Go package option for generated proto file
-  -pp, --proto-package string  Proto package name (overrides namespace-based package generation)
+  -P, --proto-package string   Proto package name (overrides namespace-based package generation)
+      --package-prefix string  Package prepended to the packages derived from the namespace
+  -w, --watch            Reconvert whenever the input XSD or a schema it imports changes
+      --java-package string          java_package option
+      --java-outer-classname string  java_outer_classname option
+      --csharp-namespace string      csharp_namespace option
+      --objc-class-prefix string     objc_class_prefix option
+      --php-namespace string         php_namespace option
+      --ruby-package string          ruby_package option
   -v, --verbose           Enable verbose output
   -h, --help             Show this help message
       --version          Show version information
       --no-header        Disable auto-generation header comment
-      --camel-case       Use camelCase for field names instead of snake_case
-      --pascal-case      Use PascalCase for field names instead of snake_case
+      --header-first     Emit the header comment before the syntax line
+  -C, --camel-case       Use camelCase for field names instead of snake_case
+  -P2, --pascal-case     Use PascalCase for field names instead of snake_case
+      --field-number-offset int  Starting field number in each message (default: 1)
+      --enum-value-prefix-style string  Enum value prefix: enum-name, type-name or none (default: enum-name)
+      --merge            Merge all input XSD files into a single proto file
+      --emit-dot string  Write a Graphviz DOT graph of message dependencies
+      --split-files      Write one .proto file per message into the output directory (-o)
+      --attributes-first Emit attribute fields before sequence fields
+      --require-target-namespace  Fail when the XSD has no targetNamespace
+      --trace string     Write a JSON Lines trace of conversion decisions
+      --stdin            Read the XSD from stdin (imports and includes are skipped)
+      --stdout           Write the proto to stdout
+      --dry-run          Convert without writing files and print statistics
+      --proto3-optional  Mark only scalar and enum fields optional (message fields have presence)
+      --output-encoding string  Output encoding: utf-8, utf-8-bom or ascii (default: utf-8)
+      --no-wrappers      Use plain scalars instead of wrapper types for nillable elements
+      --emit-validate-annotations  Emit protovalidate rules for facets and fixed values
+      --dump-type-graph string  Write a DOT graph of XSD type dependencies ("-" for stdout)
+      --field-map string  JSON file of field numbers kept stable across runs (created if missing)
+      --reserved-map string  JSON file of removed fields to emit as reserved per message
+      --use-int32-for-small-integers  Comment byte, unsignedByte, short and unsignedShort fields with their XSD type
+      --use-exact-integer-types  Map xs:byte and xs:short to sint32 and xs:unsignedByte to uint32
+      --timestamps-for-date  Map xs:date to google.protobuf.Timestamp instead of string
+      --timestamps-for-time  Map xs:time to google.protobuf.Timestamp instead of string
+      --collect-errors   Report the errors of all failing types instead of stopping at the first
+      --emit-openapi-extensions  Also write <name>.openapi.yaml mapping messages to their XSD origin
+      --buf-scaffold     Write buf.yaml and buf.gen.yaml next to the proto output
+      --stats            Print schema and conversion statistics after converting
+      --allow-alias      Give enumeration values differing only in case one number (option allow_alias)
+      --no-unspecified   Omit the UNSPECIFIED enum value and number enumeration values from 0
+      --max-parallel-imports int  Imports of a schema parsed concurrently (default: number of CPUs)
+      --max-recursion-depth int  Deepest nesting of imports and includes followed (default: 50)
+      --output-descriptor string  Also write the binary FileDescriptorProto of the output
+      --markdown-docs path.md  Also write a Markdown reference of the generated messages and enums
+      --catalog string   OASIS XML Catalog resolving import namespaces and locations to local files
+      --fetch-remote     Download imports and includes with an http or https schemaLocation
+      --fetch-timeout duration  Timeout of each schema download (default: 30s)
+      --schema-cache-dir string  Directory caching downloaded schemas across runs
+      --map-key-name string  Key element name of entry types converted to map fields (default: key)
+      --map-value-name string  Value element name of entry types converted to map fields (default: value)
+      --merge-with path.proto  Merge the messages and enums of an existing proto file into the output
+      --template path    Render the output with a Go text/template instead of the proto layout
+      --mixed-as-bytes   Emit the text of mixed content types as bytes instead of string
+      --report report.json  Write a JSON report of the conversion
+      --unbounded-choice-suffix string  Suffix of the wrapper message of a repeated choice (default: Item)
+      --indent string    One level of indentation of spaces and tabs, \t for a tab (default: two spaces)
+      --strict           Fail on type references that no schema declares instead of warning
+      --keep-original-names  Use XSD type names verbatim as message and enum names
+      --output-zip path.zip  Write the proto files into a zip archive instead of the output path
+      --dump-type-mappings  Print the XSD to proto type mappings as JSON and exit
+      --source-info      Comment messages and fields with the file and line of their XSD declaration
+      --diff             Print a unified diff against the existing output instead of writing it (exit 1 on changes, 2 on errors)
+      --import-sort string  Import order: deps (project imports first) or alpha (default: deps)
+      --import-group-separator  Separate google/protobuf imports from project imports with a blank line
+      --inline-optional-groups  Keep the elements of optional sequences as optional fields of the parent
+      --manifest path    Convert each XSD file listed in a text file, one path per line
 
 Examples:
   xsd2proto schema.xsd                          # Convert schema.xsd to schema.proto
   xsd2proto -o output.proto schema.xsd         # Convert with custom output path
   xsd2proto -p "example.com/proto" schema.xsd  # Convert with go_package option
   xsd2proto -pp "my.package" schema.xsd        # Convert with custom proto package name
+  xsd2proto --java-package com.example schema.xsd  # Convert with java_package option
   xsd2proto -v schema.xsd                       # Convert with verbose output
   xsd2proto --no-header schema.xsd             # Convert without header comment
   xsd2proto --camel-case schema.xsd            # Convert with camelCase field names
   xsd2proto --pascal-case schema.xsd           # Convert with PascalCase field names
+  xsd2proto --field-number-offset 10 schema.xsd  # Number fields starting from 10
+  xsd2proto --enum-value-prefix-style none schema.xsd  # Emit enum values without prefix
+  xsd2proto --merge -o api.proto a.xsd b.xsd   # Merge several schemas into one proto file
+  xsd2proto --emit-dot schema.dot schema.xsd   # Also write a dependency graph
+  xsd2proto --split-files -o protos/ schema.xsd  # Write one file per message
+  xsd2proto --split-files --output-zip protos.zip schema.xsd  # Bundle the split files
+  xsd2proto --trace trace.jsonl schema.xsd     # Record conversion decisions
+  cat schema.xsd | xsd2proto --stdin --stdout  # Use in a shell pipeline
+  xsd2proto --dry-run schema.xsd               # Check that schema.xsd converts
+  xsd2proto --dump-type-graph=- schema.xsd | dot -Tsvg > types.svg  # Inspect type references
+  xsd2proto --field-map fields.json schema.xsd  # Keep field numbers stable as the XSD evolves
+  xsd2proto --manifest schemas.txt            # Convert every schema listed in schemas.txt
 `
 
 func main() {
@@ -45,16 +124,83 @@ func main() {
 		outputPath   = flag.String("o", "", "Output file path")
 		goPackage    = flag.String("p", "", "Go package option")
 		protoPackage = flag.String("pp", "", "Proto package name")
+		javaPackage  = flag.String("java-package", "", "java_package option")
+		javaOuter    = flag.String("java-outer-classname", "", "java_outer_classname option")
+		csharpNS     = flag.String("csharp-namespace", "", "csharp_namespace option")
+		objcPrefix   = flag.String("objc-class-prefix", "", "objc_class_prefix option")
+		phpNS        = flag.String("php-namespace", "", "php_namespace option")
+		rubyPackage  = flag.String("ruby-package", "", "ruby_package option")
 		verbose      = flag.Bool("v", false, "Enable verbose output")
 		help         = flag.Bool("h", false, "Show help")
 		version      = flag.Bool("version", false, "Show version")
 		noHeader     = flag.Bool("no-header", false, "Disable auto-generation header comment")
+		headerFirst  = flag.Bool("header-first", false, "Emit the header comment before the syntax line")
 		camelCase    = flag.Bool("camel-case", false, "Use camelCase for field names instead of snake_case")
 		pascalCase   = flag.Bool("pascal-case", false, "Use PascalCase for field names instead of snake_case")
+		fieldOffset  = flag.Int("field-number-offset", 1, "Starting field number in each message")
+		enumPrefix   = flag.String("enum-value-prefix-style", "enum-name", "Enum value prefix style: enum-name, type-name or none")
+		merge        = flag.Bool("merge", false, "Merge all input XSD files into a single proto file")
+		emitDot      = flag.String("emit-dot", "", "Write a Graphviz DOT graph of message dependencies")
+		splitFiles   = flag.Bool("split-files", false, "Write one .proto file per message")
+		attrsFirst   = flag.Bool("attributes-first", false, "Emit attribute fields before sequence fields")
+		requireNS    = flag.Bool("require-target-namespace", false, "Fail when the XSD has no targetNamespace")
+		tracePath    = flag.String("trace", "", "Write a JSON Lines trace of conversion decisions")
+		useStdin     = flag.Bool("stdin", false, "Read the XSD from stdin")
+		useStdout    = flag.Bool("stdout", false, "Write the proto to stdout")
+		dryRun       = flag.Bool("dry-run", false, "Convert without writing files and print statistics")
+		proto3Opt    = flag.Bool("proto3-optional", false, "Mark only scalar and enum fields optional")
+		outEncoding  = flag.String("output-encoding", "utf-8", "Output encoding: utf-8, utf-8-bom or ascii")
+		noWrappers   = flag.Bool("no-wrappers", false, "Use plain scalars instead of wrapper types for nillable elements")
+		emitValidate = flag.Bool("emit-validate-annotations", false, "Emit protovalidate rules for facets and fixed values")
+		typeGraph    = flag.String("dump-type-graph", "", "Write a DOT graph of XSD type dependencies (\"-\" for stdout)")
+		fieldMapPath = flag.String("field-map", "", "JSON file of field numbers kept stable across runs")
+		reservedMap  = flag.String("reserved-map", "", "JSON file of removed fields to emit as reserved per message")
+		smallInts    = flag.Bool("use-int32-for-small-integers", false, "Comment small integer fields with their XSD type")
+		exactInts    = flag.Bool("use-exact-integer-types", false, "Map xs:byte and xs:short to sint32 and xs:unsignedByte to uint32")
+		tsDate       = flag.Bool("timestamps-for-date", false, "Map xs:date to google.protobuf.Timestamp instead of string")
+		tsTime       = flag.Bool("timestamps-for-time", false, "Map xs:time to google.protobuf.Timestamp instead of string")
+		collectErrs  = flag.Bool("collect-errors", false, "Report the errors of all failing types instead of stopping at the first")
+		emitOpenAPI  = flag.Bool("emit-openapi-extensions", false, "Also write <name>.openapi.yaml mapping messages to their XSD origin")
+		bufScaffold  = flag.Bool("buf-scaffold", false, "Write buf.yaml and buf.gen.yaml next to the proto output")
+		showStats    = flag.Bool("stats", false, "Print schema and conversion statistics after converting")
+		allowAlias   = flag.Bool("allow-alias", false, "Give enumeration values differing only in case one number")
+		noUnspec     = flag.Bool("no-unspecified", false, "Omit the UNSPECIFIED enum value and number enumeration values from 0")
+		maxParallel  = flag.Int("max-parallel-imports", runtime.NumCPU(), "Imports of a schema parsed concurrently")
+		maxDepth     = flag.Int("max-recursion-depth", 50, "Deepest nesting of imports and includes followed")
+		descriptor   = flag.String("output-descriptor", "", "Also write the binary FileDescriptorProto of the output")
+		catalogPath  = flag.String("catalog", "", "OASIS XML Catalog resolving import namespaces and locations to local files")
+		fetchRemote  = flag.Bool("fetch-remote", false, "Download imports and includes with an http or https schemaLocation")
+		fetchTimeout = flag.Duration("fetch-timeout", 30*time.Second, "Timeout of each schema download")
+		cacheDir     = flag.String("schema-cache-dir", "", "Directory caching downloaded schemas across runs")
+		mapKeyName   = flag.String("map-key-name", "key", "Key element name of entry types converted to map fields")
+		mapValueName = flag.String("map-value-name", "value", "Value element name of entry types converted to map fields")
+		mergeWith    = flag.String("merge-with", "", "Merge the messages and enums of an existing proto file into the output")
+		templatePath = flag.String("template", "", "Render the output with a Go text/template instead of the proto layout")
+		mixedAsBytes = flag.Bool("mixed-as-bytes", false, "Emit the text of mixed content types as bytes instead of string")
+		reportPath   = flag.String("report", "", "Write a JSON report of the conversion")
+		choiceSuffix = flag.String("unbounded-choice-suffix", "Item", "Suffix of the wrapper message of a repeated choice")
+		indent       = flag.String("indent", "  ", "One level of indentation of spaces and tabs, \\t for a tab")
+		strict       = flag.Bool("strict", false, "Fail on type references that no schema declares instead of warning")
+		keepNames    = flag.Bool("keep-original-names", false, "Use XSD type names verbatim as message and enum names")
+		outputZip    = flag.String("output-zip", "", "Write the proto files into a zip archive instead of the output path")
+		dumpMappings = flag.Bool("dump-type-mappings", false, "Print the XSD to proto type mappings as JSON and exit")
+		sourceInfo   = flag.Bool("source-info", false, "Comment messages and fields with the file and line of their XSD declaration")
+		diffMode     = flag.Bool("diff", false, "Print a unified diff against the existing output instead of writing it")
+		importSort   = flag.String("import-sort", "deps", "Import order: deps (project imports first) or alpha")
+		importGroups = flag.Bool("import-group-separator", false, "Separate google/protobuf imports from project imports with a blank line")
+		inlineGroups = flag.Bool("inline-optional-groups", false, "Keep the elements of optional sequences as optional fields of the parent")
+		manifestPath = flag.String("manifest", "", "Convert each XSD file listed in a text file, one path per line")
+		pkgPrefix    = flag.String("package-prefix", "", "Package prepended to the packages derived from the namespace")
+		markdownDocs = flag.String("markdown-docs", "", "Also write a Markdown reference of the generated messages and enums")
+		watch        = flag.Bool("watch", false, "Reconvert whenever the input XSD or a schema it imports changes")
 	)
 
-	// Support --proto-package long form as well
+	// Support -P and the --proto-package long form as well
+	flag.StringVar(protoPackage, "P", "", "Proto package name")
 	flag.StringVar(protoPackage, "proto-package", "", "Proto package name")
+	flag.BoolVar(camelCase, "C", false, "Use camelCase for field names instead of snake_case")
+	flag.BoolVar(pascalCase, "P2", false, "Use PascalCase for field names instead of snake_case")
+	flag.BoolVar(watch, "w", false, "Reconvert whenever the input XSD or a schema it imports changes")
 
 	// Custom usage function
 	flag.Usage = func() {
@@ -75,130 +221,223 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle type mappings flag
+	if *dumpMappings {
+		if err := dumpTypeMappings(xsd2proto.ConvertOptions{
+			FieldNumberOffset: 1,
+			ExactIntegerTypes: *exactInts,
+			TimestampsForDate: *tsDate,
+			TimestampsForTime: *tsTime,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// A diff exits with 1 for changes, so errors exit with 2 there
+	errorExit := 1
+	if *diffMode {
+		errorExit = 2
+	}
+
 	// Check if input file is provided
 	args := flag.Args()
-	if len(args) != 1 {
+	if *manifestPath != "" && (*useStdin || *merge || *useStdout || len(args) != 0) {
+		fmt.Fprintf(os.Stderr, "Error: --manifest cannot be combined with input files, --stdin, --merge or --stdout\n\n")
+		flag.Usage()
+		os.Exit(errorExit)
+	}
+	if *useStdin && (*merge || len(args) != 0) {
+		fmt.Fprintf(os.Stderr, "Error: --stdin does not take input file arguments\n\n")
+		flag.Usage()
+		os.Exit(errorExit)
+	}
+	if *useStdin && !*useStdout && *outputPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: --stdin requires -o or --stdout\n\n")
+		flag.Usage()
+		os.Exit(errorExit)
+	}
+	if *typeGraph == "-" && *useStdout {
+		fmt.Fprintf(os.Stderr, "Error: --dump-type-graph=- cannot be combined with --stdout\n\n")
+		flag.Usage()
+		os.Exit(errorExit)
+	}
+	if *merge && len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: Please provide at least one XSD input file\n\n")
+		flag.Usage()
+		os.Exit(errorExit)
+	}
+	if *watch && (*useStdin || *merge || *useStdout || *manifestPath != "" || *diffMode) {
+		fmt.Fprintf(os.Stderr, "Error: --watch cannot be combined with --stdin, --merge, --stdout, --manifest or --diff\n\n")
+		flag.Usage()
+		os.Exit(errorExit)
+	}
+	if !*useStdin && !*merge && *manifestPath == "" && len(args) != 1 {
 		fmt.Fprintf(os.Stderr, "Error: Please provide exactly one XSD input file\n\n")
 		flag.Usage()
-		os.Exit(1)
+		os.Exit(errorExit)
 	}
 
 	// Check for conflicting field naming options
 	if *camelCase && *pascalCase {
 		fmt.Fprintf(os.Stderr, "Error: Cannot use both --camel-case and --pascal-case options simultaneously\n")
-		os.Exit(1)
+		os.Exit(errorExit)
 	}
 
-	inputPath := args[0]
-
-	// Check if input file exists
-	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: Input file '%s' does not exist\n", inputPath)
-		os.Exit(1)
+	// Check if input files exist
+	for _, inputPath := range args {
+		if _, err := os.Stat(inputPath); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: Input file '%s' does not exist\n", inputPath)
+			os.Exit(errorExit)
+		}
 	}
 
-	// Perform conversion
-	if err := convertXSD(inputPath, *outputPath, *goPackage, *protoPackage, *verbose, !*noHeader, *camelCase, *pascalCase); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	opts := xsd2proto.ConvertOptions{
+		OutputPath:           *outputPath,
+		GoPackage:            *goPackage,
+		JavaPackage:          *javaPackage,
+		JavaOuterClassname:   *javaOuter,
+		CSharpNamespace:      *csharpNS,
+		ObjcClassPrefix:      *objcPrefix,
+		PhpNamespace:         *phpNS,
+		RubyPackage:          *rubyPackage,
+		ProtoPackage:         *protoPackage,
+		PackagePrefix:        *pkgPrefix,
+		Verbose:              *verbose,
+		IncludeHeader:        !*noHeader,
+		CamelCase:            *camelCase,
+		PascalCase:           *pascalCase,
+		FieldNumberOffset:    *fieldOffset,
+		EnumValuePrefixStyle: *enumPrefix,
+		DotOutputPath:        *emitDot,
+		SplitFiles:           *splitFiles,
+		AttributesFirst:      *attrsFirst,
+		RequireNamespace:     *requireNS,
+		HeaderFirst:          *headerFirst,
+		TracePath:            *tracePath,
+		DryRun:               *dryRun,
+		Proto3Optional:       *proto3Opt,
+		OutputEncoding:       *outEncoding,
+		NoWrappers:           *noWrappers,
+		TypeGraphPath:        *typeGraph,
+		FieldMapPath:         *fieldMapPath,
+		ReservedMapPath:      *reservedMap,
+		SmallIntegerComments: *smallInts,
+		ExactIntegerTypes:    *exactInts,
+		TimestampsForDate:    *tsDate,
+		TimestampsForTime:    *tsTime,
+		CollectErrors:        *collectErrs,
+		EmitOpenAPI:          *emitOpenAPI,
+		BufScaffold:          *bufScaffold,
+		Stats:                *showStats,
+		AllowAlias:           *allowAlias,
+		NoUnspecified:        *noUnspec,
+		MaxParallelImports:   *maxParallel,
+		MaxRecursionDepth:    *maxDepth,
+		DescriptorPath:       *descriptor,
+		CatalogPath:          *catalogPath,
+		FetchRemote:          *fetchRemote,
+		FetchTimeout:         *fetchTimeout,
+		SchemaCacheDir:       *cacheDir,
+		MapKeyName:           *mapKeyName,
+		MapValueName:         *mapValueName,
+		MergeWithPath:        *mergeWith,
+		TemplatePath:         *templatePath,
+		MixedAsBytes:         *mixedAsBytes,
+		ReportPath:           *reportPath,
+		ChoiceWrapperSuffix:  *choiceSuffix,
+		Indent:               strings.ReplaceAll(*indent, `\t`, "\t"),
+		Strict:               *strict,
+		KeepOriginalNames:    *keepNames,
+		OutputZipPath:        *outputZip,
+		SourceInfo:           *sourceInfo,
+		Diff:                 *diffMode,
+		ImportSortOrder:      *importSort,
+		ImportGroupSeparator: *importGroups,
+		InlineOptionalGroups: *inlineGroups,
+		MarkdownDocsPath:     *markdownDocs,
+		WarningOutput:        os.Stderr,
 	}
-
-	if !*verbose {
-		fmt.Printf("Successfully converted %s\n", inputPath)
+	if *useStdout {
+		opts.Output = os.Stdout
 	}
-}
-
-func convertXSD(inputPath, outputPath, goPackage, protoPackage string, verbose, includeHeader, camelCase, pascalCase bool) error {
-	if verbose {
-		fmt.Printf("Converting %s to protobuf...\n", inputPath)
+	if *emitValidate {
+		opts.Plugins = append(opts.Plugins, xsd2proto.NewProtovalidatePlugin())
 	}
 
-	// Create instances
-	p := parser.New()
-	conv := converter.New()
-	conv.SetFieldNamingStyle(camelCase, pascalCase)
-	gen := generator.New()
-
-	// Configure generator
-	gen.SetHeaderOptions(includeHeader, xsd2proto.GetVersion())
-
-	// Parse XSD file with imports/includes
-	schema, err := p.ParseFileWithImports(inputPath)
-	if err != nil {
-		return fmt.Errorf("failed to parse XSD file: %w", err)
+	if *watch {
+		if err := watchConvert(args[0], opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(errorExit)
+		}
+		return
 	}
 
-	// Validate parsed schema
-	if err := p.Validate(schema); err != nil {
-		return fmt.Errorf("schema validation failed: %w", err)
+	// Perform conversion
+	var err error
+	switch {
+	case *manifestPath != "":
+		err = processManifest(*manifestPath, opts)
+		args = []string{*manifestPath}
+	case *useStdin:
+		err = xsd2proto.ConvertReader(os.Stdin, opts)
+	case *merge:
+		err = xsd2proto.ConvertFiles(args, opts)
+	default:
+		err = xsd2proto.ConvertFile(args[0], opts)
 	}
-
-	if verbose {
-		fmt.Printf("Successfully parsed XSD schema with %d elements, %d complex types, %d simple types\n",
-			len(schema.Elements), len(schema.ComplexTypes), len(schema.SimpleTypes))
+	if *diffMode && errors.Is(err, xsd2proto.ErrProtoChanged) {
+		os.Exit(1)
 	}
-
-	// Convert to protobuf model
-	protoFile, err := conv.Convert(schema)
 	if err != nil {
-		return fmt.Errorf("failed to convert schema: %w", err)
-	}
-
-	// Override proto package if specified
-	if protoPackage != "" {
-		protoFile.Package = protoPackage
-	}
-
-	// Add go_package option if specified
-	if goPackage != "" {
-		protoFile.Options["go_package"] = goPackage
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(errorExit)
 	}
 
-	// Generate protobuf content
-	content, err := gen.Generate(protoFile)
-	if err != nil {
-		return fmt.Errorf("failed to generate protobuf: %w", err)
+	if !*verbose && !*useStdout && !*dryRun && !*diffMode && *typeGraph != "-" {
+		source := strings.Join(args, ", ")
+		if *useStdin {
+			source = "stdin"
+		}
+		fmt.Printf("Successfully converted %s\n", source)
 	}
+}
 
-	// Determine output path
-	finalOutputPath := outputPath
-	if finalOutputPath == "" {
-		dir := filepath.Dir(inputPath)
-		base := filepath.Base(inputPath)
-		ext := filepath.Ext(base)
-		name := strings.TrimSuffix(base, ext)
-		finalOutputPath = filepath.Join(dir, name+".proto")
+// processManifest converts the files listed in a manifest and prints the errors
+// of the failing ones. It returns an error when any failed, or
+// xsd2proto.ErrProtoChanged when a diff of any of them found changes.
+func processManifest(manifestPath string, opts xsd2proto.ConvertOptions) error {
+	_, errs := xsd2proto.ProcessManifest(manifestPath, opts)
+	var changed, failed int
+	for _, err := range errs {
+		if errors.Is(err, xsd2proto.ErrProtoChanged) {
+			changed++
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		failed++
 	}
-
-	// Write to output file
-	if err := writeToFile(finalOutputPath, content); err != nil {
-		return fmt.Errorf("failed to write output file: %w", err)
+	if failed > 0 {
+		return fmt.Errorf("%d of the files in %s failed to convert", failed, manifestPath)
 	}
-
-	if verbose {
-		fmt.Printf("Successfully generated %s\n", finalOutputPath)
+	if changed > 0 {
+		return xsd2proto.ErrProtoChanged
 	}
-
 	return nil
 }
 
-func writeToFile(path, content string) error {
-	// Create directory if it doesn't exist
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	// Write content to file
-	file, err := os.Create(path)
+// dumpTypeMappings prints the type mappings of the options as a JSON object
+// sorted by XSD type name
+func dumpTypeMappings(opts xsd2proto.ConvertOptions) error {
+	mappings, err := xsd2proto.TypeMappings(opts)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return err
 	}
-	defer file.Close()
-
-	if _, err := file.WriteString(content); err != nil {
-		return fmt.Errorf("failed to write content: %w", err)
+	data, err := json.MarshalIndent(mappings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode type mappings: %w", err)
 	}
-
+	fmt.Println(string(data))
 	return nil
 }
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/i-icc/xsd2proto"
+)
+
+// watchDebounce is how long watch mode waits after a change for further changes,
+// since editors often write a file in several steps
+const watchDebounce = 100 * time.Millisecond
+
+// watchConvert converts inputPath, then reconverts it whenever the file or any of
+// the schemas it imports, includes or redefines is written or created, until
+// interrupted. Each conversion prints a timestamped result line; a failing
+// conversion keeps watching the files of the last successful one. Fetched
+// schemas without a cache directory are kept in one for the whole session.
+func watchConvert(inputPath string, opts xsd2proto.ConvertOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// A conversion removes the temporary cache it creates, together with the
+	// fetched schemas that would be watched
+	if opts.FetchRemote && opts.SchemaCacheDir == "" {
+		cacheDir, err := os.MkdirTemp("", "xsd2proto-schemas")
+		if err != nil {
+			return fmt.Errorf("failed to create schema cache: %w", err)
+		}
+		defer os.RemoveAll(cacheDir)
+		opts.SchemaCacheDir = cacheDir
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// Directories are watched rather than files, so the files stay watched when an
+	// editor saves by replacing them
+	watched := make(map[string]bool)
+	watchedDirs := make(map[string]bool)
+	watchFiles := func(paths []string) error {
+		for _, path := range paths {
+			if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+				continue
+			}
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %s: %w", path, err)
+			}
+			watched[abs] = true
+			if dir := filepath.Dir(abs); !watchedDirs[dir] {
+				if err := watcher.Add(dir); err != nil {
+					return fmt.Errorf("failed to watch %s: %w", dir, err)
+				}
+				watchedDirs[dir] = true
+			}
+		}
+		return nil
+	}
+
+	convert := func(changed string) error {
+		err := xsd2proto.ConvertFile(inputPath, opts)
+		timestamp := time.Now().Format("15:04:05")
+		if err != nil {
+			fmt.Printf("[%s] %s: conversion failed: %v\n", timestamp, changed, err)
+			return watchFiles([]string{inputPath})
+		}
+		fmt.Printf("[%s] %s: converted successfully\n", timestamp, changed)
+		return watchFiles(append([]string{inputPath}, xsd2proto.LastConversionReport().ResolvedImports...))
+	}
+
+	if err := convert(inputPath); err != nil {
+		return err
+	}
+	fmt.Printf("Watching %s for changes, press Ctrl-C to stop\n", inputPath)
+
+	var pending string
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+			if abs, err := filepath.Abs(event.Name); err != nil || !watched[abs] {
+				continue
+			}
+			pending = event.Name
+			debounce = time.After(watchDebounce)
+		case <-debounce:
+			debounce = nil
+			if err := convert(pending); err != nil {
+				return err
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Warning: file watcher: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,1025 @@
+package xsd2proto
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	xsderrors "github.com/i-icc/xsd2proto/internal/errors"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/merger"
+	"github.com/i-icc/xsd2proto/internal/model"
+	"github.com/i-icc/xsd2proto/internal/parser"
+	"github.com/i-icc/xsd2proto/internal/stats"
+)
+
+// ConvertOptions configures the conversion of an XSD file to a proto file
+type ConvertOptions struct {
+	OutputPath           string        // Output file path (default: input filename with .proto extension)
+	GoPackage            string        // go_package option for the generated proto file
+	JavaPackage          string        // java_package option
+	JavaOuterClassname   string        // java_outer_classname option
+	CSharpNamespace      string        // csharp_namespace option
+	ObjcClassPrefix      string        // objc_class_prefix option
+	PhpNamespace         string        // php_namespace option
+	RubyPackage          string        // ruby_package option
+	ProtoPackage         string        // Proto package name (overrides namespace-based package generation)
+	PackagePrefix        string        // Package prepended to the package derived from the namespace, unless it already starts with it
+	Verbose              bool          // Print progress to stdout
+	IncludeHeader        bool          // Include the auto-generation header comment
+	CamelCase            bool          // Use camelCase for field names instead of snake_case
+	PascalCase           bool          // Use PascalCase for field names instead of snake_case
+	FieldNumberOffset    int           // Starting field number in each message; 0 starts at 1
+	EnumValuePrefixStyle string        // Enum value prefix: enum-name (default), type-name or none
+	DotOutputPath        string        // Write a Graphviz DOT graph of message dependencies to this path
+	SplitFiles           bool          // Write one file per message; OutputPath is then the output directory
+	AttributesFirst      bool          // Emit attribute fields before sequence fields
+	RequireNamespace     bool          // Fail when the XSD has no targetNamespace
+	HeaderFirst          bool          // Emit the header comment before the syntax line
+	TracePath            string        // Write a JSON Lines trace of conversion decisions to this path
+	DryRun               bool          // Run the whole pipeline and print statistics without writing files
+	Proto3Optional       bool          // Mark only scalar and enum fields optional; message fields have presence already
+	OutputEncoding       string        // Encoding of the written proto: utf-8 (default), utf-8-bom or ascii
+	NoWrappers           bool          // Keep plain scalars for nillable elements instead of wrapper types
+	TypeGraphPath        string        // Write a DOT graph of XSD type dependencies to this path; "-" writes to stdout
+	FieldMapPath         string        // JSON file of field numbers reused across runs and updated after conversion
+	ReservedMapPath      string        // JSON file of removed fields per message, emitted as reserved statements
+	SmallIntegerComments bool          // Comment fields of xs:byte, xs:unsignedByte, xs:short and xs:unsignedShort with the XSD type
+	ExactIntegerTypes    bool          // Map xs:byte and xs:short to sint32 and xs:unsignedByte to uint32
+	TimestampsForDate    bool          // Map xs:date to google.protobuf.Timestamp instead of string
+	TimestampsForTime    bool          // Map xs:time to google.protobuf.Timestamp instead of string
+	CollectErrors        bool          // Convert every type and report all failures together as a *MultiError
+	EmitOpenAPI          bool          // Also write <name>.openapi.yaml describing the XSD origin of each type
+	BufScaffold          bool          // Write buf.yaml and buf.gen.yaml next to the proto output
+	Stats                bool          // Print schema and conversion statistics after a successful conversion
+	AllowAlias           bool          // Alias enumeration values differing only in case with option allow_alias
+	NoUnspecified        bool          // Omit the UNSPECIFIED enum value and number enumeration values from 0
+	MaxParallelImports   int           // Imports of a schema parsed concurrently; 0 uses the number of CPUs, 1 parses sequentially
+	DescriptorPath       string        // Also write the binary FileDescriptorProto of the output to this path
+	CatalogPath          string        // OASIS XML Catalog mapping import namespaces and locations to local files
+	FetchRemote          bool          // Download imports and includes with an http or https schemaLocation
+	FetchTimeout         time.Duration // Timeout of each download; 0 uses parser.DefaultFetchTimeout
+	SchemaCacheDir       string        // Directory keeping downloaded schemas across runs; empty uses a temporary directory
+	MapKeyName           string        // Key element name of key-value entry types converted to map fields; empty uses "key"
+	MapValueName         string        // Value element name of key-value entry types converted to map fields; empty uses "value"
+	MergeWithPath        string        // Existing proto file whose messages, enums, imports and options are merged into the output
+	TemplatePath         string        // Go text/template rendering the output instead of the built-in proto layout
+	MixedAsBytes         bool          // Emit the text of mixed content types as bytes instead of string
+	ReportPath           string        // Write a JSON ConversionReport of the conversion to this path
+	ChoiceWrapperSuffix  string        // Suffix of the wrapper message of a repeated choice, after the parent name; empty uses "Item"
+	Indent               string        // One level of indentation in the generated proto, of spaces and tabs; empty uses two spaces
+	AbstractOptionImport string        // Proto file defining the bool xsd_abstract message option set on messages of abstract types
+	Strict               bool          // Fail on type references that no schema declares instead of printing warnings
+	KeepOriginalNames    bool          // Use XSD type names verbatim as message and enum names instead of their PascalCase form
+	OutputZipPath        string        // Write the proto files into a zip archive at this path instead of the output path
+	SourceInfo           bool          // Precede messages and fields with a "// source: <file>:<line>" comment locating the XSD declaration
+	MaxRecursionDepth    int           // Deepest nesting of imports, includes and redefines followed; 0 uses parser.DefaultMaxRecursionDepth
+	Diff                 bool          // Print a unified diff against the existing output file instead of writing it; changes return ErrProtoChanged
+	ImportSortOrder      string        // Import order: deps (default) puts project imports before google/protobuf ones, alpha sorts all
+	ImportGroupSeparator bool          // Separate google/protobuf imports from project imports with a blank line
+	InlineOptionalGroups bool          // Keep the elements of sequences with minOccurs="0" as optional fields instead of a <Parent>Group message
+	MarkdownDocsPath     string        // Also write a Markdown reference of the messages, fields and enums to this path
+
+	// Output receives the generated proto instead of OutputPath when set.
+	// Progress messages then go to stderr so they do not mix with the proto.
+	Output io.Writer
+
+	// WarningOutput receives the warnings of the conversion, such as schema
+	// validation findings, unresolved types and XSD features like xs:key that
+	// are dropped. Verbose runs print them to stderr without it; other runs
+	// drop them.
+	WarningOutput io.Writer
+
+	// TypeResolverFunc resolves non built-in types, e.g. against a schema registry
+	TypeResolverFunc TypeResolverFunc
+
+	// Plugins post-process the converted proto file before it is generated
+	Plugins []ConverterPlugin
+}
+
+// TypeResolverFunc resolves a non built-in XSD type from its namespace and local name
+// to a proto type and the import path defining it. An empty protoType leaves the type
+// to the converter.
+type TypeResolverFunc = converter.TypeResolverFunc
+
+// ConverterPlugin post-processes a converted proto file before it is generated
+type ConverterPlugin = generator.ConverterPlugin
+
+// MultiError holds the per-type errors of a conversion run with CollectErrors
+type MultiError = xsderrors.MultiError
+
+// UnresolvedTypeError is a type reference that no schema declares, reported by
+// the Strict option
+type UnresolvedTypeError = xsderrors.UnresolvedTypeError
+
+// MaxRecursionDepthError is a schema nested deeper in imports, includes and
+// redefines than the MaxRecursionDepth option allows
+type MaxRecursionDepthError = xsderrors.MaxRecursionDepthError
+
+// NewValidatePlugin returns a plugin that emits protoc-gen-validate rules for
+// the length and pattern facets of string and bytes fields
+func NewValidatePlugin() ConverterPlugin {
+	return generator.NewValidatePlugin()
+}
+
+// NewProtovalidatePlugin returns a plugin that emits protovalidate rules for the
+// length, pattern and fixed value constraints of fields
+func NewProtovalidatePlugin() ConverterPlugin {
+	return generator.NewProtovalidatePlugin()
+}
+
+// Option configures ConvertOptions
+type Option func(*ConvertOptions)
+
+// DefaultConvertOptions returns the options used when nothing is configured
+func DefaultConvertOptions() ConvertOptions {
+	return ConvertOptions{
+		IncludeHeader:        true,
+		FieldNumberOffset:    1,
+		EnumValuePrefixStyle: string(converter.EnumValuePrefixEnumName),
+	}
+}
+
+// NewConvertOptions returns the default options with the given options applied
+func NewConvertOptions(opts ...Option) ConvertOptions {
+	options := DefaultConvertOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithFieldNumberOffset sets the starting field number in each message
+func WithFieldNumberOffset(n int) Option {
+	return func(o *ConvertOptions) {
+		o.FieldNumberOffset = n
+	}
+}
+
+// WithCamelCase uses camelCase for field names instead of snake_case
+func WithCamelCase() Option {
+	return func(o *ConvertOptions) {
+		o.CamelCase = true
+	}
+}
+
+// WithPascalCase uses PascalCase for field names instead of snake_case
+func WithPascalCase() Option {
+	return func(o *ConvertOptions) {
+		o.PascalCase = true
+	}
+}
+
+// WithNoUnspecified omits the UNSPECIFIED enum value, numbering enumeration values from 0
+func WithNoUnspecified() Option {
+	return func(o *ConvertOptions) {
+		o.NoUnspecified = true
+	}
+}
+
+// WithProto3Optional limits the optional keyword to scalar and enum fields
+func WithProto3Optional() Option {
+	return func(o *ConvertOptions) {
+		o.Proto3Optional = true
+	}
+}
+
+// WithIndent sets the string used for one level of indentation in the generated proto
+func WithIndent(indent string) Option {
+	return func(o *ConvertOptions) {
+		o.Indent = indent
+	}
+}
+
+// WithGoPackage sets the go_package option
+func WithGoPackage(pkg string) Option {
+	return func(o *ConvertOptions) {
+		o.GoPackage = pkg
+	}
+}
+
+// WithJavaPackage sets the java_package option
+func WithJavaPackage(pkg string) Option {
+	return func(o *ConvertOptions) {
+		o.JavaPackage = pkg
+	}
+}
+
+// WithJavaOuterClassname sets the java_outer_classname option
+func WithJavaOuterClassname(name string) Option {
+	return func(o *ConvertOptions) {
+		o.JavaOuterClassname = name
+	}
+}
+
+// WithCSharpNamespace sets the csharp_namespace option
+func WithCSharpNamespace(namespace string) Option {
+	return func(o *ConvertOptions) {
+		o.CSharpNamespace = namespace
+	}
+}
+
+// WithObjcClassPrefix sets the objc_class_prefix option
+func WithObjcClassPrefix(prefix string) Option {
+	return func(o *ConvertOptions) {
+		o.ObjcClassPrefix = prefix
+	}
+}
+
+// WithPhpNamespace sets the php_namespace option
+func WithPhpNamespace(namespace string) Option {
+	return func(o *ConvertOptions) {
+		o.PhpNamespace = namespace
+	}
+}
+
+// WithRubyPackage sets the ruby_package option
+func WithRubyPackage(pkg string) Option {
+	return func(o *ConvertOptions) {
+		o.RubyPackage = pkg
+	}
+}
+
+// WithPlugins adds plugins run on the converted proto file before generation
+func WithPlugins(plugins ...ConverterPlugin) Option {
+	return func(o *ConvertOptions) {
+		o.Plugins = append(o.Plugins, plugins...)
+	}
+}
+
+// WithTypeResolver sets the function used to resolve non built-in types
+func WithTypeResolver(resolver TypeResolverFunc) Option {
+	return func(o *ConvertOptions) {
+		o.TypeResolverFunc = resolver
+	}
+}
+
+// ConvertFile converts the XSD file at inputPath and writes the generated proto file
+func ConvertFile(inputPath string, opts ConvertOptions) error {
+	_, err := convertFile(inputPath, opts)
+	return err
+}
+
+// convertFile converts the XSD file at inputPath and returns the paths written
+func convertFile(inputPath string, opts ConvertOptions) ([]string, error) {
+	start := time.Now()
+	conv, err := newConverter(opts)
+	if err != nil {
+		return nil, err
+	}
+	closeTrace, err := openTrace(opts, conv)
+	if err != nil {
+		return nil, err
+	}
+	defer closeTrace()
+	saveFieldMap, err := openFieldMap(opts, conv)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Verbose {
+		opts.logf("Converting %s to protobuf...\n", inputPath)
+	}
+
+	p, closeParser, err := newParser(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeParser()
+	schema, err := parseSchema(p, inputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTypeGraph(schema, opts); err != nil {
+		return nil, err
+	}
+
+	// Convert to protobuf model
+	protoFile, err := conv.Convert(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert schema: %w", err)
+	}
+
+	written, err := writeProtoFile(protoFile, inputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeOpenAPIExtensions(conv, schema, protoFile, inputPath, opts); err != nil {
+		return nil, err
+	}
+	if err := saveFieldMap(); err != nil {
+		return nil, err
+	}
+	if err := printStats(schema, protoFile, opts); err != nil {
+		return nil, err
+	}
+	if err := finishReport(start, []string{inputPath}, written, []*model.Schema{schema}, protoFile, []*converter.Converter{conv}, opts); err != nil {
+		return nil, err
+	}
+	return written, nil
+}
+
+// ConvertReader converts an XSD document read from r
+// Imports and includes cannot be resolved without a file location, so they are
+// skipped with a warning. Either Output or OutputPath must be set.
+func ConvertReader(r io.Reader, opts ConvertOptions) error {
+	if opts.Output == nil && opts.OutputPath == "" && opts.OutputZipPath == "" {
+		return fmt.Errorf("an output path is required when reading the XSD from a stream")
+	}
+	if opts.EmitOpenAPI && opts.OutputPath == "" {
+		return fmt.Errorf("an output path is required for OpenAPI extensions when writing to a stream")
+	}
+	start := time.Now()
+	conv, err := newConverter(opts)
+	if err != nil {
+		return err
+	}
+	closeTrace, err := openTrace(opts, conv)
+	if err != nil {
+		return err
+	}
+	defer closeTrace()
+	saveFieldMap, err := openFieldMap(opts, conv)
+	if err != nil {
+		return err
+	}
+
+	p := parser.New()
+	schema, err := p.Parse(r)
+	if err != nil {
+		return fmt.Errorf("failed to parse XSD: %w", err)
+	}
+	if skipped := len(schema.Imports) + len(schema.Includes) + len(schema.Redefines); skipped > 0 {
+		opts.warnf("Warning: skipping %d import(s)/include(s) that cannot be resolved from a stream\n", skipped)
+	}
+	if err := checkSchema(p, schema, "the input stream", opts); err != nil {
+		return err
+	}
+	p.NormalizeSchema(schema)
+	if err := writeTypeGraph(schema, opts); err != nil {
+		return err
+	}
+
+	protoFile, err := conv.Convert(schema)
+	if err != nil {
+		return fmt.Errorf("failed to convert schema: %w", err)
+	}
+
+	written, err := writeProtoFile(protoFile, "", opts)
+	if err != nil {
+		return err
+	}
+	if err := writeOpenAPIExtensions(conv, schema, protoFile, "", opts); err != nil {
+		return err
+	}
+	if err := saveFieldMap(); err != nil {
+		return err
+	}
+	if err := printStats(schema, protoFile, opts); err != nil {
+		return err
+	}
+	return finishReport(start, nil, written, []*model.Schema{schema}, protoFile, []*converter.Converter{conv}, opts)
+}
+
+// ConvertFiles converts several XSD files into a single merged proto file
+// The package name is derived from the first file and the default output path
+// is that of the first file. Inputs with different target namespaces are converted
+// separately and combined with generator.MergeProtoFiles, which reports types
+// defined differently in two namespaces.
+func ConvertFiles(inputPaths []string, opts ConvertOptions) error {
+	if len(inputPaths) == 0 {
+		return fmt.Errorf("no input files given")
+	}
+	start := time.Now()
+
+	if opts.Verbose {
+		opts.logf("Merging %s into a single protobuf file...\n", strings.Join(inputPaths, ", "))
+	}
+
+	p, closeParser, err := newParser(opts)
+	if err != nil {
+		return err
+	}
+	defer closeParser()
+	var schemas []*model.Schema
+	for _, inputPath := range inputPaths {
+		schema, err := parseSchema(p, inputPath, opts)
+		if err != nil {
+			return fmt.Errorf("%s: %w", inputPath, err)
+		}
+		schemas = append(schemas, schema)
+	}
+	if err := writeTypeGraph(&model.Schema{ImportedSchemas: schemas}, opts); err != nil {
+		return err
+	}
+
+	groups := groupByNamespace(schemas)
+	if opts.EmitOpenAPI && len(groups) > 1 {
+		return fmt.Errorf("OpenAPI extensions cannot be generated for schemas with different target namespaces")
+	}
+	convs := make([]*converter.Converter, len(groups))
+	for i := range groups {
+		conv, err := newConverter(opts)
+		if err != nil {
+			return err
+		}
+		convs[i] = conv
+	}
+	closeTrace, err := openTrace(opts, convs...)
+	if err != nil {
+		return err
+	}
+	defer closeTrace()
+	saveFieldMap, err := openFieldMap(opts, convs...)
+	if err != nil {
+		return err
+	}
+
+	// Convert to protobuf model, one file per namespace
+	var protoFiles []*model.ProtoFile
+	for i, group := range groups {
+		protoFile, err := convs[i].ConvertMultiple(group)
+		if err != nil {
+			return fmt.Errorf("failed to convert schemas: %w", err)
+		}
+		protoFiles = append(protoFiles, protoFile)
+	}
+
+	protoFile, err := generator.MergeProtoFiles(protoFiles)
+	if err != nil {
+		return fmt.Errorf("failed to merge schemas: %w", err)
+	}
+
+	written, err := writeProtoFile(protoFile, inputPaths[0], opts)
+	if err != nil {
+		return err
+	}
+	if err := writeOpenAPIExtensions(convs[0], &model.Schema{ImportedSchemas: groups[0]}, protoFile, inputPaths[0], opts); err != nil {
+		return err
+	}
+	if err := saveFieldMap(); err != nil {
+		return err
+	}
+	if err := printStats(&model.Schema{ImportedSchemas: schemas}, protoFile, opts); err != nil {
+		return err
+	}
+	return finishReport(start, inputPaths, written, schemas, protoFile, convs, opts)
+}
+
+// groupByNamespace groups schemas by target namespace, in order of first appearance
+func groupByNamespace(schemas []*model.Schema) [][]*model.Schema {
+	var groups [][]*model.Schema
+	index := make(map[string]int)
+	for _, schema := range schemas {
+		i, ok := index[schema.TargetNamespace]
+		if !ok {
+			i = len(groups)
+			index[schema.TargetNamespace] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], schema)
+	}
+	return groups
+}
+
+// openTrace points the converters' trace output at opts.TracePath
+// The returned function closes the trace file and is safe to call when tracing is off
+func openTrace(opts ConvertOptions, convs ...*converter.Converter) (func(), error) {
+	if opts.TracePath == "" {
+		return func() {}, nil
+	}
+	file, err := os.Create(opts.TracePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace file: %w", err)
+	}
+	for _, conv := range convs {
+		conv.SetTraceWriter(file)
+	}
+	return func() { file.Close() }, nil
+}
+
+// writeTypeGraph writes the type dependency graph of the schema when requested
+func writeTypeGraph(schema *model.Schema, opts ConvertOptions) error {
+	// A dry run or diff writes no files, but the graph can still go to stdout
+	if opts.TypeGraphPath == "" || (!opts.writesFiles() && opts.TypeGraphPath != "-") {
+		return nil
+	}
+	content := converter.BuildTypeDependencyGraph(schema).DOT()
+	if opts.TypeGraphPath == "-" {
+		if _, err := io.WriteString(os.Stdout, content); err != nil {
+			return fmt.Errorf("failed to write type graph: %w", err)
+		}
+		return nil
+	}
+	if err := writeToFile(opts.TypeGraphPath, content); err != nil {
+		return fmt.Errorf("failed to write type graph: %w", err)
+	}
+	if opts.Verbose {
+		opts.logf("Successfully generated %s\n", opts.TypeGraphPath)
+	}
+	return nil
+}
+
+// newConverter creates a converter configured from the options
+func newConverter(opts ConvertOptions) (*converter.Converter, error) {
+	if opts.CamelCase && opts.PascalCase {
+		return nil, fmt.Errorf("cannot use both camelCase and PascalCase field names")
+	}
+	if opts.FieldNumberOffset < 0 || opts.FieldNumberOffset > model.MaxFieldNumber {
+		return nil, fmt.Errorf("field number offset must be between 1 and %d, got %d", model.MaxFieldNumber, opts.FieldNumberOffset)
+	}
+	if opts.SplitFiles && opts.Output != nil {
+		return nil, fmt.Errorf("cannot split files when writing to a stream")
+	}
+	if opts.SplitFiles && opts.DescriptorPath != "" {
+		return nil, fmt.Errorf("cannot write a descriptor when splitting files")
+	}
+	if opts.SplitFiles && opts.MergeWithPath != "" {
+		return nil, fmt.Errorf("cannot merge with an existing proto when splitting files")
+	}
+	if opts.OutputZipPath != "" {
+		if err := validateZipOutput(opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Diff {
+		if err := validateDiffMode(opts); err != nil {
+			return nil, err
+		}
+	}
+	if strings.Trim(opts.Indent, " \t") != "" {
+		return nil, fmt.Errorf("indent must consist of spaces and tabs, got %q", opts.Indent)
+	}
+	if opts.BufScaffold && opts.Output != nil {
+		return nil, fmt.Errorf("cannot write a buf scaffold when writing to a stream")
+	}
+	if err := validateOutputEncoding(opts.OutputEncoding); err != nil {
+		return nil, err
+	}
+	if _, err := importSortOrder(opts.ImportSortOrder); err != nil {
+		return nil, err
+	}
+
+	conv := converter.New()
+	conv.SetFieldNamingStyle(opts.CamelCase, opts.PascalCase)
+	conv.SetFieldNumberBase(max(opts.FieldNumberOffset, 1))
+	conv.SetAttributesFirst(opts.AttributesFirst)
+	conv.SetTypeResolver(opts.TypeResolverFunc)
+	conv.SetUseWrappers(!opts.NoWrappers)
+	conv.SetSmallIntegerComments(opts.SmallIntegerComments)
+	conv.SetExactIntegerTypes(opts.ExactIntegerTypes)
+	conv.SetTimestampTypes(opts.TimestampsForDate, opts.TimestampsForTime)
+	conv.SetCollectErrors(opts.CollectErrors)
+	conv.SetPackageName(opts.ProtoPackage)
+	conv.SetPackagePrefix(opts.PackagePrefix)
+	conv.SetAllowAlias(opts.AllowAlias)
+	conv.SetNoUnspecified(opts.NoUnspecified)
+	conv.SetMapEntryNames(opts.MapKeyName, opts.MapValueName)
+	conv.SetMixedAsBytes(opts.MixedAsBytes)
+	conv.SetKeepOriginalNames(opts.KeepOriginalNames)
+	conv.SetInlineOptionalGroups(opts.InlineOptionalGroups)
+	conv.SetUnboundedChoiceSuffix(opts.ChoiceWrapperSuffix)
+	conv.SetAbstractOptionImport(opts.AbstractOptionImport)
+	conv.SetWarningWriter(opts.warningWriter())
+	if opts.ReservedMapPath != "" {
+		reservedMap, err := LoadReservedMap(opts.ReservedMapPath)
+		if err != nil {
+			return nil, err
+		}
+		conv.SetReservedMap(reservedMap)
+	}
+	if opts.EnumValuePrefixStyle != "" {
+		if err := conv.SetEnumValuePrefixStyle(converter.EnumValuePrefixStyle(opts.EnumValuePrefixStyle)); err != nil {
+			return nil, err
+		}
+	}
+
+	return conv, nil
+}
+
+// newParser creates a parser configured from the options
+// The returned function removes the temporary cache of fetched schemas, if any
+func newParser(opts ConvertOptions) (*parser.Parser, func(), error) {
+	p := parser.New()
+	if opts.MaxParallelImports > 0 {
+		p.SetMaxParallelImports(opts.MaxParallelImports)
+	}
+	p.SetMaxRecursionDepth(opts.MaxRecursionDepth)
+	var resolvers parser.ResolverChain
+	if opts.CatalogPath != "" {
+		catalog, err := parser.NewCatalogResolver(opts.CatalogPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		resolvers = append(resolvers, catalog)
+	}
+	closeParser := func() {}
+	if opts.FetchRemote {
+		timeout := opts.FetchTimeout
+		if timeout <= 0 {
+			timeout = parser.DefaultFetchTimeout
+		}
+		remote, err := parser.NewHTTPResolver(timeout, opts.SchemaCacheDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		resolvers = append(resolvers, remote)
+		closeParser = func() { remote.Close() }
+	}
+	if len(resolvers) > 0 {
+		p.SetResolver(resolvers)
+	}
+	return p, closeParser, nil
+}
+
+// parseSchema parses and validates an XSD file together with its imports/includes
+func parseSchema(p *parser.Parser, inputPath string, opts ConvertOptions) (*model.Schema, error) {
+	schema, err := p.ParseFileWithImports(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse XSD file: %w", err)
+	}
+
+	if err := checkSchema(p, schema, inputPath, opts); err != nil {
+		return nil, err
+	}
+
+	return p.NormalizeSchema(schema), nil
+}
+
+// checkSchema validates a parsed schema against the parser rules and the options
+func checkSchema(p *parser.Parser, schema *model.Schema, source string, opts ConvertOptions) error {
+	// Validate parsed schema
+	warnings, err := p.Validate(schema)
+	if err != nil {
+		return fmt.Errorf("schema validation failed: %w", err)
+	}
+	for _, warning := range warnings {
+		opts.warnf("Warning: %s\n", warning)
+	}
+
+	if unresolved := p.ValidateCompleteness(schema); len(unresolved) > 0 {
+		if opts.Strict {
+			return fmt.Errorf("schema validation failed: %w", &xsderrors.MultiError{Errors: unresolved})
+		}
+		for _, err := range unresolved {
+			opts.warnf("Warning: %v\n", err)
+		}
+	}
+
+	if opts.RequireNamespace && schema.TargetNamespace == "" {
+		return fmt.Errorf("target namespace is missing: %s has no targetNamespace attribute", source)
+	}
+
+	if opts.Verbose {
+		opts.logf("Successfully parsed XSD schema with %d elements, %d complex types, %d simple types\n",
+			len(schema.Elements), len(schema.ComplexTypes), len(schema.SimpleTypes))
+	}
+
+	return nil
+}
+
+// warningWriter returns the writer receiving warnings: WarningOutput, or stderr
+// for verbose runs without one. Nil drops them.
+func (o ConvertOptions) warningWriter() io.Writer {
+	if o.WarningOutput != nil {
+		return o.WarningOutput
+	}
+	if o.Verbose {
+		return os.Stderr
+	}
+	return nil
+}
+
+// warnf prints a warning to the warning writer, if any
+func (o ConvertOptions) warnf(format string, args ...any) {
+	if w := o.warningWriter(); w != nil {
+		fmt.Fprintf(w, format, args...)
+	}
+}
+
+// logf prints a progress message, keeping stdout free when the proto or a diff
+// is printed there
+func (o ConvertOptions) logf(format string, args ...any) {
+	if o.Output != nil || o.Diff {
+		fmt.Fprintf(os.Stderr, format, args...)
+		return
+	}
+	fmt.Printf(format, args...)
+}
+
+// writeProtoFile applies the package options, generates the proto content and writes it
+func writeProtoFile(protoFile *model.ProtoFile, inputPath string, opts ConvertOptions) ([]string, error) {
+	// Add the language-specific file options that are set
+	fileOptions := []struct{ key, value string }{
+		{"go_package", opts.GoPackage},
+		{"java_package", opts.JavaPackage},
+		{"java_outer_classname", opts.JavaOuterClassname},
+		{"csharp_namespace", opts.CSharpNamespace},
+		{"objc_class_prefix", opts.ObjcClassPrefix},
+		{"php_namespace", opts.PhpNamespace},
+		{"ruby_package", opts.RubyPackage},
+	}
+	for _, option := range fileOptions {
+		if option.value != "" {
+			protoFile.SetOption(model.StringOption(option.key, option.value))
+		}
+	}
+
+	// Split files get the plugins applied one by one so each imports only what it uses
+	if !opts.SplitFiles {
+		if err := generator.ApplyPlugins(protoFile, opts.Plugins); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.MergeWithPath != "" {
+		merged, err := mergeWithProtoFile(protoFile, opts.MergeWithPath)
+		if err != nil {
+			return nil, err
+		}
+		protoFile = merged
+	}
+
+	gen := generator.New(
+		generator.WithHeader(opts.IncludeHeader, GetVersion()),
+		generator.WithSyntaxFirst(!opts.HeaderFirst),
+		generator.WithProto3Optional(opts.Proto3Optional),
+		generator.WithSourceInfo(opts.SourceInfo),
+	)
+	if opts.Indent != "" {
+		gen.SetIndent(opts.Indent)
+	}
+	importOrder, err := importSortOrder(opts.ImportSortOrder)
+	if err != nil {
+		return nil, err
+	}
+	gen.SetImportSortOrder(importOrder)
+	gen.SetImportGroupSeparator(opts.ImportGroupSeparator)
+	if opts.TemplatePath != "" {
+		tmpl, err := template.ParseFiles(opts.TemplatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load template: %w", err)
+		}
+		gen.SetTemplate(tmpl)
+	}
+
+	if opts.DryRun {
+		if _, err := gen.Generate(protoFile); err != nil {
+			return nil, fmt.Errorf("failed to generate protobuf: %w", err)
+		}
+		printDryRunStats(protoFile)
+		return nil, nil
+	}
+	if opts.Diff {
+		return nil, printProtoDiff(gen, protoFile, inputPath, opts)
+	}
+
+	var written []string
+	if opts.OutputZipPath != "" {
+		written, err = writeZipArchive(gen, protoFile, inputPath, opts)
+	} else if opts.SplitFiles {
+		written, err = writeSplitProtoFiles(gen, protoFile, inputPath, opts)
+	} else {
+		written, err = writeSingleProtoFile(gen, protoFile, inputPath, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.BufScaffold {
+		if err := writeBufScaffold(protoFile, written, opts); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeDescriptor(protoFile, written, inputPath, opts); err != nil {
+		return nil, err
+	}
+
+	if opts.DotOutputPath != "" {
+		if err := writeToFile(opts.DotOutputPath, generator.GenerateDOT(protoFile)); err != nil {
+			return nil, fmt.Errorf("failed to write DOT file: %w", err)
+		}
+		if opts.Verbose {
+			opts.logf("Successfully generated %s\n", opts.DotOutputPath)
+		}
+	}
+	if opts.MarkdownDocsPath != "" {
+		if err := writeToFile(opts.MarkdownDocsPath, generator.GenerateMarkdown(protoFile)); err != nil {
+			return nil, fmt.Errorf("failed to write Markdown docs: %w", err)
+		}
+		if opts.Verbose {
+			opts.logf("Successfully generated %s\n", opts.MarkdownDocsPath)
+		}
+	}
+
+	return written, nil
+}
+
+// mergeWithProtoFile merges the declarations of an existing proto file into the converted one
+func mergeWithProtoFile(protoFile *model.ProtoFile, path string) (*model.ProtoFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open proto to merge with: %w", err)
+	}
+	defer file.Close()
+
+	existing, err := merger.ParseProtoFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	merged, err := merger.MergeProtoFiles(protoFile, existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge with %s: %w", path, err)
+	}
+	return merged, nil
+}
+
+// printDryRunStats prints what a conversion would generate
+func printDryRunStats(protoFile *model.ProtoFile) {
+	fields := 0
+	for _, message := range protoFile.Messages {
+		fields += len(message.Fields)
+	}
+	fmt.Printf("Dry run: %d messages, %d enums, %d fields, %d imports would be generated\n",
+		len(protoFile.Messages), len(protoFile.Enums), fields, len(protoFile.Imports))
+}
+
+// printStats prints the statistics table when requested, to stderr when the
+// proto itself goes to a stream
+func printStats(schema *model.Schema, protoFile *model.ProtoFile, opts ConvertOptions) error {
+	if !opts.Stats {
+		return nil
+	}
+	var w io.Writer = os.Stdout
+	if opts.Output != nil || opts.Diff {
+		w = os.Stderr
+	}
+	if err := stats.ComputeStats(schema, protoFile).Write(w); err != nil {
+		return fmt.Errorf("failed to write statistics: %w", err)
+	}
+	return nil
+}
+
+// writeSingleProtoFile writes the whole proto to opts.Output or the output path
+// and returns the path written, if any
+func writeSingleProtoFile(gen *generator.Generator, protoFile *model.ProtoFile, inputPath string, opts ConvertOptions) ([]string, error) {
+	// Generate protobuf content
+	content, err := gen.Generate(protoFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate protobuf: %w", err)
+	}
+	content, err = encodeOutput(content, opts.OutputEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Output != nil {
+		if _, err := io.WriteString(opts.Output, content); err != nil {
+			return nil, fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil, nil
+	}
+
+	// Determine output path
+	finalOutputPath := opts.OutputPath
+	if finalOutputPath == "" {
+		finalOutputPath = DefaultOutputPath(inputPath)
+	}
+
+	// Write to output file
+	if err := writeToFile(finalOutputPath, content); err != nil {
+		return nil, fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	if opts.Verbose {
+		opts.logf("Successfully generated %s\n", finalOutputPath)
+	}
+	return []string{finalOutputPath}, nil
+}
+
+// writeSplitProtoFiles writes one proto file per message into the output directory
+// and returns the paths written
+func writeSplitProtoFiles(gen *generator.Generator, protoFile *model.ProtoFile, inputPath string, opts ConvertOptions) ([]string, error) {
+	outputDir := opts.OutputPath
+	if outputDir == "" {
+		outputDir = filepath.Dir(inputPath)
+	}
+
+	files := generator.SplitProtoFile(protoFile)
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	written := make([]string, 0, len(fileNames))
+
+	for _, fileName := range fileNames {
+		if err := generator.ApplyPlugins(files[fileName], opts.Plugins); err != nil {
+			return nil, err
+		}
+		content, err := gen.Generate(files[fileName])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s: %w", fileName, err)
+		}
+		content, err = encodeOutput(content, opts.OutputEncoding)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fileName, err)
+		}
+
+		outputPath := filepath.Join(outputDir, fileName)
+		if err := writeToFile(outputPath, content); err != nil {
+			return nil, fmt.Errorf("failed to write output file: %w", err)
+		}
+
+		if opts.Verbose {
+			opts.logf("Successfully generated %s\n", outputPath)
+		}
+		written = append(written, outputPath)
+	}
+
+	return written, nil
+}
+
+// writeBufScaffold writes buf.yaml and buf.gen.yaml into the directory of the
+// written proto files
+func writeBufScaffold(protoFile *model.ProtoFile, written []string, opts ConvertOptions) error {
+	if len(written) == 0 {
+		return nil
+	}
+	outputDir := filepath.Dir(written[0])
+	protoFiles := make([]string, 0, len(written))
+	for _, path := range written {
+		rel, err := filepath.Rel(outputDir, path)
+		if err != nil {
+			return fmt.Errorf("failed to locate %s in the buf module: %w", path, err)
+		}
+		protoFiles = append(protoFiles, rel)
+	}
+
+	if err := generator.GenerateBufScaffold(outputDir, protoFile.Package, opts.GoPackage, protoFiles...); err != nil {
+		return err
+	}
+	if opts.Verbose {
+		opts.logf("Successfully generated %s\n", filepath.Join(outputDir, "buf.yaml"))
+	}
+	return nil
+}
+
+// DefaultOutputPath returns the input path with its extension replaced by .proto
+func DefaultOutputPath(inputPath string) string {
+	dir := filepath.Dir(inputPath)
+	base := filepath.Base(inputPath)
+	ext := filepath.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	return filepath.Join(dir, name+".proto")
+}
+
+func writeToFile(path, content string) error {
+	// Create directory if it doesn't exist
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Write content to file
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write content: %w", err)
+	}
+
+	return nil
+}
+
+// TypeMappings returns the proto types the XSD built-in types are mapped to with
+// the options, keyed by XSD type name without prefix
+func TypeMappings(opts ConvertOptions) (map[string]string, error) {
+	conv, err := newConverter(opts)
+	if err != nil {
+		return nil, err
+	}
+	return conv.GetTypeMappings(), nil
+}
+
+// ParseBytes parses an XSD document held in memory
+// Imports and includes are not resolved since there is no base directory
+func ParseBytes(data []byte) (*model.Schema, error) {
+	return parser.New().ParseBytes(data)
+}
+
+// ParseString parses an XSD document given as a string
+// Imports and includes are not resolved since there is no base directory
+func ParseString(s string) (*model.Schema, error) {
+	return parser.New().ParseString(s)
+}
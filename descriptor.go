@@ -0,0 +1,53 @@
+package xsd2proto
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// writeDescriptor writes the binary FileDescriptorProto of the proto file when
+// requested. The descriptor is named after the proto file written, so that it
+// matches what protoc produces for it.
+func writeDescriptor(protoFile *model.ProtoFile, written []string, inputPath string, opts ConvertOptions) error {
+	if opts.DescriptorPath == "" {
+		return nil
+	}
+
+	descriptor, err := generator.GenerateDescriptor(protoFile, generator.WithProto3Optional(opts.Proto3Optional))
+	if err != nil {
+		return fmt.Errorf("failed to generate descriptor: %w", err)
+	}
+	descriptor.Name = proto.String(descriptorFileName(protoFile, written, inputPath))
+
+	data, err := proto.Marshal(descriptor)
+	if err != nil {
+		return fmt.Errorf("failed to encode descriptor: %w", err)
+	}
+	if err := writeToFile(opts.DescriptorPath, string(data)); err != nil {
+		return fmt.Errorf("failed to write descriptor: %w", err)
+	}
+	if opts.Verbose {
+		opts.logf("Successfully generated %s\n", opts.DescriptorPath)
+	}
+	return nil
+}
+
+// descriptorFileName returns the proto file name recorded in the descriptor
+func descriptorFileName(protoFile *model.ProtoFile, written []string, inputPath string) string {
+	switch {
+	case len(written) > 0:
+		return filepath.Base(written[0])
+	case inputPath != "":
+		return filepath.Base(DefaultOutputPath(inputPath))
+	case protoFile.Package != "":
+		return strings.ReplaceAll(protoFile.Package, ".", "_") + ".proto"
+	default:
+		return "generated.proto"
+	}
+}
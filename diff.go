@@ -0,0 +1,66 @@
+package xsd2proto
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/i-icc/xsd2proto/internal/diff"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// ErrProtoChanged is returned by a Diff run when the generated proto differs
+// from the existing output file
+var ErrProtoChanged = errors.New("generated proto differs from the existing output")
+
+// validateDiffMode rejects the options that cannot be combined with Diff
+func validateDiffMode(opts ConvertOptions) error {
+	switch {
+	case opts.Output != nil:
+		return fmt.Errorf("cannot diff against the output when writing to a stream")
+	case opts.SplitFiles:
+		return fmt.Errorf("cannot diff when splitting files")
+	case opts.OutputZipPath != "":
+		return fmt.Errorf("cannot diff when writing a zip archive")
+	case opts.DryRun:
+		return fmt.Errorf("cannot use both diff and dry run")
+	}
+	return nil
+}
+
+// writesFiles reports whether the run writes its outputs; dry runs and diffs
+// only report what they would write
+func (o ConvertOptions) writesFiles() bool {
+	return !o.DryRun && !o.Diff
+}
+
+// printProtoDiff generates the proto and prints a unified diff against the
+// existing output file to stdout, returning ErrProtoChanged when they differ.
+// A missing output file is diffed as empty.
+func printProtoDiff(gen *generator.Generator, protoFile *model.ProtoFile, inputPath string, opts ConvertOptions) error {
+	content, err := gen.Generate(protoFile)
+	if err != nil {
+		return fmt.Errorf("failed to generate protobuf: %w", err)
+	}
+	content, err = encodeOutput(content, opts.OutputEncoding)
+	if err != nil {
+		return err
+	}
+
+	outputPath := opts.OutputPath
+	if outputPath == "" {
+		outputPath = DefaultOutputPath(inputPath)
+	}
+	existing, err := os.ReadFile(outputPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read existing output: %w", err)
+	}
+
+	hunks, changed := diff.DiffProtoFiles(string(existing), content)
+	if !changed {
+		return nil
+	}
+	fmt.Printf("--- %s\n+++ %s\n%s", outputPath, outputPath, hunks)
+	return ErrProtoChanged
+}
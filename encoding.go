@@ -0,0 +1,48 @@
+package xsd2proto
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Output encodings accepted by ConvertOptions.OutputEncoding
+const (
+	OutputEncodingUTF8    = "utf-8"
+	OutputEncodingUTF8BOM = "utf-8-bom"
+	OutputEncodingASCII   = "ascii"
+)
+
+const utf8BOM = "\xEF\xBB\xBF"
+
+// validateOutputEncoding checks that the encoding is supported; empty means UTF-8
+func validateOutputEncoding(encoding string) error {
+	switch encoding {
+	case "", OutputEncodingUTF8, OutputEncodingUTF8BOM, OutputEncodingASCII:
+		return nil
+	}
+	return fmt.Errorf("unknown output encoding %q (expected %s, %s or %s)",
+		encoding, OutputEncodingUTF8, OutputEncodingUTF8BOM, OutputEncodingASCII)
+}
+
+// encodeOutput prepares generated content for writing in the given encoding
+// ASCII output fails on the first non-ASCII character, reporting its position
+func encodeOutput(content, encoding string) (string, error) {
+	switch encoding {
+	case OutputEncodingUTF8BOM:
+		return utf8BOM + content, nil
+	case OutputEncodingASCII:
+		line, column := 1, 1
+		for offset, r := range content {
+			if r >= utf8.RuneSelf {
+				return "", fmt.Errorf("output is not ASCII: %q at line %d, column %d (byte %d)", r, line, column, offset)
+			}
+			if r == '\n' {
+				line++
+				column = 1
+			} else {
+				column++
+			}
+		}
+	}
+	return content, nil
+}
@@ -0,0 +1,81 @@
+package xsd2proto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+// FieldMap records field numbers by message name and field name
+type FieldMap = converter.FieldMap
+
+// LoadFieldMap reads a field map from a JSON file
+// A missing file yields an empty map, so the first run creates it.
+func LoadFieldMap(path string) (FieldMap, error) {
+	fieldMap := make(FieldMap)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fieldMap, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read field map: %w", err)
+	}
+	if err := json.Unmarshal(data, &fieldMap); err != nil {
+		return nil, fmt.Errorf("failed to parse field map %s: %w", path, err)
+	}
+	return fieldMap, nil
+}
+
+// SaveFieldMap writes a field map to a JSON file
+func SaveFieldMap(path string, fieldMap FieldMap) error {
+	data, err := json.MarshalIndent(fieldMap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode field map: %w", err)
+	}
+	if err := writeToFile(path, string(data)+"\n"); err != nil {
+		return fmt.Errorf("failed to write field map: %w", err)
+	}
+	return nil
+}
+
+// ReservedMap records the removed fields of each message by message name
+type ReservedMap = converter.ReservedMap
+
+// LoadReservedMap reads the removed fields to reserve from a JSON file mapping
+// message names to lists of {"number": 3, "name": "old_field"} entries
+func LoadReservedMap(path string) (ReservedMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reserved map: %w", err)
+	}
+	var reservedMap ReservedMap
+	if err := json.Unmarshal(data, &reservedMap); err != nil {
+		return nil, fmt.Errorf("failed to parse reserved map %s: %w", path, err)
+	}
+	return reservedMap, nil
+}
+
+// openFieldMap loads the field map named in the options into the converters
+// The returned function writes the updated map back, unless this is a dry run.
+func openFieldMap(opts ConvertOptions, convs ...*converter.Converter) (func() error, error) {
+	if opts.FieldMapPath == "" {
+		return func() error { return nil }, nil
+	}
+	fieldMap, err := LoadFieldMap(opts.FieldMapPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, conv := range convs {
+		conv.SetFieldMap(fieldMap)
+	}
+	return func() error {
+		if !opts.writesFiles() {
+			return nil
+		}
+		return SaveFieldMap(opts.FieldMapPath, fieldMap)
+	}, nil
+}
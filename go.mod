@@ -1,3 +1,10 @@
 module github.com/i-icc/xsd2proto
 
 go 1.21
+
+require (
+	github.com/fsnotify/fsnotify v1.7.0
+	google.golang.org/protobuf v1.33.0
+)
+
+require golang.org/x/sys v0.4.0 // indirect
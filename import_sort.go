@@ -0,0 +1,25 @@
+package xsd2proto
+
+import (
+	"fmt"
+
+	"github.com/i-icc/xsd2proto/internal/generator"
+)
+
+// Import orders accepted by ConvertOptions.ImportSortOrder
+const (
+	ImportSortAlpha = "alpha"
+	ImportSortDeps  = "deps"
+)
+
+// importSortOrder maps the import order of the options to the generator's;
+// empty means dependency first
+func importSortOrder(order string) (generator.ImportSortOrder, error) {
+	switch order {
+	case "", ImportSortDeps:
+		return generator.ImportSortDependencyFirst, nil
+	case ImportSortAlpha:
+		return generator.ImportSortAlphabetical, nil
+	}
+	return 0, fmt.Errorf("unknown import sort order %q (expected %s or %s)", order, ImportSortAlpha, ImportSortDeps)
+}
@@ -0,0 +1,261 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+var builtInTypeCases = []struct {
+	xsdType   string
+	protoType string
+	imp       string
+	repeated  bool
+}{
+	{"string", "string", "", false},
+	{"normalizedString", "string", "", false},
+	{"token", "string", "", false},
+	{"NMTOKEN", "string", "", false},
+	{"Name", "string", "", false},
+	{"NCName", "string", "", false},
+	{"ID", "string", "", false},
+	{"IDREF", "string", "", false},
+	{"QName", "string", "", false},
+	{"IDREFS", "string", "", true},
+	{"NMTOKENS", "string", "", true},
+	{"language", "string", "", false},
+	{"ENTITIES", "string", "", true},
+	{"anyURI", "string", "", false},
+	{"boolean", "bool", "", false},
+	{"int", "int32", "", false},
+	{"integer", "int32", "", false},
+	{"short", "int32", "", false},
+	{"byte", "int32", "", false},
+	{"unsignedByte", "int32", "", false},
+	{"long", "int64", "", false},
+	{"unsignedInt", "int64", "", false},
+	{"positiveInteger", "uint32", "", false},
+	{"nonNegativeInteger", "uint32", "", false},
+	{"negativeInteger", "int32", "", false},
+	{"nonPositiveInteger", "int32", "", false},
+	{"unsignedLong", "uint64", "", false},
+	{"unsignedShort", "uint32", "", false},
+	{"float", "float", "", false},
+	{"double", "double", "", false},
+	{"decimal", "double", "", false},
+	{"dateTime", "google.protobuf.Timestamp", "google/protobuf/timestamp.proto", false},
+	{"date", "string", "", false},
+	{"time", "string", "", false},
+	{"duration", "google.protobuf.Duration", "google/protobuf/duration.proto", false},
+	{"base64Binary", "bytes", "", false},
+	{"hexBinary", "bytes", "", false},
+	{"gYear", "string", "", false},
+	{"gMonth", "string", "", false},
+	{"gDay", "string", "", false},
+	{"gYearMonth", "string", "", false},
+	{"gMonthDay", "string", "", false},
+}
+
+// TestBuiltInTypesConversion converts a schema with one element of each built-in type
+func TestBuiltInTypesConversion(t *testing.T) {
+	var elements strings.Builder
+	for i, tc := range builtInTypeCases {
+		elements.WriteString(fmt.Sprintf("            <xs:element name=\"field%d\" type=\"xs:%s\"/>\n", i, tc.xsdType))
+	}
+
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/builtin">
+    <xs:complexType name="AllTypes">
+        <xs:sequence>
+` + elements.String() + `        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	schema, err := parser.New().Parse(strings.NewReader(xsdContent))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	protoFile, err := New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	if len(protoFile.Messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(protoFile.Messages))
+	}
+
+	fields := protoFile.Messages[0].Fields
+	if len(fields) != len(builtInTypeCases) {
+		t.Fatalf("Expected %d fields, got %d", len(builtInTypeCases), len(fields))
+	}
+
+	expectedImports := make(map[string]bool)
+	for i, tc := range builtInTypeCases {
+		if fields[i].Type != tc.protoType {
+			t.Errorf("xs:%s should map to %s, got %s", tc.xsdType, tc.protoType, fields[i].Type)
+		}
+		expectedLabel := model.FieldLabelRequired
+		if tc.repeated {
+			expectedLabel = model.FieldLabelRepeated
+		}
+		if fields[i].Label != expectedLabel {
+			t.Errorf("xs:%s field should be %s, got %s", tc.xsdType, expectedLabel, fields[i].Label)
+		}
+		if tc.imp != "" {
+			expectedImports[tc.imp] = true
+		}
+	}
+
+	if len(protoFile.Imports) != len(expectedImports) {
+		t.Errorf("Expected imports %v, got %v", expectedImports, protoFile.Imports)
+	}
+	for _, imp := range protoFile.Imports {
+		if !expectedImports[imp] {
+			t.Errorf("Unexpected import %s", imp)
+		}
+	}
+}
+
+// TestBuiltInTypesAreRecognized tests that every mapped type is reported as built-in
+func TestBuiltInTypesAreRecognized(t *testing.T) {
+	tm := NewTypeMapper()
+	for _, tc := range builtInTypeCases {
+		if !tm.IsBuiltInType("xs:" + tc.xsdType) {
+			t.Errorf("xs:%s should be a built-in type", tc.xsdType)
+		}
+	}
+}
+
+// TestDatePartialTypesComment tests that partial date and date fields are marked with a comment
+func TestDatePartialTypesComment(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Card">
+        <xs:sequence>
+            <xs:element name="expiryYear" type="xs:gYear"/>
+            <xs:element name="expiry" type="xs:gYearMonth" default="2030-01"/>
+            <xs:element name="issued" type="xs:date"/>
+        </xs:sequence>
+        <xs:attribute name="birthday" type="xs:gMonthDay"/>
+    </xs:complexType>
+</xs:schema>`
+
+	schema, err := parser.New().Parse(strings.NewReader(xsdContent))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+
+	expected := map[string]string{
+		"expiry_year": "XSD date partial",
+		"expiry":      "XSD date partial; default: 2030-01",
+		"issued":      "XSD date",
+		"birthday":    "XSD date partial",
+	}
+	for _, field := range protoFile.Messages[0].Fields {
+		if field.Comment != expected[field.Name] {
+			t.Errorf("Field %s: expected comment %q, got %q", field.Name, expected[field.Name], field.Comment)
+		}
+	}
+}
+
+// TestGetBuiltInMappings tests that the built-in mappings list every built-in type
+// with the proto type it is mapped to
+func TestGetBuiltInMappings(t *testing.T) {
+	tm := NewTypeMapper()
+	mappings := tm.GetBuiltInMappings()
+	if len(mappings) != len(builtInTypeCases) {
+		t.Errorf("Expected %d built-in mappings, got %d", len(builtInTypeCases), len(mappings))
+	}
+	for _, tc := range builtInTypeCases {
+		if got, exists := mappings[tc.xsdType]; !exists || got != tc.protoType {
+			t.Errorf("Built-in mapping of %s = %q, expected %q", tc.xsdType, got, tc.protoType)
+		}
+	}
+
+	mappings["string"] = "bytes"
+	if protoType, _ := tm.MapXSDType("xs:string"); protoType != "string" {
+		t.Errorf("Changing the returned map should not affect the mapper, got %s", protoType)
+	}
+}
+
+// TestGetAllMappings tests that the exact integer and timestamp types and custom
+// mappings override the built-in ones
+func TestGetAllMappings(t *testing.T) {
+	tm := NewTypeMapper()
+	tm.exactIntegers = true
+	tm.timestampDate = true
+	tm.AddCustomMapping("decimal", "string")
+	tm.AddCustomMapping("Money", "example.Money")
+
+	if custom := tm.GetCustomMappings(); len(custom) != 2 || custom["Money"] != "example.Money" {
+		t.Errorf("Unexpected custom mappings: %v", custom)
+	}
+
+	mappings := tm.GetAllMappings()
+	if len(mappings) != len(builtInTypeCases)+1 {
+		t.Errorf("Expected %d mappings, got %d", len(builtInTypeCases)+1, len(mappings))
+	}
+	for xsdType, protoType := range mappings {
+		if mapped, _ := tm.MapXSDType(xsdType); mapped != protoType {
+			t.Errorf("GetAllMappings()[%s] = %s, but MapXSDType returns %s", xsdType, protoType, mapped)
+		}
+	}
+	for xsdType, expected := range map[string]string{"byte": "sint32", "decimal": "string", "Money": "example.Money", "int": "int32", "date": "google.protobuf.Timestamp", "time": "string"} {
+		if mappings[xsdType] != expected {
+			t.Errorf("Mapping of %s = %q, expected %q", xsdType, mappings[xsdType], expected)
+		}
+	}
+}
+
+// TestTimestampTypes tests that xs:date and xs:time map to Timestamp only when
+// enabled, each on its own, and then lose their comment
+func TestTimestampTypes(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Event">
+        <xs:sequence>
+            <xs:element name="day" type="xs:date"/>
+            <xs:element name="start" type="xs:time"/>
+            <xs:element name="created" type="xs:dateTime"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	tests := []struct {
+		name       string
+		date, time bool
+		expected   map[string]string
+	}{
+		{"strings", false, false, map[string]string{"day": "string:XSD date", "start": "string:XSD time"}},
+		{"date", true, false, map[string]string{"day": "google.protobuf.Timestamp:", "start": "string:XSD time"}},
+		{"time", false, true, map[string]string{"day": "string:XSD date", "start": "google.protobuf.Timestamp:"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := parser.New().Parse(strings.NewReader(xsdContent))
+			if err != nil {
+				t.Fatalf("Failed to parse XSD: %v", err)
+			}
+			conv := New()
+			conv.SetTimestampTypes(tt.date, tt.time)
+			protoFile, err := conv.Convert(schema)
+			if err != nil {
+				t.Fatalf("Failed to convert schema: %v", err)
+			}
+			tt.expected["created"] = "google.protobuf.Timestamp:"
+			for _, field := range protoFile.Messages[0].Fields {
+				if got := field.Type + ":" + field.Comment; got != tt.expected[field.Name] {
+					t.Errorf("Field %s: expected %q, got %q", field.Name, tt.expected[field.Name], got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package converter
+
+import (
+	"strconv"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// facetConstraints returns the length and pattern facets of the simple type
+// referenced by typeName, in the order minLength, maxLength, pattern
+func (c *Converter) facetConstraints(typeName string) []model.FieldConstraint {
+	if c.currentSchema == nil || c.typeMapper.IsBuiltInType(typeName) {
+		return nil
+	}
+
+	simpleType := c.findSimpleTypeInSchema(typeName, c.currentSchema)
+	if simpleType == nil || simpleType.Restriction == nil {
+		return nil
+	}
+
+	restriction := simpleType.Restriction
+	var constraints []model.FieldConstraint
+	if restriction.MinLength != nil {
+		constraints = append(constraints, model.FieldConstraint{Facet: "minLength", Value: strconv.Itoa(restriction.MinLength.Value)})
+	}
+	if restriction.MaxLength != nil {
+		constraints = append(constraints, model.FieldConstraint{Facet: "maxLength", Value: strconv.Itoa(restriction.MaxLength.Value)})
+	}
+	if restriction.Pattern != nil {
+		constraints = append(constraints, model.FieldConstraint{Facet: "pattern", Value: restriction.Pattern.Value})
+	}
+	return constraints
+}
@@ -1,9 +1,15 @@
 package converter
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
+	"sort"
 	"strings"
+	"unicode"
 
+	xsderrors "github.com/i-icc/xsd2proto/internal/errors"
 	"github.com/i-icc/xsd2proto/internal/model"
 )
 
@@ -11,29 +17,206 @@ import (
 type Converter struct {
 	typeMapper        *TypeMapper
 	fieldCounter      int
+	fieldNumberBase   int            // Starting field number in each message
+	oneofCounter      int            // Number of oneof groups in the current message
+	usedFieldNumbers  map[int]string // Field numbers taken in the current message, by field name
 	usedEnumValues    map[string]bool
 	enumValueCounters map[string]int
 	usedMessageNames  map[string]bool   // Track used message names
 	usedEnumNames     map[string]bool   // Track used enum names
 	typeRenameMap     map[string]string // Map from original type name to renamed type name
+	collisionRenames  map[string]string // Types given a numbered name because theirs was taken, by original name
 	useCamelCase      bool              // Use camelCase for field names instead of snake_case
 	usePascalCase     bool              // Use PascalCase for field names instead of snake_case
+	enumValuePrefixer enumValuePrefixer // Strategy for prefixing enum value names
+	attributesFirst   bool              // Emit attribute fields before sequence fields
+	useWrappers       bool              // Map nillable scalar elements to wrapper types
+	allowAlias        bool              // Give enumeration values differing only in case one number
+	noUnspecified     bool              // Number enumeration values from 0 without an UNSPECIFIED value
+	smallIntComments  bool              // Comment fields of small integer types with the XSD type
+	mixedAsBytes      bool              // Emit the text of mixed content types as bytes instead of string
+	keepNames         bool              // Use XSD type names verbatim as message and enum names
 	currentSchema     *model.Schema     // Reference to current schema for ArrayOf optimization
+	tracer            *json.Encoder     // Receives conversion decisions as JSON Lines when set
+	typeResolver      TypeResolverFunc  // Resolves non built-in types from external registries
+	resolvedImports   map[string]bool   // Import paths returned by the type resolver
+	sourceSchema      *model.Schema     // Schema whose declarations are being converted
+	fieldMap          FieldMap          // Persisted field numbers, updated with new allocations
+	reservedMap       ReservedMap       // Removed fields to emit as reserved, by message name
+	currentMessage    string            // Name of the message whose fields are being numbered
+	packageName       string            // Proto package overriding the one derived from the namespace
+	packagePrefix     string            // Prepended to the packages derived from the namespace
+	mapKeyName        string            // Element name of the key of key-value entry types
+	mapValueName      string            // Element name of the value of key-value entry types
+
+	reservedWordWarnings map[string]bool // Reserved words already reported as renamed
+
+	substitutionGroups     map[string]*substitutionGroup // Substitution groups by head element name
+	substitutionGroupOrder []string                      // Head element names in document order
+
+	usedFieldNames        map[string]bool             // Field names taken in the current message
+	choiceSequenceCounter int                         // Number of sequences inside choices in the current message
+	choiceMessages        []model.ProtoMessage        // Nested messages of those sequences
+	unboundedChoiceSuffix string                      // Suffix of the wrapper message names of repeated choices
+	choiceWrappers        map[choiceWrapperKey]string // Wrapper message names of repeated choices, by parent message and choice
+	wrapperMessages       []model.ProtoMessage        // Wrapper messages not yet added to the proto file
+	optionalGroups        map[string]string           // Group message names of optional sequences, by parent message name
+	inlineOptionalGroups  bool                        // Keep the elements of optional sequences as optional fields of the parent
+
+	convertingTypes    map[string]bool               // Complex types whose conversion is in progress
+	extendingTypes     map[*model.ComplexType]bool   // Base types whose fields are being inherited
+	registeredMessages map[*model.ComplexType]string // Message names given to complex types before conversion
+
+	messageOptions       map[string][]model.ProtoOption // Options added with AddMessageOption, by message name
+	abstractOptionImport string                         // Proto file defining the xsd_abstract message option
+
+	collectErrors bool    // Keep converting the remaining types after a type fails
+	errorList     []error // Per-type errors collected by the last conversion
+
+	warnings      WarningCollector // Dropped XSD features of the last conversion
+	warningWriter io.Writer        // Receives the warnings at the end of each conversion when set
 }
 
+// mixedContentComment marks the field holding the text of a mixed content type
+const mixedContentComment = "mixed content"
+
+// abstractTypeComment marks messages of abstract XSD types, which proto3 cannot express
+const abstractTypeComment = "abstract type — do not instantiate directly"
+
 // New creates a new converter instance
 func New() *Converter {
 	return &Converter{
 		typeMapper:        NewTypeMapper(),
 		fieldCounter:      1,
+		fieldNumberBase:   1,
+		usedFieldNumbers:  make(map[int]string),
+		usedFieldNames:    make(map[string]bool),
 		usedEnumValues:    make(map[string]bool),
 		enumValueCounters: make(map[string]int),
 		usedMessageNames:  make(map[string]bool),
 		usedEnumNames:     make(map[string]bool),
 		typeRenameMap:     make(map[string]string),
+		collisionRenames:  make(map[string]string),
+		resolvedImports:   make(map[string]bool),
 		useCamelCase:      false,
 		usePascalCase:     false,
+		enumValuePrefixer: enumNamePrefix,
+		useWrappers:       true,
+		mapKeyName:        DefaultMapKeyName,
+		mapValueName:      DefaultMapValueName,
+
+		unboundedChoiceSuffix: DefaultUnboundedChoiceSuffix,
+		choiceWrappers:        make(map[choiceWrapperKey]string),
+		optionalGroups:        make(map[string]string),
+
+		reservedWordWarnings: make(map[string]bool),
+		substitutionGroups:   make(map[string]*substitutionGroup),
+		convertingTypes:      make(map[string]bool),
+		extendingTypes:       make(map[*model.ComplexType]bool),
+		registeredMessages:   make(map[*model.ComplexType]string),
+		messageOptions:       make(map[string][]model.ProtoOption),
+	}
+}
+
+// Reset clears the naming state collected by previous conversions so the converter
+// can be reused for another schema. Configuration such as the naming style, field
+// number base and type mappings is kept.
+func (c *Converter) Reset() {
+	clear(c.usedEnumValues)
+	clear(c.enumValueCounters)
+	clear(c.usedMessageNames)
+	clear(c.usedEnumNames)
+	clear(c.typeRenameMap)
+	clear(c.collisionRenames)
+	clear(c.resolvedImports)
+	clear(c.reservedWordWarnings)
+	clear(c.substitutionGroups)
+	c.substitutionGroupOrder = nil
+	clear(c.convertingTypes)
+	clear(c.extendingTypes)
+	clear(c.registeredMessages)
+	clear(c.choiceWrappers)
+	clear(c.optionalGroups)
+	c.wrapperMessages = nil
+	c.fieldCounter = c.fieldNumberBase
+	c.oneofCounter = 0
+	clear(c.usedFieldNumbers)
+	clear(c.usedFieldNames)
+	c.currentSchema = nil
+	c.sourceSchema = nil
+}
+
+// Clone returns a converter with the configuration of c and none of its naming
+// state, as after Reset. A Converter is not safe for concurrent use; converting
+// several schemas in parallel should give each goroutine a clone of one
+// configured converter. The clone gets its own copies of the type mappings and
+// field map, while the reserved map and the type resolver are shared and so must
+// be safe to call concurrently. Tracing and the warning writer are not carried
+// over, since writers cannot be shared.
+func (c *Converter) Clone() *Converter {
+	clone := New()
+	clone.typeMapper = c.typeMapper.Clone()
+	clone.fieldNumberBase = c.fieldNumberBase
+	clone.fieldCounter = c.fieldNumberBase
+	clone.useCamelCase = c.useCamelCase
+	clone.usePascalCase = c.usePascalCase
+	clone.enumValuePrefixer = c.enumValuePrefixer
+	clone.attributesFirst = c.attributesFirst
+	clone.useWrappers = c.useWrappers
+	clone.allowAlias = c.allowAlias
+	clone.noUnspecified = c.noUnspecified
+	clone.smallIntComments = c.smallIntComments
+	clone.mixedAsBytes = c.mixedAsBytes
+	clone.keepNames = c.keepNames
+	clone.inlineOptionalGroups = c.inlineOptionalGroups
+	clone.typeResolver = c.typeResolver
+	clone.reservedMap = c.reservedMap
+	clone.packageName = c.packageName
+	clone.packagePrefix = c.packagePrefix
+	clone.mapKeyName = c.mapKeyName
+	clone.mapValueName = c.mapValueName
+	clone.unboundedChoiceSuffix = c.unboundedChoiceSuffix
+	clone.collectErrors = c.collectErrors
+	clone.abstractOptionImport = c.abstractOptionImport
+	clone.messageOptions = cloneMessageOptions(c.messageOptions)
+	if c.fieldMap != nil {
+		clone.fieldMap = make(FieldMap, len(c.fieldMap))
+		for message, fields := range c.fieldMap {
+			clone.fieldMap[message] = maps.Clone(fields)
+		}
+	}
+	return clone
+}
+
+// GetTypeRenameMap returns a copy of the mapping from XSD type names to the proto
+// names they were emitted as by the conversions so far
+func (c *Converter) GetTypeRenameMap() map[string]string {
+	return maps.Clone(c.typeRenameMap)
+}
+
+// GetCollisionRenames returns the XSD types whose proto name was already taken,
+// mapped to the numbered name they were emitted as instead
+func (c *Converter) GetCollisionRenames() map[string]string {
+	return maps.Clone(c.collisionRenames)
+}
+
+// GetUsedMessageNames returns the message names emitted so far, sorted
+func (c *Converter) GetUsedMessageNames() []string {
+	return sortedKeys(c.usedMessageNames)
+}
+
+// GetUsedEnumNames returns the enum names emitted so far, sorted
+func (c *Converter) GetUsedEnumNames() []string {
+	return sortedKeys(c.usedEnumNames)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	return keys
 }
 
 // SetFieldNamingStyle sets the field naming style
@@ -42,35 +225,295 @@ func (c *Converter) SetFieldNamingStyle(useCamelCase, usePascalCase bool) {
 	c.usePascalCase = usePascalCase
 }
 
+// SetFieldNumberBase sets the starting field number in each message
+func (c *Converter) SetFieldNumberBase(base int) {
+	c.fieldNumberBase = base
+}
+
+// SetAttributesFirst sets whether attribute fields come before sequence fields
+func (c *Converter) SetAttributesFirst(attributesFirst bool) {
+	c.attributesFirst = attributesFirst
+}
+
+// SetUseWrappers sets whether nillable scalar elements use google.protobuf wrapper types
+func (c *Converter) SetUseWrappers(useWrappers bool) {
+	c.useWrappers = useWrappers
+}
+
+// SetAllowAlias sets whether enumeration values differing only in case become
+// aliases sharing one enum number instead of getting numbers of their own
+func (c *Converter) SetAllowAlias(allowAlias bool) {
+	c.allowAlias = allowAlias
+}
+
+// SetNoUnspecified sets whether enums omit the UNSPECIFIED value, numbering the
+// enumeration values from 0 to match existing proto definitions
+func (c *Converter) SetNoUnspecified(noUnspecified bool) {
+	c.noUnspecified = noUnspecified
+}
+
+// SetKeepOriginalNames sets whether message and enum names are the XSD type names
+// as written, without the namespace prefix, instead of their PascalCase form.
+// Field names still follow the naming style.
+func (c *Converter) SetKeepOriginalNames(keepNames bool) {
+	c.keepNames = keepNames
+}
+
+// SetMixedAsBytes sets whether the content field of mixed complex types is bytes rather than string
+func (c *Converter) SetMixedAsBytes(mixedAsBytes bool) {
+	c.mixedAsBytes = mixedAsBytes
+}
+
+// SetCollectErrors sets whether a failing type stops the conversion. When set,
+// per-type errors are collected and Convert returns them together in a
+// *errors.MultiError along with the partial proto file.
+func (c *Converter) SetCollectErrors(collectErrors bool) {
+	c.collectErrors = collectErrors
+}
+
+// ErrorList returns the per-type errors collected by the last conversion
+func (c *Converter) ErrorList() []error {
+	return append([]error(nil), c.errorList...)
+}
+
+// collectTypeError records a per-type conversion error when collecting errors,
+// and otherwise returns it so that the conversion stops
+func (c *Converter) collectTypeError(err error) error {
+	if !c.collectErrors {
+		return err
+	}
+	c.errorList = append(c.errorList, err)
+	return nil
+}
+
+// SetSmallIntegerComments sets whether fields of the small integer types (byte,
+// unsignedByte, short, unsignedShort) get a comment naming the original XSD type
+func (c *Converter) SetSmallIntegerComments(smallIntComments bool) {
+	c.smallIntComments = smallIntComments
+}
+
+// SetExactIntegerTypes sets whether xs:byte and xs:short map to sint32 and
+// xs:unsignedByte to uint32 instead of int32
+func (c *Converter) SetExactIntegerTypes(exactIntegers bool) {
+	c.typeMapper.exactIntegers = exactIntegers
+}
+
+// SetTimestampTypes sets whether xs:date and xs:time map to google.protobuf.Timestamp
+// instead of string. xs:dateTime is always a Timestamp.
+func (c *Converter) SetTimestampTypes(date, time bool) {
+	c.typeMapper.timestampDate = date
+	c.typeMapper.timestampTime = time
+}
+
+// GetTypeMappings returns the proto types the XSD types are mapped to, built-in
+// and custom, as configured for this converter
+func (c *Converter) GetTypeMappings() map[string]string {
+	return c.typeMapper.GetAllMappings()
+}
+
+// SetPackageName sets the proto package of the converted files, overriding the
+// package derived from the target namespace. An empty name restores the derivation.
+func (c *Converter) SetPackageName(packageName string) {
+	c.packageName = packageName
+}
+
+// SetPackagePrefix sets a package, such as an organization name, prepended to the
+// packages derived from the target namespace. A package set with SetPackageName
+// is used as it is.
+func (c *Converter) SetPackagePrefix(prefix string) {
+	c.packagePrefix = strings.Trim(prefix, ".")
+}
+
 // Convert converts an XSD schema to a Protobuf file model
 func (c *Converter) Convert(schema *model.Schema) (*model.ProtoFile, error) {
 	// Store schema reference for ArrayOf optimization
 	c.currentSchema = schema
+	c.errorList = nil
 
 	protoFile := &model.ProtoFile{
 		Syntax:  "proto3",
-		Package: c.generatePackageName(schema.TargetNamespace),
-		Options: make(map[string]string),
+		Package: c.protoPackageName(schema.TargetNamespace),
 	}
 
+	c.collectSubstitutionGroups([]*model.Schema{schema})
+	c.collectDroppedFeatures([]*model.Schema{schema})
+	defer c.printWarnings()
+
 	// Convert schema and all imported schemas recursively
-	c.convertSchemaRecursive(schema, protoFile)
+	if err := c.convertSchemaRecursive(schema, protoFile); err != nil {
+		return nil, err
+	}
+
+	groupMessages, err := c.convertSubstitutionGroups()
+	if err != nil {
+		return nil, err
+	}
+	protoFile.Messages = append(protoFile.Messages, groupMessages...)
+	c.applyMessageOptions(protoFile.Messages, "")
 
+	protoFile.Imports = c.requiredImports(protoFile)
+
+	if len(c.errorList) > 0 {
+		return protoFile, &xsderrors.MultiError{Errors: c.ErrorList()}
+	}
+	return protoFile, nil
+}
+
+// ConvertMultiple converts several XSD schemas into a single Protobuf file model
+// All schemas go through the same three passes (simple types, complex types, elements)
+// together, types are deduplicated by their namespace-qualified name, and the package
+// name is derived from the first schema's target namespace
+func (c *Converter) ConvertMultiple(schemas []*model.Schema) (*model.ProtoFile, error) {
+	if len(schemas) == 0 {
+		return nil, fmt.Errorf("no schemas to convert")
+	}
+
+	// Type lookups search every schema and their imports
+	c.currentSchema = &model.Schema{ImportedSchemas: schemas}
+	c.errorList = nil
+
+	protoFile := &model.ProtoFile{
+		Syntax:  "proto3",
+		Package: c.protoPackageName(schemas[0].TargetNamespace),
+	}
+
+	allSchemas := c.collectSchemas(schemas, make(map[*model.Schema]bool))
+	converted := make(map[string]bool)
+	c.collectSubstitutionGroups(schemas)
+	c.collectDroppedFeatures(schemas)
+	defer c.printWarnings()
+
+	// First pass: convert all simple types (enums)
+	for _, schema := range allSchemas {
+		c.sourceSchema = schema
+		for _, simpleType := range schema.SimpleTypes {
+			if simpleType.Restriction == nil || len(simpleType.Restriction.Enumerations) == 0 || c.isStringBasedEnumeration(&simpleType) {
+				continue
+			}
+			key := qualifiedName(schema.TargetNamespace, "simpleType:"+simpleType.Name)
+			if converted[key] {
+				continue
+			}
+			converted[key] = true
+			protoFile.Enums = append(protoFile.Enums, *c.convertSimpleTypeToEnum(&simpleType))
+		}
+	}
+
+	// Second pass: convert all complex types (messages), with their names
+	// registered first so that types may refer to types declared after them
+	c.registerMessageNames(allSchemas)
+	for _, schema := range allSchemas {
+		c.sourceSchema = schema
+		for i := range schema.ComplexTypes {
+			complexType := &schema.ComplexTypes[i]
+			// Skip ArrayOf pattern types - they will be converted to direct repeated fields
+			// Key-value entry types are converted to map fields likewise
+			if c.isArrayOfPattern(complexType) || c.isMapEntryPattern(complexType) {
+				continue
+			}
+			key := qualifiedName(schema.TargetNamespace, "complexType:"+complexType.Name)
+			if converted[key] {
+				continue
+			}
+			converted[key] = true
+			message, err := c.convertComplexType(complexType)
+			if err != nil {
+				c.takeWrapperMessages()
+				if err := c.collectTypeError(&xsderrors.ConvertError{TypeName: complexType.Name, Msg: "failed to convert complex type", Err: err}); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			protoFile.Messages = append(protoFile.Messages, *message)
+			protoFile.Messages = append(protoFile.Messages, c.takeWrapperMessages()...)
+		}
+	}
+
+	// Third pass: convert all elements with inline complex types
+	for _, schema := range allSchemas {
+		c.sourceSchema = schema
+		for _, element := range schema.Elements {
+			if !element.HasInlineType() {
+				continue
+			}
+			key := qualifiedName(schema.TargetNamespace, "element:"+element.Name)
+			if converted[key] {
+				continue
+			}
+			converted[key] = true
+			message, err := c.convertElementToMessage(&element)
+			if err != nil {
+				c.takeWrapperMessages()
+				if err := c.collectTypeError(&xsderrors.ConvertError{TypeName: element.Name, Msg: "failed to convert element", Err: err}); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			protoFile.Messages = append(protoFile.Messages, *message)
+			protoFile.Messages = append(protoFile.Messages, c.takeWrapperMessages()...)
+		}
+	}
+
+	groupMessages, err := c.convertSubstitutionGroups()
+	if err != nil {
+		return nil, err
+	}
+	protoFile.Messages = append(protoFile.Messages, groupMessages...)
+	c.applyMessageOptions(protoFile.Messages, "")
+
+	protoFile.Imports = c.requiredImports(protoFile)
+
+	if len(c.errorList) > 0 {
+		return protoFile, &xsderrors.MultiError{Errors: c.ErrorList()}
+	}
+	return protoFile, nil
+}
+
+// collectSchemas flattens schemas and their imports, parents first, visiting each schema once
+func (c *Converter) collectSchemas(schemas []*model.Schema, visited map[*model.Schema]bool) []*model.Schema {
+	var result []*model.Schema
+	for _, schema := range schemas {
+		if schema == nil || visited[schema] {
+			continue
+		}
+		visited[schema] = true
+		result = append(result, schema)
+		result = append(result, c.collectSchemas(schema.ImportedSchemas, visited)...)
+	}
+	return result
+}
+
+// qualifiedName returns a name qualified by its namespace in Clark notation ({namespace}name)
+func qualifiedName(namespace, name string) string {
+	return "{" + namespace + "}" + name
+}
+
+// requiredImports returns the well-known type imports needed by the fields of the proto file
+func (c *Converter) requiredImports(protoFile *model.ProtoFile) []string {
 	var mappedTypes []string
 	for _, message := range protoFile.Messages {
 		for _, field := range message.Fields {
 			mappedTypes = append(mappedTypes, field.Type)
 		}
 	}
-	protoFile.Imports = c.typeMapper.GetRequiredImports(mappedTypes)
+	imports := c.typeMapper.GetRequiredImports(mappedTypes)
 
-	return protoFile, nil
+	var resolved []string
+	for importPath := range c.resolvedImports {
+		resolved = append(resolved, importPath)
+	}
+	if c.abstractOptionImport != "" && usesAbstractOption(protoFile.Messages) && !c.resolvedImports[c.abstractOptionImport] {
+		resolved = append(resolved, c.abstractOptionImport)
+	}
+	sort.Strings(resolved)
+	return append(resolved, imports...)
 }
 
-func (c *Converter) convertSchemaRecursive(schema *model.Schema, protoFile *model.ProtoFile) {
+func (c *Converter) convertSchemaRecursive(schema *model.Schema, protoFile *model.ProtoFile) error {
 	if schema == nil {
-		return
+		return nil
 	}
+	c.sourceSchema = schema
 
 	// Track existing type names to avoid duplicates
 	existingEnums := make(map[string]bool)
@@ -98,89 +541,605 @@ func (c *Converter) convertSchemaRecursive(schema *model.Schema, protoFile *mode
 		}
 	}
 
-	// Second pass: convert all complex types (messages)
-	for _, complexType := range schema.ComplexTypes {
+	// Second pass: convert all complex types (messages), with their names
+	// registered first so that types may refer to types declared after them
+	c.registerMessageNames([]*model.Schema{schema})
+	for i := range schema.ComplexTypes {
+		complexType := &schema.ComplexTypes[i]
 		// Skip ArrayOf pattern types - they will be converted to direct repeated fields
-		if c.isArrayOfPattern(&complexType) {
+		// Key-value entry types are converted to map fields likewise
+		if c.isArrayOfPattern(complexType) || c.isMapEntryPattern(complexType) {
+			continue
+		}
+		message, err := c.convertComplexType(complexType)
+		if err != nil {
+			c.takeWrapperMessages()
+			if err := c.collectTypeError(&xsderrors.ConvertError{TypeName: complexType.Name, Msg: "failed to convert complex type", Err: err}); err != nil {
+				return err
+			}
 			continue
 		}
-		message, err := c.convertComplexType(&complexType)
-		if err != nil {
-			// Log error but continue
+		// Skip if already exists
+		if !existingMessages[message.Name] {
+			protoFile.Messages = append(protoFile.Messages, *message)
+			existingMessages[message.Name] = true
+		}
+		c.appendWrapperMessages(protoFile, existingMessages)
+	}
+
+	// Third pass: convert all elements
+	for _, element := range schema.Elements {
+		if element.HasInlineType() {
+			message, err := c.convertElementToMessage(&element)
+			if err != nil {
+				c.takeWrapperMessages()
+				if err := c.collectTypeError(&xsderrors.ConvertError{TypeName: element.Name, Msg: "failed to convert element", Err: err}); err != nil {
+					return err
+				}
+				continue
+			}
+			// Skip if already exists
+			if !existingMessages[message.Name] {
+				protoFile.Messages = append(protoFile.Messages, *message)
+				existingMessages[message.Name] = true
+			}
+			c.appendWrapperMessages(protoFile, existingMessages)
+		}
+	}
+
+	// Then, convert imported schemas (after parent)
+	for _, importedSchema := range schema.ImportedSchemas {
+		if err := c.convertSchemaRecursive(importedSchema, protoFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerMessageNames gives the complex types of the schemas their unique message
+// names before any of them is converted. Fields referring to a type declared
+// later, as in mutually referencing types, then find its final name in
+// typeRenameMap instead of the raw XSD name.
+func (c *Converter) registerMessageNames(schemas []*model.Schema) {
+	seen := make(map[string]bool)
+	for _, schema := range schemas {
+		for i := range schema.ComplexTypes {
+			complexType := &schema.ComplexTypes[i]
+			if c.isArrayOfPattern(complexType) || c.isMapEntryPattern(complexType) {
+				continue
+			}
+			key := qualifiedName(schema.TargetNamespace, "complexType:"+complexType.Name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			c.registeredMessages[complexType] = c.generateUniqueMessageName(complexType.Name)
+		}
+	}
+}
+
+func (c *Converter) convertComplexType(complexType *model.ComplexType) (*model.ProtoMessage, error) {
+	// A type reached again while it is being converted refers to itself; proto
+	// messages may do so, so the name already registered is all that is needed
+	if c.convertingTypes[complexType.Name] {
+		return &model.ProtoMessage{Name: c.typeRenameMap[complexType.Name]}, nil
+	}
+	if complexType.Name != "" {
+		c.convertingTypes[complexType.Name] = true
+		defer delete(c.convertingTypes, complexType.Name)
+	}
+
+	name, registered := c.registeredMessages[complexType]
+	if registered {
+		delete(c.registeredMessages, complexType)
+	} else {
+		name = c.generateUniqueMessageName(complexType.Name)
+	}
+
+	message := &model.ProtoMessage{
+		Name:          name,
+		IsDeprecated:  c.isDeprecated(complexType.Annotation),
+		Documentation: documentation(complexType.Annotation),
+		SourceFile:    c.sourceFile(),
+		SourceLine:    complexType.Line,
+	}
+
+	if complexType.IsAbstract() {
+		c.markAbstract(message)
+	}
+
+	c.startMessageFieldNumbers(message.Name)
+	c.oneofCounter = 0
+	c.choiceSequenceCounter = 0
+	c.choiceMessages = nil
+
+	fields, err := c.convertComplexTypeFields(complexType)
+	if err != nil {
+		return nil, err
+	}
+	message.Fields = fields
+
+	message.Messages = append(message.Messages, c.choiceMessages...)
+	c.choiceMessages = nil
+
+	c.applyReservedFields(message)
+	c.traceMessage(message)
+	return message, nil
+}
+
+// convertComplexTypeFields converts the content of a complex type to fields of the
+// current message. A type derived by extension starts with the fields of its base.
+func (c *Converter) convertComplexTypeFields(complexType *model.ComplexType) ([]model.ProtoField, error) {
+	var fields []model.ProtoField
+	if extension := complexType.Extension(); extension != nil {
+		baseFields, err := c.inheritBaseFields(extension.Base)
+		if err != nil {
+			return nil, fmt.Errorf("extension of %s: %w", complexType.Name, err)
+		}
+		fields = append(fields, baseFields...)
+	}
+	content := complexType.OwnContent()
+	if err := c.reservePinnedFieldNumbers(c.messageContentElements(content)); err != nil {
+		return nil, err
+	}
+
+	// The text of mixed content comes before the type's own fields
+	if complexType.IsMixed() && !hasMixedContentField(fields) {
+		field, err := c.mixedContentField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+
+	// Attributes take the first field numbers when requested
+	if c.attributesFirst {
+		attributeFields, err := c.convertAttributes(content)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, attributeFields...)
+	}
+
+	// Process sequence children in document order
+	if content.Sequence != nil {
+		sequenceFields, err := c.convertContentSequence(content.Sequence)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, sequenceFields...)
+	}
+
+	// A repeated choice is a list of wrapper messages, each holding one alternative
+	if content.Choice != nil && c.isRepeatedOccurs(content.Choice.MaxOccurs) {
+		field, err := c.convertUnboundedChoice(content.Choice)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	} else if content.Choice != nil {
+		choiceFields, err := c.convertChoice(content.Choice)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, choiceFields...)
+	}
+
+	if !c.attributesFirst {
+		attributeFields, err := c.convertAttributes(content)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, attributeFields...)
+	}
+
+	return fields, nil
+}
+
+// inheritBaseFields converts the fields of an extended base type, numbered as in
+// the base message itself, and continues the numbering of the current message
+// after the highest of them
+func (c *Converter) inheritBaseFields(baseName string) ([]model.ProtoField, error) {
+	base := c.findComplexTypeInSchema(baseName, c.currentSchema)
+	if base == nil {
+		return nil, fmt.Errorf("base type %s not found", baseName)
+	}
+	if c.extendingTypes[base] {
+		return nil, fmt.Errorf("base type %s extends itself", baseName)
+	}
+	c.extendingTypes[base] = true
+	defer delete(c.extendingTypes, base)
+
+	// The base fields are declared, and their prefixes bound, in the schema of the base
+	if schema := c.declaringSchema(base, c.currentSchema, make(map[*model.Schema]bool)); schema != nil {
+		sourceSchema := c.sourceSchema
+		c.sourceSchema = schema
+		defer func() { c.sourceSchema = sourceSchema }()
+	}
+
+	baseMessage, exists := c.typeRenameMap[base.Name]
+	if !exists {
+		baseMessage = c.formatMessageName(base.Name)
+	}
+
+	// The base fields are converted as they are in the base message
+	state := c.saveMessageState()
+	c.startMessageFieldNumbers(baseMessage)
+	c.oneofCounter = 0
+	c.choiceSequenceCounter = 0
+	c.choiceMessages = nil
+	fields, err := c.convertComplexTypeFields(base)
+	baseChoiceMessages := c.choiceMessages
+	oneofCounter, choiceSequenceCounter := c.oneofCounter, c.choiceSequenceCounter
+	c.restoreMessageState(state)
+	if err != nil {
+		return nil, err
+	}
+
+	// Messages nested in the base for its choice sequences are referenced through it
+	nested := make(map[string]bool)
+	for _, message := range baseChoiceMessages {
+		nested[message.Name] = true
+	}
+
+	maxNumber := 0
+	for i := range fields {
+		if nested[fields[i].Type] {
+			fields[i].Type = baseMessage + "." + fields[i].Type
+		}
+		c.usedFieldNumbers[fields[i].Number] = fields[i].Name
+		c.usedFieldNames[fields[i].Name] = true
+		c.recordFieldNumber(fields[i].Name, fields[i].Number)
+		maxNumber = max(maxNumber, fields[i].Number)
+	}
+	c.fieldCounter = max(c.fieldCounter, maxNumber+1)
+	c.oneofCounter = max(c.oneofCounter, oneofCounter)
+	c.choiceSequenceCounter = max(c.choiceSequenceCounter, choiceSequenceCounter)
+	return fields, nil
+}
+
+// hasMixedContentField reports whether the fields include a mixed content field
+func hasMixedContentField(fields []model.ProtoField) bool {
+	for _, field := range fields {
+		if field.Comment == mixedContentComment {
+			return true
+		}
+	}
+	return false
+}
+
+// mixedContentField returns the field holding the text of a mixed content type
+func (c *Converter) mixedContentField() (model.ProtoField, error) {
+	name := c.uniqueFieldName("content", "")
+	fieldType := "string"
+	if c.mixedAsBytes {
+		fieldType = "bytes"
+	}
+	number, err := c.nextFieldNumber(name)
+	if err != nil {
+		return model.ProtoField{}, err
+	}
+	return model.ProtoField{
+		Name:    c.formatFieldName(name),
+		Type:    fieldType,
+		Number:  number,
+		Label:   model.FieldLabelRequired,
+		Comment: mixedContentComment,
+	}, nil
+}
+
+// convertAttributes converts the attributes and attribute wildcard of a complex type
+func (c *Converter) convertAttributes(complexType *model.ComplexType) ([]model.ProtoField, error) {
+	var fields []model.ProtoField
+
+	// Process attributes as fields
+	for _, attribute := range complexType.Attributes {
+		field, err := c.convertAttributeToField(&attribute)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, *field)
+	}
+
+	// Arbitrary attributes allowed by xs:anyAttribute are collected into a map
+	if complexType.AnyAttribute != nil {
+		name := c.uniqueFieldName("attributes", attributeFieldSuffix)
+		number, err := c.nextFieldNumber(name)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, model.ProtoField{
+			Name:    c.formatFieldName(name),
+			Type:    "string",
+			KeyType: "string",
+			IsMap:   true,
+			Number:  number,
+		})
+	}
+
+	return fields, nil
+}
+
+// convertSequence converts sequence children in document order, flattening nested
+// sequences and turning each choice into its own oneof group, or into a list of
+// wrapper messages when the choice is repeated
+func (c *Converter) convertSequence(sequence *model.Sequence) ([]model.ProtoField, error) {
+	var fields []model.ProtoField
+	for _, child := range sequence.Children {
+		switch {
+		case child.Element != nil:
+			field, err := c.convertElementToField(child.Element)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, *field)
+		case child.Choice != nil && c.isRepeatedOccurs(child.Choice.MaxOccurs):
+			field, err := c.convertUnboundedChoice(child.Choice)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+		case child.Choice != nil:
+			choiceFields, err := c.convertChoice(child.Choice)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, choiceFields...)
+		case child.Sequence != nil:
+			nestedFields, err := c.convertSequence(child.Sequence)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nestedFields...)
+		}
+	}
+	return fields, nil
+}
+
+// convertChoice converts the elements of a choice occurring at most once into a
+// oneof group. Repeated elements cannot live in a oneof, so they stay plain fields;
+// repeated choices are converted by convertUnboundedChoice instead.
+func (c *Converter) convertChoice(choice *model.Choice) ([]model.ProtoField, error) {
+	oneofName := c.nextOneofName()
+
+	var fields []model.ProtoField
+	for _, element := range choice.Elements {
+		field, err := c.convertElementToField(&element)
+		if err != nil {
+			return nil, err
+		}
+		if field.Label != model.FieldLabelRepeated {
+			field.Oneof = oneofName
+			field.Label = model.FieldLabelOptional
+		}
+		fields = append(fields, *field)
+	}
+
+	// Each sequence alternative becomes a nested message referenced from the oneof
+	for i := range choice.Sequences {
+		message, err := c.convertChoiceSequence(&choice.Sequences[i])
+		if err != nil {
+			return nil, err
+		}
+		c.choiceMessages = append(c.choiceMessages, *message)
+
+		name := c.formatFieldName(c.uniqueFieldName(fmt.Sprintf("sequence_%d", c.choiceSequenceCounter), ""))
+		number, err := c.nextFieldNumber(name)
+		if err != nil {
+			return nil, err
+		}
+		field := model.ProtoField{
+			Name:   name,
+			Type:   message.Name,
+			Number: number,
+			Label:  model.FieldLabelOptional,
+			Oneof:  oneofName,
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// messageState is the per-message conversion state set aside while a nested
+// message is converted
+type messageState struct {
+	fieldCounter          int
+	currentMessage        string
+	usedFieldNumbers      map[int]string
+	usedFieldNames        map[string]bool
+	oneofCounter          int
+	choiceSequenceCounter int
+	choiceMessages        []model.ProtoMessage
+}
+
+func (c *Converter) saveMessageState() messageState {
+	return messageState{
+		fieldCounter:          c.fieldCounter,
+		currentMessage:        c.currentMessage,
+		usedFieldNumbers:      maps.Clone(c.usedFieldNumbers),
+		usedFieldNames:        maps.Clone(c.usedFieldNames),
+		oneofCounter:          c.oneofCounter,
+		choiceSequenceCounter: c.choiceSequenceCounter,
+		choiceMessages:        c.choiceMessages,
+	}
+}
+
+func (c *Converter) restoreMessageState(state messageState) {
+	c.fieldCounter = state.fieldCounter
+	c.currentMessage = state.currentMessage
+	c.usedFieldNumbers = state.usedFieldNumbers
+	c.usedFieldNames = state.usedFieldNames
+	c.oneofCounter = state.oneofCounter
+	c.choiceSequenceCounter = state.choiceSequenceCounter
+	c.choiceMessages = state.choiceMessages
+}
+
+// convertChoiceSequence converts a sequence inside a choice to a message nested
+// in the current message, named <Parent>_<index>Choice with the index counted
+// across the choices of the parent
+func (c *Converter) convertChoiceSequence(sequence *model.Sequence) (*model.ProtoMessage, error) {
+	c.choiceSequenceCounter++
+	parent := c.currentMessage
+	scopeName := parent
+	if i := strings.LastIndex(parent, "."); i >= 0 {
+		scopeName = parent[i+1:]
+	}
+	message := &model.ProtoMessage{Name: fmt.Sprintf("%s_%dChoice", scopeName, c.choiceSequenceCounter)}
+
+	// The nested message numbers its fields on its own
+	state := c.saveMessageState()
+	defer c.restoreMessageState(state)
+	c.startMessageFieldNumbers(parent + "." + message.Name)
+	c.oneofCounter = 0
+	c.choiceSequenceCounter = 0
+	c.choiceMessages = nil
+	if err := c.reservePinnedFieldNumbers(messageSequenceElements(sequence)); err != nil {
+		return nil, err
+	}
+
+	fields, err := c.convertSequence(sequence)
+	if err != nil {
+		return nil, err
+	}
+	message.Fields = fields
+	message.Messages = c.choiceMessages
+	return message, nil
+}
+
+// assignFieldNumber returns the field number for an element, using its
+// proto-field-number hint when present and the next free number otherwise
+func (c *Converter) assignFieldNumber(element *model.Element) (int, error) {
+	if element.ProtoFieldNumber == 0 {
+		return c.nextFieldNumber(element.Name)
+	}
+	if err := model.CheckFieldNumber(element.ProtoFieldNumber); err != nil {
+		return 0, fmt.Errorf("element %s: %w", element.Name, err)
+	}
+	if owner, taken := c.usedFieldNumbers[element.ProtoFieldNumber]; taken && owner != c.formatFieldName(element.Name) {
+		return 0, fmt.Errorf("field number %d of %s conflicts with field %s", element.ProtoFieldNumber, element.Name, owner)
+	}
+	c.usedFieldNumbers[element.ProtoFieldNumber] = c.formatFieldName(element.Name)
+	c.recordFieldNumber(element.Name, element.ProtoFieldNumber)
+	return element.ProtoFieldNumber, nil
+}
+
+// reservePinnedFieldNumbers takes the numbers pinned by proto-field-number hints
+// of the elements of the current message before any of its fields is numbered,
+// so that fields numbered automatically ahead of a pinned element skip its number.
+// Two elements pinning one number, or a pin on a number of the field map or the
+// reserved map, are errors.
+func (c *Converter) reservePinnedFieldNumbers(elements []*model.Element) error {
+	for _, element := range elements {
+		if element.IsRef() {
+			element = c.resolveElementRef(element)
+		}
+		if element.ProtoFieldNumber == 0 {
 			continue
 		}
-		// Skip if already exists
-		if !existingMessages[message.Name] {
-			protoFile.Messages = append(protoFile.Messages, *message)
-			existingMessages[message.Name] = true
+		if err := model.CheckFieldNumber(element.ProtoFieldNumber); err != nil {
+			return fmt.Errorf("element %s: %w", element.Name, err)
 		}
+		name := c.formatFieldName(element.Name)
+		if owner, taken := c.usedFieldNumbers[element.ProtoFieldNumber]; taken && owner != name {
+			return fmt.Errorf("field number %d of %s conflicts with field %s", element.ProtoFieldNumber, element.Name, owner)
+		}
+		c.usedFieldNumbers[element.ProtoFieldNumber] = name
 	}
+	return nil
+}
 
-	// Third pass: convert all elements
-	for _, element := range schema.Elements {
-		if element.ComplexType != nil {
-			message, err := c.convertElementToMessage(&element)
-			if err != nil {
-				// Log error but continue
-				continue
-			}
-			// Skip if already exists
-			if !existingMessages[message.Name] {
-				protoFile.Messages = append(protoFile.Messages, *message)
-				existingMessages[message.Name] = true
-			}
+// messageContentElements returns the elements of a complex type's content that become
+// fields of its own message, leaving out those of an optional sequence or a
+// repeated choice, which move into a message of their own
+func (c *Converter) messageContentElements(content *model.ComplexType) []*model.Element {
+	var elements []*model.Element
+	if sequence := content.Sequence; sequence != nil {
+		if sequence.MinOccurs != "0" || c.isRepeatedOccurs(sequence.MaxOccurs) || c.inlineOptionalGroups {
+			elements = append(elements, messageSequenceElements(sequence)...)
 		}
 	}
-
-	// Then, convert imported schemas (after parent)
-	for _, importedSchema := range schema.ImportedSchemas {
-		c.convertSchemaRecursive(importedSchema, protoFile)
+	if content.Choice != nil && !c.isRepeatedOccurs(content.Choice.MaxOccurs) {
+		elements = append(elements, messageChoiceElements(content.Choice)...)
 	}
+	return elements
 }
 
-func (c *Converter) convertComplexType(complexType *model.ComplexType) (*model.ProtoMessage, error) {
-	message := &model.ProtoMessage{
-		Name: c.generateUniqueMessageName(complexType.Name),
+// messageSequenceElements returns the elements numbered in the message of a sequence,
+// including those of nested sequences and choices but not of choice sequences,
+// which become nested messages
+func messageSequenceElements(sequence *model.Sequence) []*model.Element {
+	var elements []*model.Element
+	for _, child := range sequence.Children {
+		switch {
+		case child.Element != nil:
+			elements = append(elements, child.Element)
+		case child.Choice != nil:
+			elements = append(elements, messageChoiceElements(child.Choice)...)
+		case child.Sequence != nil:
+			elements = append(elements, messageSequenceElements(child.Sequence)...)
+		}
 	}
+	return elements
+}
 
-	c.fieldCounter = 1
+// messageChoiceElements returns the element alternatives of a choice
+func messageChoiceElements(choice *model.Choice) []*model.Element {
+	elements := make([]*model.Element, len(choice.Elements))
+	for i := range choice.Elements {
+		elements[i] = &choice.Elements[i]
+	}
+	return elements
+}
 
-	// Process sequence elements
-	if complexType.Sequence != nil {
-		for _, element := range complexType.Sequence.Elements {
-			field, err := c.convertElementToField(&element)
-			if err != nil {
-				return nil, err
-			}
-			message.Fields = append(message.Fields, *field)
+// nextFieldNumber returns the number recorded for the field in the field map, or
+// else the next field number not yet taken in the current message. Numbers in
+// the range reserved by protobuf are skipped.
+func (c *Converter) nextFieldNumber(name string) (int, error) {
+	if number, ok := c.persistedFieldNumber(name); ok {
+		if err := model.CheckFieldNumber(number); err != nil {
+			return 0, fmt.Errorf("field map number of %s: %w", name, err)
 		}
+		return number, nil
 	}
-
-	if complexType.Choice != nil {
-		for _, element := range complexType.Choice.Elements {
-			field, err := c.convertElementToField(&element)
-			if err != nil {
-				return nil, err
-			}
-			field.Label = model.FieldLabelOptional
-			message.Fields = append(message.Fields, *field)
+	for {
+		if c.fieldCounter >= model.FirstReservedFieldNumber && c.fieldCounter <= model.LastReservedFieldNumber {
+			c.fieldCounter = model.LastReservedFieldNumber + 1
 		}
+		if _, taken := c.usedFieldNumbers[c.fieldCounter]; !taken {
+			break
+		}
+		c.fieldCounter++
 	}
+	if c.fieldCounter > model.MaxFieldNumber {
+		return 0, fmt.Errorf("field %s: no field number left, the maximum is %d", name, model.MaxFieldNumber)
+	}
+	number := c.fieldCounter
+	c.usedFieldNumbers[number] = name
+	c.recordFieldNumber(name, number)
+	c.fieldCounter++
+	return number, nil
+}
 
-	// Process attributes as fields
-	for _, attribute := range complexType.Attributes {
-		field, err := c.convertAttributeToField(&attribute)
-		if err != nil {
-			return nil, err
-		}
-		message.Fields = append(message.Fields, *field)
+// sourceFile returns the file of the schema being converted, "" when it was not
+// read from a file
+func (c *Converter) sourceFile() string {
+	if c.sourceSchema == nil {
+		return ""
 	}
+	return c.sourceSchema.Location
+}
 
-	return message, nil
+// nextOneofName returns a oneof name unique within the current message
+func (c *Converter) nextOneofName() string {
+	c.oneofCounter++
+	if c.oneofCounter == 1 {
+		return "choice"
+	}
+	return fmt.Sprintf("choice_%d", c.oneofCounter)
 }
 
 func (c *Converter) convertElementToMessage(element *model.Element) (*model.ProtoMessage, error) {
-	if element.ComplexType == nil {
+	if !element.HasInlineType() {
 		return nil, fmt.Errorf("element %s has no complex type", element.Name)
 	}
 
@@ -189,31 +1148,100 @@ func (c *Converter) convertElementToMessage(element *model.Element) (*model.Prot
 		messageName = element.ComplexType.Name
 	}
 
-	element.ComplexType.Name = messageName
-	return c.convertComplexType(element.ComplexType)
+	// Name a copy so that the schema is not modified, e.g. while clones convert it concurrently
+	complexType := *element.ComplexType
+	complexType.Name = messageName
+	message, err := c.convertComplexType(&complexType)
+	if err != nil {
+		return nil, err
+	}
+	if c.isDeprecated(element.Annotation) {
+		message.IsDeprecated = true
+	}
+	if doc := documentation(element.Annotation); doc != "" {
+		message.Documentation = doc
+	}
+	message.SourceLine = element.Line
+	if element.IsAbstract() && message.Comment != abstractTypeComment {
+		c.markAbstract(message)
+	}
+	return message, nil
 }
 
 func (c *Converter) convertElementToField(element *model.Element) (*model.ProtoField, error) {
-	protoType, err := c.typeMapper.MapXSDType(element.Type)
+	// A reference is located where it is used, not at the declaration it refers to
+	sourceLine := element.Line
+	var groupMessage string
+	if element.IsRef() {
+		groupMessage, _ = c.substitutionGroupMessage(element.Ref)
+		element = c.resolveElementRef(element)
+	}
+	if name := c.uniqueFieldName(element.Name, elementFieldSuffix); name != element.Name {
+		renamed := *element
+		renamed.Name = name
+		element = &renamed
+	}
+
+	mapped, err := c.typeMapper.mapXSDTypeDetailed(element.Type)
 	if err != nil {
 		return nil, err
 	}
+	protoType := mapped.ProtoType
+
+	// A key-value entry type, or an ArrayOf wrapper of one, becomes a map field
+	if valueType := c.getMapEntryValueType(element.Type); valueType != "" {
+		c.trace(TraceEvent{Action: TraceActionMapType, XSDType: element.Type, ProtoType: "map<string, " + valueType + ">"})
+
+		number, err := c.assignFieldNumber(element)
+		if err != nil {
+			return nil, err
+		}
+
+		field := &model.ProtoField{
+			Name:    c.formatFieldName(element.Name),
+			Type:    valueType,
+			KeyType: "string",
+			IsMap:   true,
+			Number:  number,
+			Label:   model.FieldLabelRepeated, // Keeps the map out of oneof groups
+
+			IsDeprecated:  c.isDeprecated(element.Annotation),
+			Documentation: documentation(element.Annotation),
+			SourceFile:    c.sourceFile(),
+			SourceLine:    sourceLine,
+		}
+		c.applyAppInfo(field, element.Annotation)
+		return field, nil
+	}
 
 	// Check if this field references an ArrayOf pattern type
 	arrayElementType := c.getArrayOfElementType(element.Type)
 	if arrayElementType != "" {
+		c.trace(TraceEvent{Action: TraceActionMapType, XSDType: element.Type, ProtoType: arrayElementType})
+
+		number, err := c.assignFieldNumber(element)
+		if err != nil {
+			return nil, err
+		}
+
 		// Convert ArrayOf reference to direct repeated field
 		field := &model.ProtoField{
 			Name:   c.formatFieldName(element.Name),
 			Type:   arrayElementType,
-			Number: c.fieldCounter,
+			Number: number,
 			Label:  model.FieldLabelRepeated,
+
+			IsDeprecated:  c.isDeprecated(element.Annotation),
+			Documentation: documentation(element.Annotation),
+			SourceFile:    c.sourceFile(),
+			SourceLine:    sourceLine,
 		}
-		c.fieldCounter++
+		c.applyAppInfo(field, element.Annotation)
 		return field, nil
 	}
 
 	var comment string
+	restrictsBuiltIn := false
 	// Check if this type references a string-based enumeration
 	if c.isStringBasedEnumerationType(element.Type) {
 		protoType = "string"
@@ -221,10 +1249,31 @@ func (c *Converter) convertElementToField(element *model.Element) (*model.ProtoF
 		if values := c.getStringEnumerationValues(element.Type); len(values) > 0 {
 			comment = fmt.Sprintf("Valid values: %s", strings.Join(values, ", "))
 		}
+	} else if baseType := c.simpleTypeBaseType(element.Type); baseType != "" {
+		// Other restrictions take the proto type of their built-in base
+		protoType = baseType
+		restrictsBuiltIn = true
+	}
+	if c.typeMapper.IsDatePartialType(element.Type) {
+		comment = "XSD date partial"
+	}
+	if dateTimeComment := c.typeMapper.dateTimeComment(element.Type); dateTimeComment != "" {
+		comment = dateTimeComment
+	}
+	if c.smallIntComments && c.typeMapper.IsSmallIntegerType(element.Type) {
+		comment = joinComments(comment, "xs:"+c.typeMapper.CleanTypeName(element.Type))
+	}
+	comment = appendValueConstraint(comment, element)
+
+	resolvedType, err := c.resolveExternalType(element.Type)
+	if err != nil {
+		return nil, err
 	}
 
 	// If the type has been renamed, use the new name
-	if !c.typeMapper.IsBuiltInType(element.Type) && protoType != "string" {
+	if resolvedType != "" {
+		protoType = resolvedType
+	} else if !c.typeMapper.IsBuiltInType(element.Type) && protoType != "string" && !restrictsBuiltIn {
 		// For custom types, check if they have been renamed
 		cleanType := c.typeMapper.CleanTypeName(element.Type)
 
@@ -234,7 +1283,7 @@ func (c *Converter) convertElementToField(element *model.Element) (*model.ProtoF
 		} else {
 			// If not found, check if the Pascal case version has been renamed
 			// This handles case-insensitive type references
-			pascalCaseType := c.toPascalCase(cleanType)
+			pascalCaseType := c.formatTypeName(cleanType)
 			if renamedType, exists := c.typeRenameMap[pascalCaseType]; exists {
 				protoType = renamedType
 			} else {
@@ -244,25 +1293,83 @@ func (c *Converter) convertElementToField(element *model.Element) (*model.ProtoF
 		}
 	}
 
+	// A reference to a substitution group head accepts any member of the group
+	if groupMessage != "" {
+		protoType = groupMessage
+	}
+
+	// A nillable scalar needs a wrapper to tell xsi:nil apart from the default value
+	if c.useWrappers && element.Nillable == "true" {
+		if wrapper, ok := c.typeMapper.WrapperType(protoType); ok {
+			protoType = wrapper
+		}
+	}
+
+	c.trace(TraceEvent{Action: TraceActionMapType, XSDType: element.Type, ProtoType: protoType})
+
+	number, err := c.assignFieldNumber(element)
+	if err != nil {
+		return nil, err
+	}
+
 	field := &model.ProtoField{
 		Name:    c.formatFieldName(element.Name),
 		Type:    protoType,
-		Number:  c.fieldCounter,
+		Number:  number,
 		Label:   c.determineFieldLabel(element.MinOccurs, element.MaxOccurs),
 		Comment: comment,
+
+		IsDeprecated:  c.isDeprecated(element.Annotation),
+		Documentation: documentation(element.Annotation),
+		SourceFile:    c.sourceFile(),
+		SourceLine:    sourceLine,
+	}
+	if mapped.IsRepeated {
+		field.Label = model.FieldLabelRepeated
+	}
+	field.Constraints = c.facetConstraints(element.Type)
+	if element.Fixed != "" {
+		field.Constraints = append(field.Constraints, model.FieldConstraint{Facet: "fixed", Value: element.Fixed})
 	}
+	c.applyAppInfo(field, element.Annotation)
 
-	c.fieldCounter++
 	return field, nil
 }
 
+// appendValueConstraint adds the element's default or fixed value to a field comment
+func appendValueConstraint(comment string, element *model.Element) string {
+	switch {
+	case element.Fixed != "":
+		return joinComments(comment, "fixed: "+element.Fixed)
+	case element.Default != "":
+		return joinComments(comment, "default: "+element.Default)
+	}
+	return comment
+}
+
+// joinComments appends a note to a field comment
+func joinComments(comment, note string) string {
+	if comment == "" {
+		return note
+	}
+	return comment + "; " + note
+}
+
 func (c *Converter) convertAttributeToField(attribute *model.Attribute) (*model.ProtoField, error) {
-	protoType, err := c.typeMapper.MapXSDType(attribute.Type)
+	if name := c.uniqueFieldName(attribute.Name, attributeFieldSuffix); name != attribute.Name {
+		renamed := *attribute
+		renamed.Name = name
+		attribute = &renamed
+	}
+
+	mapped, err := c.typeMapper.mapXSDTypeDetailed(attribute.Type)
 	if err != nil {
 		return nil, err
 	}
+	protoType := mapped.ProtoType
 
 	var comment string
+	restrictsBuiltIn := false
 	// Check if this type references a string-based enumeration
 	if c.isStringBasedEnumerationType(attribute.Type) {
 		protoType = "string"
@@ -270,10 +1377,33 @@ func (c *Converter) convertAttributeToField(attribute *model.Attribute) (*model.
 		if values := c.getStringEnumerationValues(attribute.Type); len(values) > 0 {
 			comment = fmt.Sprintf("Valid values: %s", strings.Join(values, ", "))
 		}
+	} else if baseType := c.simpleTypeBaseType(attribute.Type); baseType != "" {
+		// Other restrictions take the proto type of their built-in base
+		protoType = baseType
+		restrictsBuiltIn = true
+	}
+	if c.typeMapper.IsDatePartialType(attribute.Type) {
+		comment = "XSD date partial"
+	}
+	if dateTimeComment := c.typeMapper.dateTimeComment(attribute.Type); dateTimeComment != "" {
+		comment = dateTimeComment
+	}
+	if c.smallIntComments && c.typeMapper.IsSmallIntegerType(attribute.Type) {
+		comment = joinComments(comment, "xs:"+c.typeMapper.CleanTypeName(attribute.Type))
+	}
+	if attribute.Fixed != "" {
+		comment = joinComments(comment, fmt.Sprintf("fixed: %q", attribute.Fixed))
+	}
+
+	resolvedType, err := c.resolveExternalType(attribute.Type)
+	if err != nil {
+		return nil, err
 	}
 
 	// If the type has been renamed, use the new name
-	if !c.typeMapper.IsBuiltInType(attribute.Type) && protoType != "string" {
+	if resolvedType != "" {
+		protoType = resolvedType
+	} else if !c.typeMapper.IsBuiltInType(attribute.Type) && protoType != "string" && !restrictsBuiltIn {
 		// For custom types, check if they have been renamed
 		cleanType := c.typeMapper.CleanTypeName(attribute.Type)
 
@@ -283,52 +1413,103 @@ func (c *Converter) convertAttributeToField(attribute *model.Attribute) (*model.
 		} else {
 			// If not found, check if the Pascal case version has been renamed
 			// This handles case-insensitive type references
-			pascalCaseType := c.toPascalCase(cleanType)
+			pascalCaseType := c.formatTypeName(cleanType)
 			if renamedType, exists := c.typeRenameMap[pascalCaseType]; exists {
 				protoType = renamedType
 			}
 		}
 	}
 
+	c.trace(TraceEvent{Action: TraceActionMapType, XSDType: attribute.Type, ProtoType: protoType})
+
+	number, err := c.nextFieldNumber(attribute.Name)
+	if err != nil {
+		return nil, err
+	}
 	field := &model.ProtoField{
 		Name:    c.formatFieldName(attribute.Name),
 		Type:    protoType,
-		Number:  c.fieldCounter,
+		Number:  number,
 		Label:   c.determineAttributeLabel(attribute.Use),
 		Comment: comment,
+
+		IsDeprecated:  c.isDeprecated(attribute.Annotation),
+		Documentation: documentation(attribute.Annotation),
+		SourceFile:    c.sourceFile(),
+		SourceLine:    attribute.Line,
+	}
+	if mapped.IsRepeated {
+		field.Label = model.FieldLabelRepeated
+	}
+	field.Constraints = c.facetConstraints(attribute.Type)
+	if attribute.Fixed != "" {
+		field.Constraints = append(field.Constraints, model.FieldConstraint{Facet: "fixed", Value: attribute.Fixed})
 	}
+	c.applyAppInfo(field, attribute.Annotation)
 
-	c.fieldCounter++
 	return field, nil
 }
 
+// hasDuplicateEnumNumbers reports whether two enum values share a number,
+// which protoc accepts only with allow_alias
+func hasDuplicateEnumNumbers(values []model.ProtoEnumValue) bool {
+	seen := make(map[int]bool)
+	for _, value := range values {
+		if seen[value.Number] {
+			return true
+		}
+		seen[value.Number] = true
+	}
+	return false
+}
+
 func (c *Converter) convertSimpleTypeToEnum(simpleType *model.SimpleType) *model.ProtoEnum {
 	uniqueEnumName := c.generateUniqueEnumName(simpleType.Name)
 	enum := &model.ProtoEnum{
-		Name: uniqueEnumName,
+		Name:          uniqueEnumName,
+		Documentation: documentation(simpleType.Annotation),
 	}
 
 	// First, add the UNSPECIFIED value at index 0
-	unspecifiedValue := model.ProtoEnumValue{
-		Name:   c.generateUniqueEnumValueName(uniqueEnumName, "UNSPECIFIED", true),
-		Number: 0,
+	number := 0
+	if !c.noUnspecified {
+		unspecifiedValue := model.ProtoEnumValue{
+			Name:   c.generateUniqueEnumValueName(uniqueEnumName, "UNSPECIFIED", true),
+			Number: 0,
+		}
+		enum.Values = append(enum.Values, unspecifiedValue)
+		number = 1
 	}
-	enum.Values = append(enum.Values, unspecifiedValue)
 
-	// Then add all the actual enum values starting from index 1
-	for i, enumeration := range simpleType.Restriction.Enumerations {
+	// Then add all the actual enum values starting from index 1, or 0 without UNSPECIFIED
+	aliasNumbers := make(map[string]int)
+	for _, enumeration := range simpleType.Restriction.Enumerations {
 		enumValue := model.ProtoEnumValue{
-			Name:   c.generateUniqueEnumValueName(uniqueEnumName, enumeration.Value, false),
-			Number: i + 1,
+			Name:    c.generateUniqueEnumValueName(uniqueEnumName, enumeration.Value, false),
+			Number:  number,
+			Comment: documentation(enumeration.Annotation),
+		}
+		if c.allowAlias {
+			key := strings.ToUpper(enumeration.Value)
+			if aliased, exists := aliasNumbers[key]; exists {
+				enumValue.Number = aliased
+			} else {
+				aliasNumbers[key] = number
+				number++
+			}
+		} else {
+			number++
 		}
 		enum.Values = append(enum.Values, enumValue)
 	}
+	enum.AllowAlias = hasDuplicateEnumNumbers(enum.Values)
 
+	c.trace(TraceEvent{Action: TraceActionEmitEnum, Enum: enum.Name})
 	return enum
 }
 
 func (c *Converter) formatMessageName(name string) string {
-	return c.toPascalCase(name)
+	return c.formatTypeName(name)
 }
 
 func (c *Converter) formatFieldName(name string) string {
@@ -342,24 +1523,35 @@ func (c *Converter) formatFieldName(name string) string {
 }
 
 func (c *Converter) formatEnumName(name string) string {
+	return c.formatTypeName(name)
+}
+
+// formatTypeName returns the proto name of an XSD type: its PascalCase form, or
+// the name as written when original names are kept
+func (c *Converter) formatTypeName(name string) string {
+	if c.keepNames {
+		return name
+	}
 	return c.toPascalCase(name)
 }
 
 // generateUniqueEnumValueName adds prefix to enum value names to avoid duplicates
 func (c *Converter) generateUniqueEnumValueName(enumName, valueName string, isFirstValue bool) string {
-	// Convert enum name to snake_case for the prefix (e.g., FixtureType -> fixture_type -> FIXTURE_TYPE)
-	enumSnakeCase := c.toSnakeCase(enumName)
-	enumPrefix := strings.ToUpper(enumSnakeCase)
+	enumPrefix := c.enumValuePrefixer(c, enumName)
 
 	// For the first value (index 0), add _UNSPECIFIED suffix according to protobuf style guide
-	var prefixedName string
-	if isFirstValue {
-		prefixedName = enumPrefix + "_UNSPECIFIED"
-	} else {
-		basicValueName := strings.ToUpper(valueName)
-		prefixedName = enumPrefix + "_" + basicValueName
+	// unless the sentinel is disabled, in which case the first value is a regular one
+	basicValueName := "UNSPECIFIED"
+	if !isFirstValue || c.noUnspecified {
+		basicValueName = strings.ToUpper(valueName)
 	}
 
+	// Identifiers cannot start with a digit, so keep the enum name prefix for such values
+	if enumPrefix == "" && basicValueName != "" && basicValueName[0] >= '0' && basicValueName[0] <= '9' {
+		enumPrefix = enumNamePrefix(c, enumName)
+	}
+	prefixedName := enumPrefix + basicValueName
+
 	if !c.usedEnumValues[prefixedName] {
 		c.usedEnumValues[prefixedName] = true
 		return prefixedName
@@ -380,7 +1572,7 @@ func (c *Converter) generateUniqueEnumValueName(enumName, valueName string, isFi
 
 // generateUniqueMessageName ensures message names are unique
 func (c *Converter) generateUniqueMessageName(originalName string) string {
-	formattedName := c.formatMessageName(originalName)
+	formattedName := c.avoidReservedTypeName(c.formatMessageName(originalName))
 
 	// Check if the formatted name is already used by either messages or enums
 	if !c.usedMessageNames[formattedName] && !c.usedEnumNames[formattedName] {
@@ -396,6 +1588,7 @@ func (c *Converter) generateUniqueMessageName(originalName string) string {
 		if !c.usedMessageNames[candidateName] && !c.usedEnumNames[candidateName] {
 			c.usedMessageNames[candidateName] = true
 			c.typeRenameMap[originalName] = candidateName
+			c.collisionRenames[originalName] = candidateName
 			return candidateName
 		}
 		counter++
@@ -404,7 +1597,7 @@ func (c *Converter) generateUniqueMessageName(originalName string) string {
 
 // generateUniqueEnumName ensures enum names are unique
 func (c *Converter) generateUniqueEnumName(originalName string) string {
-	formattedName := c.formatEnumName(originalName)
+	formattedName := c.avoidReservedTypeName(c.formatEnumName(originalName))
 
 	// Check if the formatted name is already used by either messages or enums
 	if !c.usedEnumNames[formattedName] && !c.usedMessageNames[formattedName] {
@@ -420,13 +1613,34 @@ func (c *Converter) generateUniqueEnumName(originalName string) string {
 		if !c.usedEnumNames[candidateName] && !c.usedMessageNames[candidateName] {
 			c.usedEnumNames[candidateName] = true
 			c.typeRenameMap[originalName] = candidateName
+			c.collisionRenames[originalName] = candidateName
 			return candidateName
 		}
 		counter++
 	}
 }
 
+// protoPackageName returns the package set with SetPackageName, or the one derived
+// from the target namespace
+func (c *Converter) protoPackageName(targetNamespace string) string {
+	if c.packageName != "" {
+		return c.packageName
+	}
+	return c.generatePackageName(targetNamespace)
+}
+
+// generatePackageName derives the proto package from the target namespace and
+// prepends the package prefix unless the derived name already starts with it
 func (c *Converter) generatePackageName(targetNamespace string) string {
+	name := namespacePackageName(targetNamespace)
+	if c.packagePrefix == "" || name == c.packagePrefix || strings.HasPrefix(name, c.packagePrefix+".") {
+		return name
+	}
+	return c.packagePrefix + "." + name
+}
+
+// namespacePackageName derives a proto package name from a target namespace
+func namespacePackageName(targetNamespace string) string {
 	if targetNamespace == "" {
 		return "generated"
 	}
@@ -497,9 +1711,14 @@ func (c *Converter) generatePackageName(targetNamespace string) string {
 	return result
 }
 
+// isRepeatedOccurs checks if a maxOccurs value allows more than one occurrence
+func (c *Converter) isRepeatedOccurs(maxOccurs string) bool {
+	return maxOccurs == "unbounded" || (maxOccurs != "" && maxOccurs != "1")
+}
+
 func (c *Converter) determineFieldLabel(minOccurs, maxOccurs string) model.FieldLabel {
 	// Handle repeated fields first
-	if maxOccurs == "unbounded" || (maxOccurs != "" && maxOccurs != "1") {
+	if c.isRepeatedOccurs(maxOccurs) {
 		return model.FieldLabelRepeated
 	}
 
@@ -544,6 +1763,40 @@ func (c *Converter) toPascalCase(s string) string {
 	return result.String()
 }
 
+// splitWords splits a name without separators into words at each isWordBoundary
+func splitWords(s string) []string {
+	if len(s) == 0 {
+		return []string{}
+	}
+
+	runes := []rune(s)
+	var parts []string
+	start := 0
+	for i := range runes {
+		if isWordBoundary(runes, i) {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
+
+// isWordBoundary reports whether a new word starts at runes[i], following the
+// rules of protoc-gen-go: an uppercase letter after a lowercase letter or digit
+// starts a word, and so does the last letter of an uppercase run followed by a
+// lowercase letter. Digits stay with the word before them, so HTTPSEndpoint
+// splits into HTTPS and Endpoint and HTTP2Status into HTTP2 and Status.
+func isWordBoundary(runes []rune, i int) bool {
+	if i == 0 || !unicode.IsUpper(runes[i]) {
+		return false
+	}
+	prev := runes[i-1]
+	if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+}
+
 // isArrayOfPattern checks if a complex type follows the ArrayOf pattern
 // Returns true if the type name starts with "ArrayOf" and contains only one repeated element
 func (c *Converter) isArrayOfPattern(complexType *model.ComplexType) bool {
@@ -591,41 +1844,25 @@ func (c *Converter) getArrayOfElementType(typeName string) string {
 		}
 
 		// For custom types, return the Pascal case formatted name
-		return c.toPascalCase(elementType)
+		return c.formatTypeName(elementType)
 	}
 
 	return ""
 }
 
+// splitCamelCase splits a name into its words, keeping runs of uppercase
+// letters such as acronyms together: XMLParser becomes XML and Parser
 func (c *Converter) splitCamelCase(s string) []string {
-	if len(s) == 0 {
-		return []string{}
-	}
-
-	var parts []string
-	var current strings.Builder
-
-	for i, r := range s {
-		if i > 0 && r >= 'A' && r <= 'Z' {
-			if current.Len() > 0 {
-				parts = append(parts, current.String())
-				current.Reset()
-			}
-		}
-		current.WriteRune(r)
-	}
-
-	if current.Len() > 0 {
-		parts = append(parts, current.String())
-	}
-
-	return parts
+	return splitWords(s)
 }
 
+// toSnakeCase converts a name to snake_case with the word boundaries of
+// splitWords, e.g. getUserID becomes get_user_id
 func (c *Converter) toSnakeCase(s string) string {
+	runes := []rune(s)
 	var result strings.Builder
-	for i, r := range s {
-		if i > 0 && (r >= 'A' && r <= 'Z') {
+	for i, r := range runes {
+		if isWordBoundary(runes, i) {
 			result.WriteRune('_')
 		}
 		if r == '-' || r == '.' {
@@ -707,6 +1944,62 @@ func (c *Converter) findSimpleTypeInSchema(typeName string, schema *model.Schema
 	return nil
 }
 
+// findElementInSchema searches for a top-level Element in the schema hierarchy
+func (c *Converter) findElementInSchema(elementName string, schema *model.Schema) *model.Element {
+	if schema == nil {
+		return nil
+	}
+
+	cleanName := c.typeMapper.CleanTypeName(elementName)
+
+	// Search in current schema first (current schema has priority)
+	for i := range schema.Elements {
+		if schema.Elements[i].Name == cleanName {
+			return &schema.Elements[i]
+		}
+	}
+
+	// Search in imported schemas
+	for _, importedSchema := range schema.ImportedSchemas {
+		if element := c.findElementInSchema(elementName, importedSchema); element != nil {
+			return element
+		}
+	}
+
+	return nil
+}
+
+// resolveElementRef returns the element declaration a ref element points to, keeping
+// the occurrence constraints of the reference
+// An inline complex type on the target is referenced by the target element's name,
+// which is the name its message is generated under
+func (c *Converter) resolveElementRef(ref *model.Element) *model.Element {
+	resolved := model.Element{
+		Name: c.typeMapper.CleanTypeName(ref.Ref),
+		Type: ref.Ref,
+	}
+
+	if target := c.findElementInSchema(ref.Ref, c.currentSchema); target != nil {
+		resolved = *target
+		if target.HasInlineType() {
+			resolved.Type = target.Name
+			resolved.ComplexType = nil
+		}
+	}
+
+	resolved.Ref = ""
+	resolved.MinOccurs = ref.MinOccurs
+	resolved.MaxOccurs = ref.MaxOccurs
+	if ref.ProtoFieldNumber != 0 {
+		resolved.ProtoFieldNumber = ref.ProtoFieldNumber
+	}
+	if ref.Annotation != nil {
+		resolved.Annotation = ref.Annotation
+	}
+
+	return &resolved
+}
+
 // findComplexTypeInSchema searches for a ComplexType in the schema hierarchy
 func (c *Converter) findComplexTypeInSchema(typeName string, schema *model.Schema) *model.ComplexType {
 	if schema == nil {
@@ -732,6 +2025,26 @@ func (c *Converter) findComplexTypeInSchema(typeName string, schema *model.Schem
 	return nil
 }
 
+// declaringSchema returns the schema among schema and its imports whose complex
+// types include complexType
+func (c *Converter) declaringSchema(complexType *model.ComplexType, schema *model.Schema, visited map[*model.Schema]bool) *model.Schema {
+	if schema == nil || visited[schema] {
+		return nil
+	}
+	visited[schema] = true
+	for i := range schema.ComplexTypes {
+		if &schema.ComplexTypes[i] == complexType {
+			return schema
+		}
+	}
+	for _, importedSchema := range schema.ImportedSchemas {
+		if declaring := c.declaringSchema(complexType, importedSchema, visited); declaring != nil {
+			return declaring
+		}
+	}
+	return nil
+}
+
 // isStringBasedEnumerationType checks if a type name references a string-based enumeration
 func (c *Converter) isStringBasedEnumerationType(typeName string) bool {
 	if c.currentSchema == nil {
@@ -752,6 +2065,36 @@ func (c *Converter) isStringBasedEnumerationType(typeName string) bool {
 	return false
 }
 
+// simpleTypeBaseType returns the proto type of the built-in base of a simple type
+// restricting it without enumerations, possibly through other restrictions.
+// Returns empty string for other types, which are enums or messages.
+func (c *Converter) simpleTypeBaseType(typeName string) string {
+	if c.currentSchema == nil || c.typeMapper.IsBuiltInType(typeName) {
+		return ""
+	}
+	if complexType := c.findComplexTypeInSchema(typeName, c.currentSchema); complexType != nil {
+		return ""
+	}
+
+	simpleType := c.findSimpleTypeInSchema(typeName, c.currentSchema)
+	// Bound the walk so that a restriction cycle cannot loop forever
+	for depth := 0; simpleType != nil && depth < 32; depth++ {
+		restriction := simpleType.Restriction
+		if restriction == nil || (len(restriction.Enumerations) > 0 && !c.isStringBasedEnumeration(simpleType)) {
+			return ""
+		}
+		if c.typeMapper.IsBuiltInType(restriction.Base) {
+			protoType, err := c.typeMapper.MapXSDType(restriction.Base)
+			if err != nil {
+				return ""
+			}
+			return protoType
+		}
+		simpleType = c.findSimpleTypeInSchema(restriction.Base, c.currentSchema)
+	}
+	return ""
+}
+
 // getStringEnumerationValues returns the enumeration values for a string-based enumeration type
 func (c *Converter) getStringEnumerationValues(typeName string) []string {
 	if c.currentSchema == nil {
@@ -769,3 +2112,61 @@ func (c *Converter) getStringEnumerationValues(typeName string) []string {
 
 	return nil
 }
+
+// isDeprecated checks if an annotation marks its owner as deprecated, either with
+// "deprecated" anywhere in the documentation (case-insensitive) or with an appinfo
+// of source="deprecated" and content true, or content deprecated=true
+func (c *Converter) isDeprecated(annotation *model.Annotation) bool {
+	if annotation == nil {
+		return false
+	}
+
+	for _, doc := range annotation.Documentation {
+		if strings.Contains(strings.ToLower(doc.Content), "deprecated") {
+			return true
+		}
+	}
+
+	for _, appInfo := range annotation.AppInfo {
+		if isDeprecatedAppInfo(appInfo) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDeprecatedAppInfo reports whether an appinfo marks its owner as deprecated
+func isDeprecatedAppInfo(appInfo model.AppInfo) bool {
+	content := strings.ReplaceAll(strings.TrimSpace(appInfo.Content), " ", "")
+	if appInfo.Source == "deprecated" {
+		return content == "true"
+	}
+	return content == "deprecated=true"
+}
+
+// applyAppInfo stores free-form appinfo content of an annotation in the field options
+// Appinfo with a source understood by the converter (such as deprecated) is skipped
+func (c *Converter) applyAppInfo(field *model.ProtoField, annotation *model.Annotation) {
+	if annotation == nil {
+		return
+	}
+
+	var values []string
+	for _, appInfo := range annotation.AppInfo {
+		if appInfo.Source == "deprecated" || appInfo.Source == model.AppInfoSourceFieldNumber || isDeprecatedAppInfo(appInfo) {
+			continue
+		}
+		if value := strings.TrimSpace(appInfo.Content); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+
+	if field.Options == nil {
+		field.Options = make(map[string]string)
+	}
+	field.Options[model.AppInfoOptionKey] = strings.Join(values, ", ")
+}
@@ -0,0 +1,50 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnumValuePrefixStyle selects how enum value names are prefixed
+type EnumValuePrefixStyle string
+
+const (
+	// EnumValuePrefixEnumName prefixes values with the full enum name (FIXTURE_TYPE_MATCH)
+	EnumValuePrefixEnumName EnumValuePrefixStyle = "enum-name"
+	// EnumValuePrefixTypeName prefixes values with the first word of the enum name (FIXTURE_MATCH)
+	EnumValuePrefixTypeName EnumValuePrefixStyle = "type-name"
+	// EnumValuePrefixNone uses the enumeration values as-is (MATCH)
+	EnumValuePrefixNone EnumValuePrefixStyle = "none"
+)
+
+// enumValuePrefixer returns the prefix, including the trailing underscore, for values of an enum
+type enumValuePrefixer func(c *Converter, enumName string) string
+
+func enumNamePrefix(c *Converter, enumName string) string {
+	// Convert enum name to snake_case for the prefix (e.g., FixtureType -> fixture_type -> FIXTURE_TYPE)
+	return strings.ToUpper(c.toSnakeCase(enumName)) + "_"
+}
+
+func typeNamePrefix(c *Converter, enumName string) string {
+	firstWord := strings.SplitN(c.toSnakeCase(enumName), "_", 2)[0]
+	return strings.ToUpper(firstWord) + "_"
+}
+
+func noPrefix(c *Converter, enumName string) string {
+	return ""
+}
+
+// SetEnumValuePrefixStyle selects the prefix strategy used for enum value names
+func (c *Converter) SetEnumValuePrefixStyle(style EnumValuePrefixStyle) error {
+	switch style {
+	case EnumValuePrefixEnumName:
+		c.enumValuePrefixer = enumNamePrefix
+	case EnumValuePrefixTypeName:
+		c.enumValuePrefixer = typeNamePrefix
+	case EnumValuePrefixNone:
+		c.enumValuePrefixer = noPrefix
+	default:
+		return fmt.Errorf("unknown enum value prefix style %q (expected enum-name, type-name or none)", style)
+	}
+	return nil
+}
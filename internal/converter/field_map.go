@@ -0,0 +1,45 @@
+package converter
+
+// FieldMap records the field numbers of every message, keyed by message name
+// and then by proto field name. Persisting it between runs keeps field numbers
+// stable when the XSD evolves.
+type FieldMap map[string]map[string]int
+
+// SetFieldMap sets the field numbers to reuse. Numbers allocated for new fields
+// are added to the map, and numbers of fields no longer in the schema stay
+// reserved so they are never given to another field.
+func (c *Converter) SetFieldMap(fieldMap FieldMap) {
+	c.fieldMap = fieldMap
+}
+
+// startMessageFieldNumbers prepares field numbering for a new message, reserving
+// the numbers recorded for it in the field map and those of its removed fields
+func (c *Converter) startMessageFieldNumbers(messageName string) {
+	c.fieldCounter = c.fieldNumberBase
+	c.currentMessage = messageName
+	clear(c.usedFieldNumbers)
+	clear(c.usedFieldNames)
+	for name, number := range c.fieldMap[messageName] {
+		c.usedFieldNumbers[number] = name
+	}
+	c.reserveRemovedFieldNumbers()
+}
+
+// persistedFieldNumber returns the number recorded for a field of the current message
+func (c *Converter) persistedFieldNumber(name string) (int, bool) {
+	number, ok := c.fieldMap[c.currentMessage][c.formatFieldName(name)]
+	return number, ok
+}
+
+// recordFieldNumber adds a newly allocated field number to the field map
+func (c *Converter) recordFieldNumber(name string, number int) {
+	if c.fieldMap == nil {
+		return
+	}
+	fields := c.fieldMap[c.currentMessage]
+	if fields == nil {
+		fields = make(map[string]int)
+		c.fieldMap[c.currentMessage] = fields
+	}
+	fields[c.formatFieldName(name)] = number
+}
@@ -0,0 +1,30 @@
+package converter
+
+import "fmt"
+
+// Suffixes given to an element or attribute whose field name is already used in the message
+const (
+	elementFieldSuffix   = "_elem"
+	attributeFieldSuffix = "_attr"
+)
+
+// uniqueFieldName returns the name to convert a field from so that its proto
+// name is not yet used in the current message. A taken name gets the suffix,
+// then _2, _3 and so on, and the rename is reported as a warning.
+func (c *Converter) uniqueFieldName(name, suffix string) string {
+	candidate := name
+	for n := 1; c.usedFieldNames[c.formatFieldName(candidate)]; n++ {
+		if n == 1 && suffix != "" {
+			candidate = name + suffix
+			continue
+		}
+		candidate = fmt.Sprintf("%s_%d", name, n)
+	}
+	c.usedFieldNames[c.formatFieldName(candidate)] = true
+
+	if candidate != name {
+		c.warnings.Add("field %q is already used in message %s, emitting it as %q",
+			c.formatFieldName(name), c.currentMessage, c.formatFieldName(candidate))
+	}
+	return candidate
+}
@@ -0,0 +1,85 @@
+package converter
+
+import (
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// Default element names of a key-value entry type
+const (
+	DefaultMapKeyName   = "key"
+	DefaultMapValueName = "value"
+)
+
+// SetMapEntryNames sets the element names identifying key-value entry types.
+// Empty names keep the defaults "key" and "value".
+func (c *Converter) SetMapEntryNames(keyName, valueName string) {
+	if keyName == "" {
+		keyName = DefaultMapKeyName
+	}
+	if valueName == "" {
+		valueName = DefaultMapValueName
+	}
+	c.mapKeyName = keyName
+	c.mapValueName = valueName
+}
+
+// isMapEntryPattern checks if a complex type is a key-value entry: a sequence of
+// exactly a string key element and a single value element, without attributes
+func (c *Converter) isMapEntryPattern(complexType *model.ComplexType) bool {
+	if complexType.Sequence == nil || len(complexType.Sequence.Children) != 2 ||
+		complexType.Choice != nil || len(complexType.Attributes) > 0 || complexType.AnyAttribute != nil {
+		return false
+	}
+
+	key := complexType.Sequence.Children[0].Element
+	value := complexType.Sequence.Children[1].Element
+	if key == nil || value == nil || key.Name != c.mapKeyName || value.Name != c.mapValueName {
+		return false
+	}
+	if key.IsRef() || value.IsRef() || value.HasInlineType() || c.isRepeatedOccurs(value.MaxOccurs) {
+		return false
+	}
+	return c.isStringType(key.Type)
+}
+
+// isStringType checks if a type maps to a proto string, directly or through a
+// restriction of a string type
+func (c *Converter) isStringType(typeName string) bool {
+	if c.typeMapper.IsBuiltInType(typeName) {
+		mapped, err := c.typeMapper.mapXSDTypeDetailed(typeName)
+		return err == nil && mapped.ProtoType == "string" && !mapped.IsRepeated
+	}
+	simpleType := c.findSimpleTypeInSchema(typeName, c.currentSchema)
+	return simpleType != nil && c.isStringBasedEnumeration(simpleType)
+}
+
+// getMapEntryValueType returns the proto value type of a map field for a
+// reference to a key-value entry type, or directly to an ArrayOf wrapper of one.
+// Returns empty string if the type is neither.
+func (c *Converter) getMapEntryValueType(typeName string) string {
+	if typeName == "" || c.typeMapper.IsBuiltInType(typeName) || c.currentSchema == nil {
+		return ""
+	}
+
+	complexType := c.findComplexTypeInSchema(typeName, c.currentSchema)
+	if complexType != nil && c.isArrayOfPattern(complexType) {
+		complexType = c.findComplexTypeInSchema(complexType.Sequence.Elements[0].Type, c.currentSchema)
+	}
+	if complexType == nil || !c.isMapEntryPattern(complexType) {
+		return ""
+	}
+
+	valueType := complexType.Sequence.Children[1].Element.Type
+	if c.typeMapper.IsBuiltInType(valueType) {
+		protoType, _ := c.typeMapper.MapXSDType(valueType)
+		return protoType
+	}
+	if c.isStringBasedEnumerationType(valueType) {
+		return "string"
+	}
+	cleanType := c.typeMapper.CleanTypeName(valueType)
+	if renamedType, exists := c.typeRenameMap[cleanType]; exists {
+		return renamedType
+	}
+	return c.formatTypeName(cleanType)
+}
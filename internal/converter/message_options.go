@@ -0,0 +1,90 @@
+package converter
+
+import "github.com/i-icc/xsd2proto/internal/model"
+
+// AbstractOptionName is the custom message option marking messages of abstract types
+const AbstractOptionName = "(xsd_abstract)"
+
+// AddMessageOption adds an option to the message with the given name in every
+// following conversion, e.g. AddMessageOption("Order", "(my.entity)", "true", false).
+// Nested messages are named with their parents, as in Order.Line. An option with
+// the same key replaces the earlier one.
+func (c *Converter) AddMessageOption(messageName, key, value string, quoted bool) {
+	option := model.IdentOption(key, value)
+	if quoted {
+		option = model.StringOption(key, value)
+	}
+	options := c.messageOptions[messageName]
+	for i := range options {
+		if options[i].Key == key {
+			options[i] = option
+			return
+		}
+	}
+	c.messageOptions[messageName] = append(options, option)
+}
+
+// SetAbstractOptionImport sets the import path of a proto file extending
+// google.protobuf.MessageOptions with a bool xsd_abstract option. When set, the
+// messages of abstract types carry option (xsd_abstract) = true and the file is
+// imported. An empty path only marks them with a comment.
+func (c *Converter) SetAbstractOptionImport(importPath string) {
+	c.abstractOptionImport = importPath
+}
+
+// markAbstract marks a message converted from an abstract type or element
+func (c *Converter) markAbstract(message *model.ProtoMessage) {
+	message.Comment = abstractTypeComment
+	if c.abstractOptionImport != "" {
+		message.Options = append(message.Options, model.IdentOption(AbstractOptionName, "true"))
+	}
+}
+
+// applyMessageOptions adds the options set with AddMessageOption to the messages
+// of the proto file and its nested messages
+func (c *Converter) applyMessageOptions(messages []model.ProtoMessage, scope string) {
+	for i := range messages {
+		message := &messages[i]
+		name := scope + message.Name
+		for _, option := range c.messageOptions[name] {
+			setMessageOption(message, option)
+		}
+		c.applyMessageOptions(message.Messages, name+".")
+	}
+}
+
+// setMessageOption adds an option to a message, replacing one with the same key
+func setMessageOption(message *model.ProtoMessage, option model.ProtoOption) {
+	for i := range message.Options {
+		if message.Options[i].Key == option.Key {
+			message.Options[i] = option
+			return
+		}
+	}
+	message.Options = append(message.Options, option)
+}
+
+// usesAbstractOption reports whether a message of the list or nested in one
+// carries the abstract option
+func usesAbstractOption(messages []model.ProtoMessage) bool {
+	for _, message := range messages {
+		for _, option := range message.Options {
+			if option.Key == AbstractOptionName {
+				return true
+			}
+		}
+		if usesAbstractOption(message.Messages) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneMessageOptions copies the options set with AddMessageOption
+func cloneMessageOptions(messageOptions map[string][]model.ProtoOption) map[string][]model.ProtoOption {
+	clone := make(map[string][]model.ProtoOption, len(messageOptions))
+	for name, options := range messageOptions {
+		clone[name] = append([]model.ProtoOption{}, options...)
+	}
+	return clone
+}
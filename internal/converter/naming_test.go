@@ -0,0 +1,51 @@
+package converter
+
+import "testing"
+
+var namingCases = []struct {
+	input  string
+	snake  string
+	pascal string
+	camel  string
+}{
+	{"firstName", "first_name", "FirstName", "firstName"},
+	{"FirstName", "first_name", "FirstName", "firstName"},
+	{"first_name", "first_name", "FirstName", "firstName"},
+	{"first-name", "first_name", "FirstName", "firstName"},
+	{"person.age", "person_age", "PersonAge", "personAge"},
+	{"XMLParser", "xml_parser", "XmlParser", "xmlParser"},
+	{"xml_parser", "xml_parser", "XmlParser", "xmlParser"},
+	{"getUserID", "get_user_id", "GetUserId", "getUserId"},
+	{"HTTPSEndpoint", "https_endpoint", "HttpsEndpoint", "httpsEndpoint"},
+	{"HTTP2Status", "http2_status", "Http2Status", "http2Status"},
+	{"ID", "id", "Id", "id"},
+	{"id", "id", "Id", "id"},
+	{"URL", "url", "Url", "url"},
+	{"userIDNumber", "user_id_number", "UserIdNumber", "userIdNumber"},
+	{"parseXMLDocument", "parse_xml_document", "ParseXmlDocument", "parseXmlDocument"},
+	{"address1", "address1", "Address1", "address1"},
+	{"line2Text", "line2_text", "Line2Text", "line2Text"},
+	{"ISO8601Date", "iso8601_date", "Iso8601Date", "iso8601Date"},
+	{"version2", "version2", "Version2", "version2"},
+	{"A", "a", "A", "a"},
+	{"ABTest", "ab_test", "AbTest", "abTest"},
+	{"already_snake_case", "already_snake_case", "AlreadySnakeCase", "alreadySnakeCase"},
+	{"MixedCase_with-Separators", "mixed_case_with_separators", "MixedCaseWithSeparators", "mixedCaseWithSeparators"},
+	{"simpleField", "simple_field", "SimpleField", "simpleField"},
+}
+
+// TestNamingConversions tests acronym, digit and separator handling of the name conversions
+func TestNamingConversions(t *testing.T) {
+	c := New()
+	for _, tt := range namingCases {
+		if got := c.toSnakeCase(tt.input); got != tt.snake {
+			t.Errorf("toSnakeCase(%q) = %q, expected %q", tt.input, got, tt.snake)
+		}
+		if got := c.toPascalCase(tt.input); got != tt.pascal {
+			t.Errorf("toPascalCase(%q) = %q, expected %q", tt.input, got, tt.pascal)
+		}
+		if got := c.toCamelCase(tt.input); got != tt.camel {
+			t.Errorf("toCamelCase(%q) = %q, expected %q", tt.input, got, tt.camel)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package converter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// openAPIFormats maps scalar proto types to OpenAPI types and formats
+var openAPIFormats = map[string][2]string{
+	"string":                    {"string", ""},
+	"bool":                      {"boolean", ""},
+	"bytes":                     {"string", "byte"},
+	"int32":                     {"integer", "int32"},
+	"sint32":                    {"integer", "int32"},
+	"uint32":                    {"integer", "int64"},
+	"int64":                     {"integer", "int64"},
+	"uint64":                    {"integer", "int64"},
+	"float":                     {"number", "float"},
+	"double":                    {"number", "double"},
+	"google.protobuf.Timestamp": {"string", "date-time"},
+	"google.protobuf.Duration":  {"string", "duration"},
+}
+
+// GenerateOpenAPIExtensions renders an OpenAPI 3.0 document describing the XSD
+// origin of the converted types. Every message and named simple type becomes a
+// component schema annotated with x-xsd-type, x-xsd-namespace and, for simple
+// types, x-xsd-restriction. It must be called after Convert on the same schema so
+// that component names match the generated messages.
+func (c *Converter) GenerateOpenAPIExtensions(schema *model.Schema, title, version string) string {
+	w := &yamlWriter{}
+	w.line(0, "openapi: 3.0.3")
+	w.line(0, "info:")
+	w.line(1, "title: "+strconv.Quote(title))
+	w.line(1, "version: "+strconv.Quote(version))
+	w.line(0, "paths: {}")
+	w.line(0, "components:")
+	w.line(1, "schemas:")
+
+	c.currentSchema = schema
+	for _, s := range c.collectSchemas([]*model.Schema{schema}, make(map[*model.Schema]bool)) {
+		for i := range s.SimpleTypes {
+			c.writeSimpleTypeSchema(w, &s.SimpleTypes[i], s.TargetNamespace)
+		}
+		for i := range s.ComplexTypes {
+			complexType := &s.ComplexTypes[i]
+			if c.isArrayOfPattern(complexType) || c.isMapEntryPattern(complexType) {
+				continue
+			}
+			c.writeComplexTypeSchema(w, c.componentName(complexType.Name), complexType.Name, complexType, complexType.Annotation, s.TargetNamespace)
+		}
+		for _, element := range s.Elements {
+			if element.HasInlineType() {
+				c.writeComplexTypeSchema(w, c.componentName(element.Name), element.Name, element.ComplexType, element.Annotation, s.TargetNamespace)
+			}
+		}
+	}
+
+	return w.String()
+}
+
+// componentName returns the name a type was emitted as
+func (c *Converter) componentName(xsdName string) string {
+	if name, exists := c.typeRenameMap[xsdName]; exists {
+		return name
+	}
+	return c.formatMessageName(xsdName)
+}
+
+func (c *Converter) writeComplexTypeSchema(w *yamlWriter, name, xsdName string, complexType *model.ComplexType, annotation *model.Annotation, namespace string) {
+	w.line(2, name+":")
+	w.line(3, "type: object")
+	writeDescription(w, 3, annotation)
+	w.line(3, "x-xsd-type: "+strconv.Quote(xsdName))
+	w.line(3, "x-xsd-namespace: "+strconv.Quote(namespace))
+
+	// A derived type includes the properties of its base
+	if extension := complexType.Extension(); extension != nil {
+		base := c.componentName(c.typeMapper.CleanTypeName(extension.Base))
+		w.line(3, "allOf:")
+		w.line(4, "- $ref: "+strconv.Quote("#/components/schemas/"+base))
+	}
+	complexType = complexType.OwnContent()
+
+	var elements []model.Element
+	if complexType.Sequence != nil {
+		elements = append(elements, sequenceElements(complexType.Sequence)...)
+	}
+	if complexType.Choice != nil {
+		elements = append(elements, choiceElements(complexType.Choice)...)
+	}
+	if len(elements) == 0 && len(complexType.Attributes) == 0 {
+		return
+	}
+
+	w.line(3, "properties:")
+	for _, element := range elements {
+		if element.IsRef() {
+			element = *c.resolveElementRef(&element)
+		}
+		w.line(4, c.formatFieldName(element.Name)+":")
+		c.writePropertyType(w, element.Type, c.isRepeatedOccurs(element.MaxOccurs))
+		writeDescription(w, 5, element.Annotation)
+		w.line(5, "x-xsd-type: "+strconv.Quote(element.Type))
+	}
+	for _, attribute := range complexType.Attributes {
+		w.line(4, c.formatFieldName(attribute.Name)+":")
+		c.writePropertyType(w, attribute.Type, false)
+		writeDescription(w, 5, attribute.Annotation)
+		w.line(5, "x-xsd-type: "+strconv.Quote(attribute.Type))
+		w.line(5, "x-xsd-attribute: true")
+	}
+}
+
+// writePropertyType writes the type of a property, referring to the component of
+// a named XSD type and wrapping the references in allOf so extensions may follow
+func (c *Converter) writePropertyType(w *yamlWriter, xsdType string, repeated bool) {
+	level := 5
+	if repeated {
+		w.line(level, "type: array")
+		w.line(level, "items:")
+		level++
+	}
+
+	if xsdType != "" && !c.typeMapper.IsBuiltInType(xsdType) {
+		ref := strconv.Quote("#/components/schemas/" + c.componentName(c.typeMapper.CleanTypeName(xsdType)))
+		if repeated {
+			w.line(level, "$ref: "+ref)
+			return
+		}
+		w.line(level, "allOf:")
+		w.line(level+1, "- $ref: "+ref)
+		return
+	}
+
+	protoType, _ := c.typeMapper.MapXSDType(xsdType)
+	format, known := openAPIFormats[protoType]
+	if !known {
+		format = openAPIFormats["string"]
+	}
+	w.line(level, "type: "+format[0])
+	if format[1] != "" {
+		w.line(level, "format: "+format[1])
+	}
+}
+
+func (c *Converter) writeSimpleTypeSchema(w *yamlWriter, simpleType *model.SimpleType, namespace string) {
+	restriction := simpleType.Restriction
+	name := c.componentName(simpleType.Name)
+
+	w.line(2, name+":")
+	openAPIType := "string"
+	if restriction != nil {
+		protoType, _ := c.typeMapper.MapXSDType(restriction.Base)
+		if format, known := openAPIFormats[protoType]; known {
+			openAPIType = format[0]
+		}
+	}
+	w.line(3, "type: "+openAPIType)
+	writeDescription(w, 3, simpleType.Annotation)
+	w.line(3, "x-xsd-type: "+strconv.Quote(simpleType.Name))
+	w.line(3, "x-xsd-namespace: "+strconv.Quote(namespace))
+	if restriction == nil {
+		return
+	}
+
+	if len(restriction.Enumerations) > 0 {
+		w.line(3, "enum:")
+		for _, enumeration := range restriction.Enumerations {
+			w.line(4, "- "+openAPIEnumValue(enumeration.Value, openAPIType))
+		}
+	}
+
+	w.line(3, "x-xsd-restriction:")
+	w.line(4, "base: "+strconv.Quote(restriction.Base))
+	if restriction.MinLength != nil {
+		w.line(4, fmt.Sprintf("minLength: %d", restriction.MinLength.Value))
+	}
+	if restriction.MaxLength != nil {
+		w.line(4, fmt.Sprintf("maxLength: %d", restriction.MaxLength.Value))
+	}
+	if restriction.Pattern != nil {
+		w.line(4, "pattern: "+strconv.Quote(restriction.Pattern.Value))
+	}
+
+	var described []model.Enumeration
+	for _, enumeration := range restriction.Enumerations {
+		if documentation(enumeration.Annotation) != "" {
+			described = append(described, enumeration)
+		}
+	}
+	if len(described) > 0 {
+		w.line(3, "x-xsd-enum-descriptions:")
+		for _, enumeration := range described {
+			w.line(4, strconv.Quote(enumeration.Value)+": "+strconv.Quote(documentation(enumeration.Annotation)))
+		}
+	}
+}
+
+// openAPIEnumValue renders an enumeration value, unquoted for numeric types
+func openAPIEnumValue(value, openAPIType string) string {
+	if openAPIType == "integer" || openAPIType == "number" {
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return value
+		}
+	}
+	return strconv.Quote(value)
+}
+
+func writeDescription(w *yamlWriter, level int, annotation *model.Annotation) {
+	if doc := documentation(annotation); doc != "" {
+		w.line(level, "description: "+strconv.Quote(doc))
+	}
+}
+
+// documentation returns the documentation of an annotation with whitespace collapsed
+func documentation(annotation *model.Annotation) string {
+	if annotation == nil {
+		return ""
+	}
+	var parts []string
+	for _, doc := range annotation.Documentation {
+		if text := strings.Join(strings.Fields(doc.Content), " "); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// yamlWriter builds a YAML document line by line with two-space indentation
+type yamlWriter struct {
+	strings.Builder
+}
+
+func (w *yamlWriter) line(level int, text string) {
+	w.WriteString(strings.Repeat("  ", level))
+	w.WriteString(text)
+	w.WriteString("\n")
+}
@@ -0,0 +1,79 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// optionalGroupSuffix is appended to the parent message name to name the message
+// holding the elements of an optional sequence
+const optionalGroupSuffix = "Group"
+
+// SetInlineOptionalGroups sets whether the elements of a sequence with
+// minOccurs="0" stay fields of the parent message, each optional, instead of
+// moving into a <Parent>Group message referenced by one optional field
+func (c *Converter) SetInlineOptionalGroups(inline bool) {
+	c.inlineOptionalGroups = inline
+}
+
+// convertContentSequence converts the sequence of a complex type. The elements of
+// an optional sequence are present or absent together, so they become a message
+// of their own referenced by a single optional field.
+func (c *Converter) convertContentSequence(sequence *model.Sequence) ([]model.ProtoField, error) {
+	if sequence.MinOccurs != "0" || c.isRepeatedOccurs(sequence.MaxOccurs) {
+		return c.convertSequence(sequence)
+	}
+	if c.inlineOptionalGroups {
+		fields, err := c.convertSequence(sequence)
+		if err != nil {
+			return nil, err
+		}
+		for i := range fields {
+			if fields[i].Label != model.FieldLabelRepeated && !fields[i].IsMap {
+				fields[i].Label = model.FieldLabelOptional
+			}
+		}
+		return fields, nil
+	}
+
+	parent := c.currentMessage
+	group, exists := c.optionalGroups[parent]
+	if !exists {
+		group = c.wrapperMessageName(parent + optionalGroupSuffix)
+		c.optionalGroups[parent] = group
+
+		// The group numbers its fields on its own
+		state := c.saveMessageState()
+		c.startMessageFieldNumbers(group)
+		c.oneofCounter = 0
+		c.choiceSequenceCounter = 0
+		c.choiceMessages = nil
+
+		err := c.reservePinnedFieldNumbers(messageSequenceElements(sequence))
+		var fields []model.ProtoField
+		if err == nil {
+			fields, err = c.convertSequence(sequence)
+		}
+		message := model.ProtoMessage{Name: group, Fields: fields, Messages: c.choiceMessages}
+		c.restoreMessageState(state)
+		if err != nil {
+			return nil, fmt.Errorf("optional sequence of %s: %w", parent, err)
+		}
+
+		c.traceMessage(&message)
+		c.wrapperMessages = append(c.wrapperMessages, message)
+	}
+
+	name := c.uniqueFieldName(group, "")
+	number, err := c.nextFieldNumber(name)
+	if err != nil {
+		return nil, err
+	}
+	return []model.ProtoField{{
+		Name:   c.formatFieldName(name),
+		Type:   group,
+		Number: number,
+		Label:  model.FieldLabelOptional,
+	}}, nil
+}
@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"sort"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// ReservedField is a field number and name removed from a message
+type ReservedField struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+}
+
+// ReservedMap records the removed fields of every message, keyed by message name
+type ReservedMap map[string][]ReservedField
+
+// SetReservedMap sets the removed fields to mark as reserved. Their numbers are
+// never given to a new field, and both numbers and names are emitted as
+// reserved statements in their message.
+func (c *Converter) SetReservedMap(reservedMap ReservedMap) {
+	c.reservedMap = reservedMap
+}
+
+// reserveRemovedFieldNumbers takes the numbers of the removed fields of the current message
+func (c *Converter) reserveRemovedFieldNumbers() {
+	for _, field := range c.reservedMap[c.currentMessage] {
+		if _, taken := c.usedFieldNumbers[field.Number]; !taken && field.Number > 0 {
+			c.usedFieldNumbers[field.Number] = "reserved " + field.Name
+		}
+	}
+}
+
+// applyReservedFields adds the removed fields of a converted message as reserved
+// numbers and names. Entries still used by a field of the message are skipped,
+// since protoc rejects reserving them.
+func (c *Converter) applyReservedFields(message *model.ProtoMessage) {
+	reserved := c.reservedMap[message.Name]
+	if len(reserved) == 0 {
+		return
+	}
+
+	usedNumbers := make(map[int]bool)
+	usedNames := make(map[string]bool)
+	for _, field := range message.Fields {
+		usedNumbers[field.Number] = true
+		usedNames[field.Name] = true
+	}
+
+	seenNumbers := make(map[int]bool)
+	seenNames := make(map[string]bool)
+	for _, field := range reserved {
+		if field.Number > 0 && !usedNumbers[field.Number] && !seenNumbers[field.Number] {
+			seenNumbers[field.Number] = true
+			message.ReservedNumbers = append(message.ReservedNumbers, field.Number)
+		}
+		if field.Name != "" && !usedNames[field.Name] && !seenNames[field.Name] {
+			seenNames[field.Name] = true
+			message.ReservedNames = append(message.ReservedNames, field.Name)
+		}
+	}
+	sort.Ints(message.ReservedNumbers)
+	sort.Strings(message.ReservedNames)
+}
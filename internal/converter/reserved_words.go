@@ -0,0 +1,33 @@
+package converter
+
+// reservedTypeNames are the proto3 keywords protoc cannot read as the type of a
+// field: the scalar types, the labels, and the keywords starting a statement in
+// a message body. A message or enum with one of these names could not be
+// referenced, so it is renamed. Field names may be any keyword.
+var reservedTypeNames = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true,
+	"uint64": true, "sint32": true, "sint64": true, "fixed32": true, "fixed64": true,
+	"sfixed32": true, "sfixed64": true, "bool": true, "string": true, "bytes": true,
+	"optional": true, "repeated": true, "required": true, "map": true, "group": true,
+	"message": true, "enum": true, "oneof": true, "option": true, "reserved": true,
+	"extensions": true, "extend": true,
+}
+
+// avoidReservedTypeName appends an underscore to message and enum names that
+// protoc would not read as a type
+func (c *Converter) avoidReservedTypeName(name string) string {
+	if !reservedTypeNames[name] {
+		return name
+	}
+	c.warnReservedWord(name, name+"_")
+	return name + "_"
+}
+
+// warnReservedWord reports a renamed type as a warning, once per name and conversion
+func (c *Converter) warnReservedWord(name, renamed string) {
+	if c.reservedWordWarnings[name] {
+		return
+	}
+	c.reservedWordWarnings[name] = true
+	c.warnings.Add("type name %q is a proto keyword, emitting it as %q", name, renamed)
+}
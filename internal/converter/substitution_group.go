@@ -0,0 +1,81 @@
+package converter
+
+import (
+	xsderrors "github.com/i-icc/xsd2proto/internal/errors"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// substitutionGroup is a head element together with the elements that may replace it
+type substitutionGroup struct {
+	head        string
+	messageName string   // Union message holding one oneof option per member
+	members     []string // Names of the elements declaring the head as substitutionGroup
+}
+
+// collectSubstitutionGroups finds the top-level elements that declare a substitution
+// group and reserves a union message name for each group head
+func (c *Converter) collectSubstitutionGroups(schemas []*model.Schema) {
+	clear(c.substitutionGroups)
+	c.substitutionGroupOrder = nil
+	for _, schema := range c.collectSchemas(schemas, make(map[*model.Schema]bool)) {
+		for _, element := range schema.Elements {
+			if element.SubstitutionGroup == "" {
+				continue
+			}
+			head := c.typeMapper.CleanTypeName(element.SubstitutionGroup)
+			group, exists := c.substitutionGroups[head]
+			if !exists {
+				group = &substitutionGroup{head: head}
+				c.substitutionGroups[head] = group
+				c.substitutionGroupOrder = append(c.substitutionGroupOrder, head)
+			}
+			group.members = append(group.members, element.Name)
+		}
+	}
+
+	for _, head := range c.substitutionGroupOrder {
+		group := c.substitutionGroups[head]
+		if group.messageName == "" {
+			group.messageName = c.generateUniqueMessageName(head + "Group")
+		}
+	}
+}
+
+// substitutionGroupMessage returns the union message replacing references to a head element
+func (c *Converter) substitutionGroupMessage(ref string) (string, bool) {
+	group, exists := c.substitutionGroups[c.typeMapper.CleanTypeName(ref)]
+	if !exists {
+		return "", false
+	}
+	return group.messageName, true
+}
+
+// convertSubstitutionGroups creates a union message for each substitution group,
+// holding a oneof value with one field per member element
+func (c *Converter) convertSubstitutionGroups() ([]model.ProtoMessage, error) {
+	var messages []model.ProtoMessage
+groups:
+	for _, head := range c.substitutionGroupOrder {
+		group := c.substitutionGroups[head]
+		message := model.ProtoMessage{Name: group.messageName}
+
+		c.startMessageFieldNumbers(message.Name)
+		for _, member := range group.members {
+			field, err := c.convertElementToField(&model.Element{Ref: member})
+			if err != nil {
+				if err := c.collectTypeError(&xsderrors.ConvertError{TypeName: head, Msg: "failed to convert substitution group", Err: err}); err != nil {
+					return nil, err
+				}
+				continue groups
+			}
+			field.Label = model.FieldLabelOptional
+			field.Oneof = "value"
+			message.Fields = append(message.Fields, *field)
+		}
+
+		c.applyReservedFields(&message)
+		c.traceMessage(&message)
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// Trace actions recorded during conversion
+const (
+	TraceActionMapType     = "map-type"
+	TraceActionResolveType = "resolve-type"
+	TraceActionEmitEnum    = "emit-enum"
+	TraceActionEmitMessage = "emit-message"
+	TraceActionEmitField   = "emit-field"
+)
+
+// TraceEvent describes a single conversion decision
+type TraceEvent struct {
+	Action    string `json:"action"`
+	XSDType   string `json:"xsdType,omitempty"`
+	ProtoType string `json:"protoType,omitempty"`
+	Enum      string `json:"enum,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Field     string `json:"field,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Number    int    `json:"number,omitempty"`
+}
+
+// SetTraceWriter makes the converter write every conversion decision to w as
+// JSON Lines. A nil writer disables tracing.
+func (c *Converter) SetTraceWriter(w io.Writer) {
+	if w == nil {
+		c.tracer = nil
+		return
+	}
+	c.tracer = json.NewEncoder(w)
+}
+
+// trace records a conversion decision when tracing is enabled
+func (c *Converter) trace(event TraceEvent) {
+	if c.tracer == nil {
+		return
+	}
+	// Tracing is a debugging aid and never fails the conversion
+	_ = c.tracer.Encode(event)
+}
+
+// traceMessage records a converted message and each of its fields
+func (c *Converter) traceMessage(message *model.ProtoMessage) {
+	if c.tracer == nil {
+		return
+	}
+	c.trace(TraceEvent{Action: TraceActionEmitMessage, Message: message.Name})
+	for _, field := range message.Fields {
+		c.trace(TraceEvent{
+			Action:  TraceActionEmitField,
+			Message: message.Name,
+			Field:   field.Name,
+			Type:    field.Type,
+			Number:  field.Number,
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// Type graph node kinds
+const (
+	TypeNodeMessage    = "message"
+	TypeNodeEnum       = "enum"
+	TypeNodeSimpleType = "simpleType"
+)
+
+// TypeNode is a named type of the schema, identified by its proto name
+type TypeNode struct {
+	Name    string
+	XSDName string
+	Kind    string
+}
+
+// TypeEdge is a reference from a field of one type to another type
+type TypeEdge struct {
+	From  string
+	To    string
+	Field string
+}
+
+// TypeGraph holds the dependencies between the types of a schema
+type TypeGraph struct {
+	Nodes []TypeNode
+	Edges []TypeEdge
+}
+
+// BuildTypeDependencyGraph collects every type of the schema and its imports
+// together with the field references between them
+func BuildTypeDependencyGraph(schema *model.Schema) *TypeGraph {
+	c := New()
+	graph := &TypeGraph{}
+	known := make(map[string]bool)
+
+	schemas := c.collectSchemas([]*model.Schema{schema}, make(map[*model.Schema]bool))
+	addNode := func(xsdName, kind string) {
+		name := c.formatMessageName(xsdName)
+		if !known[name] {
+			known[name] = true
+			graph.Nodes = append(graph.Nodes, TypeNode{Name: name, XSDName: xsdName, Kind: kind})
+		}
+	}
+
+	for _, s := range schemas {
+		for _, simpleType := range s.SimpleTypes {
+			kind := TypeNodeSimpleType
+			if simpleType.Restriction != nil && len(simpleType.Restriction.Enumerations) > 0 && !c.isStringBasedEnumeration(&simpleType) {
+				kind = TypeNodeEnum
+			}
+			addNode(simpleType.Name, kind)
+		}
+		for _, complexType := range s.ComplexTypes {
+			addNode(complexType.Name, TypeNodeMessage)
+		}
+		for _, element := range s.Elements {
+			if element.HasInlineType() {
+				addNode(element.Name, TypeNodeMessage)
+			}
+		}
+	}
+
+	for _, s := range schemas {
+		c.currentSchema = s
+		for i := range s.ComplexTypes {
+			c.collectTypeEdges(graph, known, c.formatMessageName(s.ComplexTypes[i].Name), &s.ComplexTypes[i])
+		}
+		for _, element := range s.Elements {
+			if element.HasInlineType() {
+				c.collectTypeEdges(graph, known, c.formatMessageName(element.Name), element.ComplexType)
+			}
+		}
+	}
+
+	return graph
+}
+
+// collectTypeEdges adds an edge for every field of the complex type that refers to a known type
+func (c *Converter) collectTypeEdges(graph *TypeGraph, known map[string]bool, from string, complexType *model.ComplexType) {
+	addEdge := func(fieldName, typeName string) {
+		if typeName == "" || c.typeMapper.IsBuiltInType(typeName) {
+			return
+		}
+		to := c.formatMessageName(c.typeMapper.CleanTypeName(typeName))
+		if known[to] {
+			graph.Edges = append(graph.Edges, TypeEdge{From: from, To: to, Field: c.formatFieldName(fieldName)})
+		}
+	}
+
+	// A derived type depends on its base, whose fields it inherits
+	if extension := complexType.Extension(); extension != nil {
+		addEdge("base", extension.Base)
+	}
+	complexType = complexType.OwnContent()
+
+	var elements []model.Element
+	if complexType.Sequence != nil {
+		elements = append(elements, sequenceElements(complexType.Sequence)...)
+	}
+	if complexType.Choice != nil {
+		elements = append(elements, choiceElements(complexType.Choice)...)
+	}
+
+	for _, element := range elements {
+		if element.IsRef() {
+			element = *c.resolveElementRef(&element)
+		}
+		if element.HasInlineType() {
+			nested := c.formatMessageName(element.Name)
+			if !known[nested] {
+				known[nested] = true
+				graph.Nodes = append(graph.Nodes, TypeNode{Name: nested, XSDName: element.Name, Kind: TypeNodeMessage})
+			}
+			graph.Edges = append(graph.Edges, TypeEdge{From: from, To: nested, Field: c.formatFieldName(element.Name)})
+			c.collectTypeEdges(graph, known, nested, element.ComplexType)
+			continue
+		}
+		addEdge(element.Name, element.Type)
+	}
+
+	for _, attribute := range complexType.Attributes {
+		addEdge(attribute.Name, attribute.Type)
+	}
+}
+
+// sequenceElements returns the elements of a sequence, including those of nested
+// sequences and choices, in document order
+func sequenceElements(sequence *model.Sequence) []model.Element {
+	var elements []model.Element
+	for _, child := range sequence.Children {
+		switch {
+		case child.Element != nil:
+			elements = append(elements, *child.Element)
+		case child.Choice != nil:
+			elements = append(elements, choiceElements(child.Choice)...)
+		case child.Sequence != nil:
+			elements = append(elements, sequenceElements(child.Sequence)...)
+		}
+	}
+	return elements
+}
+
+// choiceElements returns the elements of a choice, including those of its sequences
+func choiceElements(choice *model.Choice) []model.Element {
+	elements := append([]model.Element{}, choice.Elements...)
+	for i := range choice.Sequences {
+		elements = append(elements, sequenceElements(&choice.Sequences[i])...)
+	}
+	return elements
+}
+
+// DOT renders the graph in Graphviz DOT format, with edges labelled by field name
+func (g *TypeGraph) DOT() string {
+	var content strings.Builder
+
+	content.WriteString("digraph types {\n")
+	content.WriteString("  rankdir=LR;\n")
+	content.WriteString("  node [shape=box];\n\n")
+
+	for _, node := range g.Nodes {
+		shape := "box"
+		if node.Kind != TypeNodeMessage {
+			shape = "ellipse"
+		}
+		content.WriteString(fmt.Sprintf("  %q [label=%q, shape=%s];\n", node.Name, node.Name, shape))
+	}
+
+	if len(g.Edges) > 0 {
+		content.WriteString("\n")
+		for _, edge := range g.Edges {
+			content.WriteString(fmt.Sprintf("  %q -> %q [label=%q];\n", edge.From, edge.To, edge.Field))
+		}
+	}
+
+	content.WriteString("}\n")
+	return content.String()
+}
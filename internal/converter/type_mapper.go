@@ -1,11 +1,16 @@
 package converter
 
 import (
+	"maps"
+	"sort"
 	"strings"
 )
 
 type TypeMapper struct {
 	customMappings map[string]string
+	exactIntegers  bool // Map small integer types to the closest proto types
+	timestampDate  bool // Map xs:date to google.protobuf.Timestamp instead of string
+	timestampTime  bool // Map xs:time to google.protobuf.Timestamp instead of string
 }
 
 func NewTypeMapper() *TypeMapper {
@@ -14,42 +19,146 @@ func NewTypeMapper() *TypeMapper {
 	}
 }
 
+// Clone returns a copy of the type mapper with its own custom mappings, so
+// mappings added to one copy do not affect the other
+func (tm *TypeMapper) Clone() *TypeMapper {
+	clone := *tm
+	clone.customMappings = make(map[string]string, len(tm.customMappings))
+	for xsdType, protoType := range tm.customMappings {
+		clone.customMappings[xsdType] = protoType
+	}
+	return &clone
+}
+
+// TypeMapResult is the proto mapping of an XSD type
+type TypeMapResult struct {
+	ProtoType  string
+	IsRepeated bool // The XSD type is a whitespace-separated list, so the field is repeated
+}
+
+// mapXSDTypeDetailed maps an XSD type like MapXSDType and reports whether it is
+// one of the built-in list types (IDREFS, NMTOKENS, ENTITIES), whose items become a
+// repeated field
+func (tm *TypeMapper) mapXSDTypeDetailed(xsdType string) (TypeMapResult, error) {
+	protoType, err := tm.MapXSDType(xsdType)
+	if err != nil {
+		return TypeMapResult{}, err
+	}
+
+	cleanType := tm.CleanTypeName(xsdType)
+	_, custom := tm.customMappings[cleanType]
+	switch cleanType {
+	case "IDREFS", "NMTOKENS", "ENTITIES":
+		return TypeMapResult{ProtoType: protoType, IsRepeated: !custom}, nil
+	}
+	return TypeMapResult{ProtoType: protoType}, nil
+}
+
 func (tm *TypeMapper) MapXSDType(xsdType string) (string, error) {
 	cleanType := tm.CleanTypeName(xsdType)
 
 	if protoType, exists := tm.customMappings[cleanType]; exists {
 		return protoType, nil
 	}
-	switch cleanType {
-	case "string", "normalizedString", "token", "NMTOKEN", "Name", "NCName", "ID", "IDREF":
-		return "string", nil
-	case "boolean":
-		return "bool", nil
-	case "int", "integer", "short", "byte", "unsignedByte":
-		return "int32", nil
-	case "long", "unsignedInt":
-		return "int64", nil
-	case "float":
-		return "float", nil
-	case "double", "decimal":
-		return "double", nil
-	case "dateTime", "date", "time":
-		return "google.protobuf.Timestamp", nil
-	case "duration":
-		return "google.protobuf.Duration", nil
-	case "anyURI":
-		return "string", nil
-	case "base64Binary", "hexBinary":
-		return "bytes", nil
-	case "unsignedLong":
-		return "uint64", nil
-	case "unsignedShort":
-		return "uint32", nil
-	default:
-		// Return the cleaned type name as-is, without formatting
-		// The converter will handle the formatting and uniqueness
-		return cleanType, nil
+	if protoType, exists := exactIntegerTypes[cleanType]; exists && tm.exactIntegers {
+		return protoType, nil
 	}
+	if (cleanType == "date" && tm.timestampDate) || (cleanType == "time" && tm.timestampTime) {
+		return timestampType, nil
+	}
+	if protoType, exists := builtInMappings[cleanType]; exists {
+		return protoType, nil
+	}
+	// Return the cleaned type name as-is, without formatting
+	// The converter will handle the formatting and uniqueness
+	return cleanType, nil
+}
+
+// timestampType is the well-known type of points in time
+const timestampType = "google.protobuf.Timestamp"
+
+// builtInMappings maps the XSD built-in types to their default proto types
+var builtInMappings = map[string]string{
+	"string":           "string",
+	"normalizedString": "string",
+	"token":            "string",
+	"NMTOKEN":          "string",
+	"Name":             "string",
+	"NCName":           "string",
+	"ID":               "string",
+	"IDREF":            "string",
+	"QName":            "string",
+	"IDREFS":           "string",
+	"NMTOKENS":         "string",
+	"language":         "string",
+	"ENTITIES":         "string",
+	"anyURI":           "string",
+
+	"boolean": "bool",
+
+	"int":                "int32",
+	"integer":            "int32",
+	"short":              "int32",
+	"byte":               "int32",
+	"unsignedByte":       "int32",
+	"negativeInteger":    "int32",
+	"nonPositiveInteger": "int32",
+	"long":               "int64",
+	"unsignedInt":        "int64",
+	"positiveInteger":    "uint32",
+	"nonNegativeInteger": "uint32",
+	"unsignedShort":      "uint32",
+	"unsignedLong":       "uint64",
+
+	"float":   "float",
+	"double":  "double",
+	"decimal": "double",
+
+	"dateTime": timestampType,
+	"duration": "google.protobuf.Duration",
+
+	// A calendar date or a time of day is not a point in time, so unlike
+	// xs:dateTime these keep their lexical form unless mapped to Timestamp
+	"date": "string",
+	"time": "string",
+
+	// No well-known type represents a partial date
+	"gYear":      "string",
+	"gMonth":     "string",
+	"gDay":       "string",
+	"gYearMonth": "string",
+	"gMonthDay":  "string",
+
+	"base64Binary": "bytes",
+	"hexBinary":    "bytes",
+}
+
+// GetBuiltInMappings returns the default proto types of the XSD built-in types
+func (tm *TypeMapper) GetBuiltInMappings() map[string]string {
+	return maps.Clone(builtInMappings)
+}
+
+// GetCustomMappings returns the mappings added with AddCustomMapping
+func (tm *TypeMapper) GetCustomMappings() map[string]string {
+	return maps.Clone(tm.customMappings)
+}
+
+// GetAllMappings returns the mappings MapXSDType applies: the built-in ones,
+// overridden by the exact integer and timestamp types when enabled and by the
+// custom mappings
+func (tm *TypeMapper) GetAllMappings() map[string]string {
+	mappings := tm.GetBuiltInMappings()
+	if tm.exactIntegers {
+		maps.Copy(mappings, exactIntegerTypes)
+	}
+	if tm.timestampDate {
+		mappings["date"] = timestampType
+	}
+	if tm.timestampTime {
+		mappings["time"] = timestampType
+	}
+	maps.Copy(mappings, tm.customMappings)
+	return mappings
 }
 
 func (tm *TypeMapper) AddCustomMapping(xsdType, protoType string) {
@@ -92,43 +201,78 @@ func (tm *TypeMapper) toPascalCase(s string) string {
 }
 
 func (tm *TypeMapper) splitCamelCase(s string) []string {
-	if len(s) == 0 {
-		return []string{}
-	}
+	return splitWords(s)
+}
 
-	var parts []string
-	var current strings.Builder
+func (tm *TypeMapper) IsBuiltInType(typeName string) bool {
+	cleanType := tm.CleanTypeName(typeName)
+	_, exists := builtInMappings[cleanType]
+	return exists
+}
 
-	for i, r := range s {
-		if i > 0 && r >= 'A' && r <= 'Z' {
-			if current.Len() > 0 {
-				parts = append(parts, current.String())
-				current.Reset()
-			}
-		}
-		current.WriteRune(r)
+// IsDatePartialType reports whether the type is one of the partial date types
+// (gYear, gMonth, ...), which are mapped to string
+func (tm *TypeMapper) IsDatePartialType(typeName string) bool {
+	switch tm.CleanTypeName(typeName) {
+	case "gYear", "gMonth", "gDay", "gYearMonth", "gMonthDay":
+		return true
 	}
+	return false
+}
 
-	if current.Len() > 0 {
-		parts = append(parts, current.String())
+// dateTimeComment returns the comment of fields of xs:date and xs:time mapped to
+// string, naming the XSD type, and "" for every other type
+func (tm *TypeMapper) dateTimeComment(typeName string) string {
+	cleanType := tm.CleanTypeName(typeName)
+	if _, custom := tm.customMappings[cleanType]; custom {
+		return ""
 	}
-
-	return parts
+	switch {
+	case cleanType == "date" && !tm.timestampDate:
+		return "XSD date"
+	case cleanType == "time" && !tm.timestampTime:
+		return "XSD time"
+	}
+	return ""
 }
 
-func (tm *TypeMapper) IsBuiltInType(typeName string) bool {
-	cleanType := tm.CleanTypeName(typeName)
-	builtInTypes := map[string]bool{
-		"string": true, "normalizedString": true, "token": true, "NMTOKEN": true,
-		"Name": true, "NCName": true, "ID": true, "IDREF": true,
-		"boolean": true,
-		"int":     true, "integer": true, "short": true, "byte": true, "unsignedByte": true,
-		"long": true, "unsignedInt": true, "unsignedLong": true, "unsignedShort": true,
-		"float": true, "double": true, "decimal": true,
-		"dateTime": true, "date": true, "time": true, "duration": true,
-		"anyURI": true, "base64Binary": true, "hexBinary": true,
+// IsSmallIntegerType reports whether the type is one of the integer types narrower
+// than 32 bits (byte, unsignedByte, short, unsignedShort)
+func (tm *TypeMapper) IsSmallIntegerType(typeName string) bool {
+	switch tm.CleanTypeName(typeName) {
+	case "byte", "unsignedByte", "short", "unsignedShort":
+		return true
 	}
-	return builtInTypes[cleanType]
+	return false
+}
+
+// exactIntegerTypes maps signed small integers to zigzag-encoded sint32, which is
+// smaller on the wire for negative values, and unsigned ones to uint32
+var exactIntegerTypes = map[string]string{
+	"byte":         "sint32",
+	"short":        "sint32",
+	"unsignedByte": "uint32",
+}
+
+// wrapperTypes maps scalar proto types to their well-known wrapper messages
+var wrapperTypes = map[string]string{
+	"string": "google.protobuf.StringValue",
+	"bool":   "google.protobuf.BoolValue",
+	"bytes":  "google.protobuf.BytesValue",
+	"int32":  "google.protobuf.Int32Value",
+	"sint32": "google.protobuf.Int32Value",
+	"int64":  "google.protobuf.Int64Value",
+	"uint32": "google.protobuf.UInt32Value",
+	"uint64": "google.protobuf.UInt64Value",
+	"float":  "google.protobuf.FloatValue",
+	"double": "google.protobuf.DoubleValue",
+}
+
+// WrapperType returns the wrapper message for a scalar proto type, so that a
+// null value can be told apart from the default value
+func (tm *TypeMapper) WrapperType(protoType string) (string, bool) {
+	wrapper, ok := wrapperTypes[protoType]
+	return wrapper, ok
 }
 
 func (tm *TypeMapper) GetRequiredImports(mappedTypes []string) []string {
@@ -136,7 +280,7 @@ func (tm *TypeMapper) GetRequiredImports(mappedTypes []string) []string {
 
 	for _, protoType := range mappedTypes {
 		switch protoType {
-		case "google.protobuf.Timestamp":
+		case timestampType:
 			imports["google/protobuf/timestamp.proto"] = true
 		case "google.protobuf.Duration":
 			imports["google/protobuf/duration.proto"] = true
@@ -152,6 +296,11 @@ func (tm *TypeMapper) GetRequiredImports(mappedTypes []string) []string {
 			imports["google/protobuf/struct.proto"] = true
 		case "google.protobuf.FieldMask":
 			imports["google/protobuf/field_mask.proto"] = true
+		case "google.protobuf.StringValue", "google.protobuf.BoolValue", "google.protobuf.BytesValue",
+			"google.protobuf.Int32Value", "google.protobuf.Int64Value",
+			"google.protobuf.UInt32Value", "google.protobuf.UInt64Value",
+			"google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+			imports["google/protobuf/wrappers.proto"] = true
 		}
 	}
 
@@ -159,6 +308,7 @@ func (tm *TypeMapper) GetRequiredImports(mappedTypes []string) []string {
 	for imp := range imports {
 		result = append(result, imp)
 	}
+	sort.Strings(result)
 
 	return result
 }
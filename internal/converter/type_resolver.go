@@ -0,0 +1,44 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypeResolverFunc resolves a non built-in XSD type at conversion time, for example
+// against a schema registry. It returns the proto type to use and the import path
+// that defines it. Returning an empty protoType leaves the type to the converter.
+type TypeResolverFunc func(namespace, localName string) (protoType string, importPath string, err error)
+
+// SetTypeResolver sets the function consulted for every non built-in type reference
+func (c *Converter) SetTypeResolver(resolver TypeResolverFunc) {
+	c.typeResolver = resolver
+}
+
+// resolveExternalType asks the type resolver for a proto type for xsdType
+// Built-in types and string enumerations are never passed to the resolver, and an
+// empty result means the resolver is unset or declined the type
+func (c *Converter) resolveExternalType(xsdType string) (string, error) {
+	if c.typeResolver == nil || c.typeMapper.IsBuiltInType(xsdType) || c.isStringBasedEnumerationType(xsdType) {
+		return "", nil
+	}
+
+	prefix, localName := "", xsdType
+	if idx := strings.LastIndex(xsdType, ":"); idx != -1 {
+		prefix, localName = xsdType[:idx], xsdType[idx+1:]
+	}
+	namespace := ""
+	if c.sourceSchema != nil {
+		namespace = c.sourceSchema.NamespaceForPrefix(prefix)
+	}
+
+	protoType, importPath, err := c.typeResolver(namespace, localName)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve type %s: %w", xsdType, err)
+	}
+	if protoType != "" && importPath != "" {
+		c.resolvedImports[importPath] = true
+	}
+	c.trace(TraceEvent{Action: TraceActionResolveType, XSDType: xsdType, ProtoType: protoType})
+	return protoType, nil
+}
@@ -0,0 +1,106 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// DefaultUnboundedChoiceSuffix is appended to the parent message name to name the
+// wrapper message of a repeated choice
+const DefaultUnboundedChoiceSuffix = "Item"
+
+// SetUnboundedChoiceSuffix sets the suffix of the wrapper messages holding one
+// occurrence of a repeated choice. An empty suffix keeps the default "Item".
+func (c *Converter) SetUnboundedChoiceSuffix(suffix string) {
+	if suffix == "" {
+		suffix = DefaultUnboundedChoiceSuffix
+	}
+	c.unboundedChoiceSuffix = suffix
+}
+
+// choiceWrapperKey identifies a repeated choice within the message it belongs to
+type choiceWrapperKey struct {
+	parent string
+	choice *model.Choice
+}
+
+// convertUnboundedChoice converts a repeated choice, the content of a complex type
+// or part of a sequence, to a wrapper message with a oneof of the alternatives,
+// named <Parent>Item, and returns the repeated field of the wrapper in the current
+// message. Further repeated choices of the same message get numbered wrappers.
+func (c *Converter) convertUnboundedChoice(choice *model.Choice) (model.ProtoField, error) {
+	parent := c.currentMessage
+	key := choiceWrapperKey{parent: parent, choice: choice}
+	wrapper, exists := c.choiceWrappers[key]
+	if !exists {
+		wrapper = c.wrapperMessageName(parent + c.unboundedChoiceSuffix)
+		c.choiceWrappers[key] = wrapper
+
+		// The wrapper numbers its fields on its own; one occurrence of the
+		// choice is a single alternative, so the copy is not repeated
+		state := c.saveMessageState()
+		c.startMessageFieldNumbers(wrapper)
+		c.oneofCounter = 0
+		c.choiceSequenceCounter = 0
+		c.choiceMessages = nil
+
+		single := *choice
+		single.MinOccurs = ""
+		single.MaxOccurs = ""
+		err := c.reservePinnedFieldNumbers(messageChoiceElements(&single))
+		var fields []model.ProtoField
+		if err == nil {
+			fields, err = c.convertChoice(&single)
+		}
+		message := model.ProtoMessage{Name: wrapper, Fields: fields, Messages: c.choiceMessages}
+		c.restoreMessageState(state)
+		if err != nil {
+			return model.ProtoField{}, fmt.Errorf("repeated choice of %s: %w", parent, err)
+		}
+
+		c.traceMessage(&message)
+		c.wrapperMessages = append(c.wrapperMessages, message)
+	}
+
+	name := c.uniqueFieldName("items", "")
+	number, err := c.nextFieldNumber(name)
+	if err != nil {
+		return model.ProtoField{}, err
+	}
+	return model.ProtoField{
+		Name:   c.formatFieldName(name),
+		Type:   wrapper,
+		Number: number,
+		Label:  model.FieldLabelRepeated,
+	}, nil
+}
+
+// wrapperMessageName returns name, numbered when a message or enum already has it.
+// Unlike type names, wrapper names are not recorded as renames of an XSD type.
+func (c *Converter) wrapperMessageName(name string) string {
+	candidate := name
+	for i := 2; c.usedMessageNames[candidate] || c.usedEnumNames[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	c.usedMessageNames[candidate] = true
+	return candidate
+}
+
+// takeWrapperMessages returns the wrapper messages created since the last call
+func (c *Converter) takeWrapperMessages() []model.ProtoMessage {
+	messages := c.wrapperMessages
+	c.wrapperMessages = nil
+	return messages
+}
+
+// appendWrapperMessages adds the wrapper messages created since the last call to
+// protoFile, skipping those it already has
+func (c *Converter) appendWrapperMessages(protoFile *model.ProtoFile, existingMessages map[string]bool) {
+	for _, message := range c.takeWrapperMessages() {
+		if !existingMessages[message.Name] {
+			protoFile.Messages = append(protoFile.Messages, message)
+			existingMessages[message.Name] = true
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// WarningCollector collects the warnings of a conversion about XSD features
+// that have no proto equivalent and are dropped
+type WarningCollector []string
+
+// Add records a warning, formatted as with fmt.Sprintf
+func (w *WarningCollector) Add(format string, args ...interface{}) {
+	*w = append(*w, fmt.Sprintf(format, args...))
+}
+
+// SetWarningWriter makes the converter print the warnings of each conversion to
+// w when it ends. A nil writer only collects them for GetWarnings.
+func (c *Converter) SetWarningWriter(w io.Writer) {
+	c.warningWriter = w
+}
+
+// GetWarnings returns the warnings of the last conversion
+func (c *Converter) GetWarnings() []string {
+	return append([]string{}, c.warnings...)
+}
+
+// printWarnings writes the collected warnings to the warning writer when set
+func (c *Converter) printWarnings() {
+	if c.warningWriter == nil {
+		return
+	}
+	for _, warning := range c.warnings {
+		fmt.Fprintf(c.warningWriter, "Warning: %s\n", warning)
+	}
+}
+
+// collectDroppedFeatures records a warning for each identity constraint, assertion
+// and block or final restriction in the schemas and their imports
+func (c *Converter) collectDroppedFeatures(schemas []*model.Schema) {
+	c.warnings = nil
+	clear(c.reservedWordWarnings)
+	for _, schema := range c.collectSchemas(schemas, make(map[*model.Schema]bool)) {
+		for i := range schema.SimpleTypes {
+			c.warnSimpleType(&schema.SimpleTypes[i], "simpleType "+schema.SimpleTypes[i].Name)
+		}
+		for i := range schema.ComplexTypes {
+			c.warnComplexType(&schema.ComplexTypes[i], "complexType "+schema.ComplexTypes[i].Name)
+		}
+		for i := range schema.Elements {
+			c.warnElement(&schema.Elements[i])
+		}
+	}
+}
+
+func (c *Converter) warnSimpleType(simpleType *model.SimpleType, where string) {
+	if simpleType.Restriction != nil && len(simpleType.Restriction.Assertions) > 0 {
+		c.warnings.Add("%s: xs:assertion is not converted", where)
+	}
+}
+
+// warnComplexType checks a complex type and the elements of its content; where
+// names the type, or the element declaring it inline, in the warnings
+func (c *Converter) warnComplexType(complexType *model.ComplexType, where string) {
+	if complexType.Block != "" {
+		c.warnings.Add("%s: block=%q is not converted", where, complexType.Block)
+	}
+	if complexType.Final != "" {
+		c.warnings.Add("%s: final=%q is not converted", where, complexType.Final)
+	}
+	if len(complexType.Assertions) > 0 {
+		c.warnings.Add("%s: xs:assert is not converted", where)
+	}
+
+	content := complexType.OwnContent()
+	if content.Sequence != nil {
+		c.warnSequence(content.Sequence)
+	}
+	if content.Choice != nil {
+		c.warnChoice(content.Choice)
+	}
+}
+
+func (c *Converter) warnSequence(sequence *model.Sequence) {
+	for _, child := range sequence.Children {
+		switch {
+		case child.Element != nil:
+			c.warnElement(child.Element)
+		case child.Choice != nil:
+			c.warnChoice(child.Choice)
+		case child.Sequence != nil:
+			c.warnSequence(child.Sequence)
+		}
+	}
+}
+
+func (c *Converter) warnChoice(choice *model.Choice) {
+	for i := range choice.Elements {
+		c.warnElement(&choice.Elements[i])
+	}
+	for i := range choice.Sequences {
+		c.warnSequence(&choice.Sequences[i])
+	}
+}
+
+func (c *Converter) warnElement(element *model.Element) {
+	where := "element " + element.Name
+	if element.IsRef() {
+		where = "element ref " + element.Ref
+	}
+	for _, key := range element.Keys {
+		c.warnings.Add("%s: xs:key %q is not converted", where, key.Name)
+	}
+	for _, keyRef := range element.KeyRefs {
+		c.warnings.Add("%s: xs:keyref %q is not converted", where, keyRef.Name)
+	}
+	for _, unique := range element.Uniques {
+		c.warnings.Add("%s: xs:unique %q is not converted", where, unique.Name)
+	}
+	if element.Block != "" {
+		c.warnings.Add("%s: block=%q is not converted", where, element.Block)
+	}
+	if element.Final != "" {
+		c.warnings.Add("%s: final=%q is not converted", where, element.Final)
+	}
+	if element.SimpleType != nil {
+		c.warnSimpleType(element.SimpleType, where)
+	}
+	if element.ComplexType != nil {
+		c.warnComplexType(element.ComplexType, where)
+	}
+}
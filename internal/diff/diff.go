@@ -0,0 +1,192 @@
+// Package diff compares proto files line by line
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// op is one line of the edit script turning the old lines into the new ones
+type op struct {
+	kind    byte // ' ' for a kept line, '-' for a removed one, '+' for an added one
+	text    string
+	oldLine int // Lines of the old content before this one
+	newLine int // Lines of the new content before this one
+}
+
+// DiffProtoFiles returns the unified diff hunks turning oldContent into
+// newContent, with three lines of context around each change, and whether the
+// contents differ. The hunks carry no file header.
+func DiffProtoFiles(oldContent, newContent string) (string, bool) {
+	if oldContent == newContent {
+		return "", false
+	}
+	ops := editScript(splitLines(oldContent), splitLines(newContent))
+
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change and extend the hunk while the changes after it
+		// are close enough for their context to overlap
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		last := first
+		for i := first + 1; i < len(ops); i++ {
+			if ops[i].kind == ' ' {
+				continue
+			}
+			if i-last-1 > 2*contextLines {
+				break
+			}
+			last = i
+		}
+
+		from := max(first-contextLines, start)
+		to := min(last+contextLines+1, len(ops))
+		writeHunk(&out, ops[from:to])
+		start = to
+	}
+	return out.String(), true
+}
+
+// writeHunk writes a hunk header and the lines of the hunk
+func writeHunk(out *strings.Builder, ops []op) {
+	var oldCount, newCount int
+	for _, o := range ops {
+		if o.kind != '+' {
+			oldCount++
+		}
+		if o.kind != '-' {
+			newCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(ops[0].oldLine, oldCount), hunkRange(ops[0].newLine, newCount))
+	for _, o := range ops {
+		out.WriteByte(o.kind)
+		out.WriteString(o.text)
+		out.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the start and length of a hunk side; an empty side names the
+// line before it, as diff -u does
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// editScript returns a shortest edit script from a to b, found with Myers'
+// algorithm after setting aside the common prefix and suffix
+func editScript(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []op
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{kind: ' ', text: a[i], oldLine: i, newLine: i})
+	}
+	for _, o := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		o.oldLine += prefix
+		o.newLine += prefix
+		ops = append(ops, o)
+	}
+	for i := 0; i < suffix; i++ {
+		oldIndex, newIndex := len(a)-suffix+i, len(b)-suffix+i
+		ops = append(ops, op{kind: ' ', text: a[oldIndex], oldLine: oldIndex, newLine: newIndex})
+	}
+	return ops
+}
+
+// myers returns a shortest edit script from a to b. It keeps the furthest
+// reaching path of each diagonal for every edit count, then walks back from
+// the end to recover the path.
+func myers(a, b []string) []op {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, offset)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrack(a, b []string, trace [][]int, offset int) []op {
+	x, y := len(a), len(b)
+	var reversed []op
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, op{kind: ' ', text: a[x-1], oldLine: x - 1, newLine: y - 1})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, op{kind: '+', text: b[y-1], oldLine: x, newLine: y - 1})
+			} else {
+				reversed = append(reversed, op{kind: '-', text: a[x-1], oldLine: x - 1, newLine: y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	ops := make([]op, len(reversed))
+	for i, o := range reversed {
+		ops[len(reversed)-1-i] = o
+	}
+	return ops
+}
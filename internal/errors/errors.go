@@ -0,0 +1,106 @@
+// Package errors defines the error types reported by the conversion pipeline
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError is an error in an XSD document, located by line and column
+type ParseError struct {
+	File   string // Empty when the document was not read from a file
+	Line   int
+	Column int
+	Msg    string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	location := fmt.Sprintf("%d:%d", e.Line, e.Column)
+	if e.File != "" {
+		location = e.File + ":" + location
+	}
+	return location + ": " + e.Msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ConvertError is an error converting an XSD type to proto
+type ConvertError struct {
+	TypeName string
+	Msg      string
+	Err      error
+}
+
+func (e *ConvertError) Error() string {
+	msg := e.TypeName + ": " + e.Msg
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ConvertError) Unwrap() error {
+	return e.Err
+}
+
+// UnresolvedTypeError is a type reference that no schema defines
+type UnresolvedTypeError struct {
+	TypeName string // The reference as written, e.g. tns:Address
+	UsedIn   string // The declaration making the reference, e.g. complexType Order, element address
+}
+
+func (e *UnresolvedTypeError) Error() string {
+	return "unresolved type " + e.TypeName + " in " + e.UsedIn
+}
+
+// MaxRecursionDepthError is a schema reached through more nested imports,
+// includes and redefines than the parser allows
+type MaxRecursionDepthError struct {
+	Depth int    // Nesting depth of the schema; the file parsed first is at depth 0
+	File  string // The schema that was not parsed
+}
+
+func (e *MaxRecursionDepthError) Error() string {
+	return fmt.Sprintf("maximum recursion depth exceeded: %s is nested %d levels deep", e.File, e.Depth)
+}
+
+// GenerateError is an error rendering the proto file
+type GenerateError struct {
+	Msg string
+	Err error
+}
+
+func (e *GenerateError) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *GenerateError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError holds several independent errors reported together
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "%d errors occurred:", len(e.Errors))
+	for _, err := range e.Errors {
+		msg.WriteString("\n  " + err.Error())
+	}
+	return msg.String()
+}
+
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// versionSegment matches trailing package version segments such as v1 or v2beta1
+var versionSegment = regexp.MustCompile(`^v\d+([a-z]+\d*)?$`)
+
+// GenerateBufScaffold writes a buf.yaml and a buf.gen.yaml into outputDir, which is
+// the module root of the generated proto files. The module name is derived from the
+// proto package; the Go plugin entry uses goPackage as go_package prefix when set.
+// The proto files given are listed, relative to outputDir, in the buf.yaml header.
+func GenerateBufScaffold(outputDir, protoPackage, goPackage string, protoFiles ...string) error {
+	org, module := bufModuleName(protoPackage, outputDir)
+
+	var bufYAML strings.Builder
+	bufYAML.WriteString("# Generated by xsd2proto. This directory is the module root of:\n")
+	for _, protoFile := range protoFiles {
+		bufYAML.WriteString("#   " + filepath.ToSlash(protoFile) + "\n")
+	}
+	bufYAML.WriteString("version: v1\n")
+	bufYAML.WriteString(fmt.Sprintf("name: buf.build/%s/%s\n", org, module))
+	bufYAML.WriteString("lint:\n  use:\n    - DEFAULT\n")
+	bufYAML.WriteString("breaking:\n  use:\n    - FILE\n")
+
+	var bufGenYAML strings.Builder
+	bufGenYAML.WriteString("version: v1\n")
+	if goPackage != "" {
+		bufGenYAML.WriteString("managed:\n  enabled: true\n  go_package_prefix:\n")
+		bufGenYAML.WriteString(fmt.Sprintf("    default: %s\n", goPackage))
+	}
+	bufGenYAML.WriteString("plugins:\n")
+	bufGenYAML.WriteString("  - plugin: go\n    out: gen/go\n    opt:\n      - paths=source_relative\n")
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create buf module directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "buf.yaml"), []byte(bufYAML.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write buf.yaml: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "buf.gen.yaml"), []byte(bufGenYAML.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write buf.gen.yaml: %w", err)
+	}
+	return nil
+}
+
+// bufModuleName derives the owner and repository of a BSR module name from the
+// last two non-version segments of the proto package, e.g. example/people for
+// com.example.people.v1. Without a package the directory name is used for both.
+func bufModuleName(protoPackage, outputDir string) (string, string) {
+	var segments []string
+	for _, segment := range strings.Split(protoPackage, ".") {
+		if segment != "" && !versionSegment.MatchString(segment) {
+			segments = append(segments, strings.ToLower(segment))
+		}
+	}
+
+	switch len(segments) {
+	case 0:
+		name, err := filepath.Abs(outputDir)
+		if err != nil {
+			name = outputDir
+		}
+		name = strings.ToLower(filepath.Base(name))
+		return name, name
+	case 1:
+		return segments[0], segments[0]
+	default:
+		return segments[len(segments)-2], segments[len(segments)-1]
+	}
+}
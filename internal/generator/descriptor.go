@@ -0,0 +1,339 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// scalarFieldTypes maps proto scalar type names to descriptor field types
+var scalarFieldTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"double":   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"float":    descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+	"int32":    descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"int64":    descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"uint32":   descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	"uint64":   descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"sint32":   descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+	"sint64":   descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+	"fixed32":  descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+	"fixed64":  descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+	"sfixed32": descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+	"sfixed64": descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+	"bool":     descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"string":   descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bytes":    descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+}
+
+// GenerateDescriptor builds the FileDescriptorProto of a proto file, as protoc
+// would for the generated text. Field types are resolved to fully qualified
+// names; types the file does not define are assumed to be messages of the
+// package, except qualified ones such as google.protobuf.Timestamp. The name of
+// the descriptor is left for the caller to set. Field options given as raw text,
+// like validation rules, are extensions and are not included, and neither are
+// the services and extensions of RawDeclarations. opts are those of the
+// Generator writing the proto text, so that labels set by WithProto3Optional
+// match it.
+func GenerateDescriptor(protoFile *model.ProtoFile, opts ...Option) (*descriptorpb.FileDescriptorProto, error) {
+	if errs := protoFile.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid proto file: %w", errs[0])
+	}
+
+	g := New(opts...)
+	g.enumNames = make(map[string]bool)
+	collectEnumNames(protoFile.Enums, protoFile.Messages, g.enumNames)
+	d := &descriptorBuilder{
+		pkg:       protoFile.Package,
+		types:     make(map[string]bool),
+		generator: g,
+	}
+	d.collectTypes(d.scope(""), protoFile.Messages, protoFile.Enums)
+
+	options, err := fileOptions(protoFile.Options)
+	if err != nil {
+		return nil, err
+	}
+	file := &descriptorpb.FileDescriptorProto{
+		Syntax:     proto.String("proto3"),
+		Dependency: append([]string(nil), protoFile.Imports...),
+		Options:    options,
+	}
+	if protoFile.Package != "" {
+		file.Package = proto.String(protoFile.Package)
+	}
+	for i := range protoFile.Enums {
+		file.EnumType = append(file.EnumType, enumDescriptor(&protoFile.Enums[i]))
+	}
+	for i := range protoFile.Messages {
+		file.MessageType = append(file.MessageType, d.messageDescriptor(&protoFile.Messages[i], d.scope("")))
+	}
+	return file, nil
+}
+
+// descriptorBuilder resolves type references while building descriptors
+type descriptorBuilder struct {
+	pkg       string
+	types     map[string]bool // Fully qualified names of messages (false) and enums (true)
+	generator *Generator      // Generator whose labels the field descriptors follow
+}
+
+// scope returns the fully qualified name of a scope relative to the package
+func (d *descriptorBuilder) scope(name string) string {
+	scope := ""
+	if d.pkg != "" {
+		scope = "." + d.pkg
+	}
+	if name != "" {
+		scope += "." + name
+	}
+	return scope
+}
+
+func (d *descriptorBuilder) collectTypes(scope string, messages []model.ProtoMessage, enums []model.ProtoEnum) {
+	for _, enum := range enums {
+		d.types[scope+"."+enum.Name] = true
+	}
+	for _, message := range messages {
+		d.types[scope+"."+message.Name] = false
+		d.collectTypes(scope+"."+message.Name, message.Messages, message.Enums)
+	}
+}
+
+// resolveType returns the fully qualified name of a type referenced from a scope
+// and whether it is an enum, searching the scope and its parents like protoc
+func (d *descriptorBuilder) resolveType(scope, typeName string) (string, bool) {
+	if strings.Contains(typeName, ".") {
+		return "." + typeName, false
+	}
+	for {
+		candidate := scope + "." + typeName
+		if isEnum, ok := d.types[candidate]; ok {
+			return candidate, isEnum
+		}
+		if scope == "" {
+			return d.scope(typeName), false
+		}
+		scope = scope[:strings.LastIndex(scope, ".")]
+	}
+}
+
+func (d *descriptorBuilder) messageDescriptor(message *model.ProtoMessage, parentScope string) *descriptorpb.DescriptorProto {
+	scope := parentScope + "." + message.Name
+	descriptor := &descriptorpb.DescriptorProto{Name: proto.String(message.Name)}
+	if message.IsDeprecated || hasDeprecatedOption(message.Options) {
+		descriptor.Options = &descriptorpb.MessageOptions{Deprecated: proto.Bool(true)}
+	}
+
+	for i := range message.Enums {
+		descriptor.EnumType = append(descriptor.EnumType, enumDescriptor(&message.Enums[i]))
+	}
+	for i := range message.Messages {
+		descriptor.NestedType = append(descriptor.NestedType, d.messageDescriptor(&message.Messages[i], scope))
+	}
+
+	oneofIndex := make(map[string]int32)
+	for _, field := range message.Fields {
+		fieldDescriptor := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(field.Name),
+			Number:   proto.Int32(int32(field.Number)),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			JsonName: proto.String(jsonName(field.Name)),
+		}
+		if field.IsDeprecated {
+			fieldDescriptor.Options = &descriptorpb.FieldOptions{Deprecated: proto.Bool(true)}
+		}
+
+		switch {
+		case field.IsMap:
+			entry := d.mapEntryDescriptor(&field, scope)
+			descriptor.NestedType = append(descriptor.NestedType, entry)
+			fieldDescriptor.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			fieldDescriptor.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+			fieldDescriptor.TypeName = proto.String(scope + "." + entry.GetName())
+			descriptor.Field = append(descriptor.Field, fieldDescriptor)
+			continue
+		case field.Label == model.FieldLabelRepeated:
+			fieldDescriptor.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		}
+		d.setFieldType(fieldDescriptor, scope, field.Type)
+
+		switch {
+		case field.Oneof != "":
+			index, exists := oneofIndex[field.Oneof]
+			if !exists {
+				index = int32(len(descriptor.OneofDecl))
+				oneofIndex[field.Oneof] = index
+				descriptor.OneofDecl = append(descriptor.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(field.Oneof)})
+			}
+			fieldDescriptor.OneofIndex = proto.Int32(index)
+		case field.Label == model.FieldLabelOptional && d.generator.optionalLabel(&field):
+			// proto3 optional fields live in a synthetic oneof declared after the real ones
+			fieldDescriptor.Proto3Optional = proto.Bool(true)
+		}
+		descriptor.Field = append(descriptor.Field, fieldDescriptor)
+	}
+
+	for _, fieldDescriptor := range descriptor.Field {
+		if fieldDescriptor.GetProto3Optional() {
+			fieldDescriptor.OneofIndex = proto.Int32(int32(len(descriptor.OneofDecl)))
+			descriptor.OneofDecl = append(descriptor.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + fieldDescriptor.GetName())})
+		}
+	}
+
+	// Reserved ranges are half-open, so each number covers [number, number+1)
+	for _, number := range message.ReservedNumbers {
+		descriptor.ReservedRange = append(descriptor.ReservedRange, &descriptorpb.DescriptorProto_ReservedRange{
+			Start: proto.Int32(int32(number)),
+			End:   proto.Int32(int32(number) + 1),
+		})
+	}
+	descriptor.ReservedName = append(descriptor.ReservedName, message.ReservedNames...)
+	return descriptor
+}
+
+// setFieldType sets the scalar type, or the message or enum type name, of a field
+func (d *descriptorBuilder) setFieldType(field *descriptorpb.FieldDescriptorProto, scope, typeName string) {
+	if scalar, ok := scalarFieldTypes[typeName]; ok {
+		field.Type = scalar.Enum()
+		return
+	}
+	fullName, isEnum := d.resolveType(scope, typeName)
+	field.TypeName = proto.String(fullName)
+	if isEnum {
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+	} else {
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	}
+}
+
+// mapEntryDescriptor builds the nested <Field>Entry message protoc synthesizes for a map field
+func (d *descriptorBuilder) mapEntryDescriptor(field *model.ProtoField, scope string) *descriptorpb.DescriptorProto {
+	key := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("key"),
+		Number:   proto.Int32(1),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		JsonName: proto.String("key"),
+	}
+	d.setFieldType(key, scope, field.KeyType)
+	value := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("value"),
+		Number:   proto.Int32(2),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		JsonName: proto.String("value"),
+	}
+	d.setFieldType(value, scope, field.Type)
+
+	return &descriptorpb.DescriptorProto{
+		Name:    proto.String(mapEntryName(field.Name)),
+		Field:   []*descriptorpb.FieldDescriptorProto{key, value},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	}
+}
+
+func enumDescriptor(enum *model.ProtoEnum) *descriptorpb.EnumDescriptorProto {
+	descriptor := &descriptorpb.EnumDescriptorProto{Name: proto.String(enum.Name)}
+	if enum.AllowAlias {
+		descriptor.Options = &descriptorpb.EnumOptions{AllowAlias: proto.Bool(true)}
+	}
+	for _, value := range enum.Values {
+		descriptor.Value = append(descriptor.Value, &descriptorpb.EnumValueDescriptorProto{
+			Name:   proto.String(value.Name),
+			Number: proto.Int32(int32(value.Number)),
+		})
+	}
+	return descriptor
+}
+
+// hasDeprecatedOption reports whether the options include deprecated = true.
+// Custom message options are extensions and are not included in descriptors.
+func hasDeprecatedOption(options []model.ProtoOption) bool {
+	for _, option := range options {
+		if option.Key == "deprecated" && option.Value == "true" && !option.Quoted {
+			return true
+		}
+	}
+	return false
+}
+
+// fileOptions converts the file options of descriptor.proto; other options, such
+// as custom ones, are left out
+func fileOptions(options []model.ProtoOption) (*descriptorpb.FileOptions, error) {
+	if len(options) == 0 {
+		return nil, nil
+	}
+	fileOptions := &descriptorpb.FileOptions{}
+	for _, option := range options {
+		value := option.Value
+		switch option.Key {
+		case "go_package":
+			fileOptions.GoPackage = &value
+		case "java_package":
+			fileOptions.JavaPackage = &value
+		case "java_outer_classname":
+			fileOptions.JavaOuterClassname = &value
+		case "csharp_namespace":
+			fileOptions.CsharpNamespace = &value
+		case "objc_class_prefix":
+			fileOptions.ObjcClassPrefix = &value
+		case "php_namespace":
+			fileOptions.PhpNamespace = &value
+		case "ruby_package":
+			fileOptions.RubyPackage = &value
+		case "swift_prefix":
+			fileOptions.SwiftPrefix = &value
+		case "optimize_for":
+			mode, ok := descriptorpb.FileOptions_OptimizeMode_value[value]
+			if !ok {
+				return nil, fmt.Errorf("invalid value %s for option optimize_for", option.Literal())
+			}
+			fileOptions.OptimizeFor = descriptorpb.FileOptions_OptimizeMode(mode).Enum()
+		case "java_multiple_files", "cc_enable_arenas", "deprecated":
+			flag, err := strconv.ParseBool(value)
+			if err != nil || option.Quoted {
+				return nil, fmt.Errorf("invalid value %s for option %s", option.Literal(), option.Key)
+			}
+			switch option.Key {
+			case "java_multiple_files":
+				fileOptions.JavaMultipleFiles = &flag
+			case "cc_enable_arenas":
+				fileOptions.CcEnableArenas = &flag
+			default:
+				fileOptions.Deprecated = &flag
+			}
+		}
+	}
+	return fileOptions, nil
+}
+
+// jsonName returns the lowerCamelCase JSON name protoc derives from a field name
+func jsonName(name string) string {
+	var result strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upper = false
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+// mapEntryName returns the name of the entry message of a map field, e.g.
+// AttributesEntry for attributes or my_map
+func mapEntryName(fieldName string) string {
+	name := jsonName(fieldName)
+	if name == "" {
+		return "Entry"
+	}
+	return strings.ToUpper(name[:1]) + name[1:] + "Entry"
+}
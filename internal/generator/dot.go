@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// GenerateDOT renders a Graphviz DOT graph of the dependencies between the
+// messages and enums of a proto file. Messages and enums are color-coded and
+// edges from repeated fields are drawn dashed.
+func GenerateDOT(protoFile *model.ProtoFile) string {
+	var content strings.Builder
+
+	content.WriteString("digraph proto {\n")
+	content.WriteString("  rankdir=LR;\n")
+	content.WriteString("  node [shape=box, style=filled];\n\n")
+
+	knownTypes := make(map[string]bool)
+	for _, enum := range protoFile.Enums {
+		knownTypes[enum.Name] = true
+		content.WriteString(fmt.Sprintf("  %q [label=%q, shape=ellipse, fillcolor=\"lightyellow\"];\n", enum.Name, enum.Name))
+	}
+	for _, message := range protoFile.Messages {
+		knownTypes[message.Name] = true
+		content.WriteString(fmt.Sprintf("  %q [label=%q, fillcolor=\"lightblue\"];\n", message.Name, message.Name))
+	}
+
+	var edges []string
+	for _, message := range protoFile.Messages {
+		for _, field := range message.Fields {
+			if !knownTypes[field.Type] {
+				continue
+			}
+			attributes := fmt.Sprintf("label=%q", field.Name)
+			if field.Label == model.FieldLabelRepeated {
+				attributes += ", style=dashed, arrowhead=crow"
+			}
+			edges = append(edges, fmt.Sprintf("  %q -> %q [%s];\n", message.Name, field.Type, attributes))
+		}
+	}
+	if len(edges) > 0 {
+		content.WriteString("\n")
+		for _, edge := range edges {
+			content.WriteString(edge)
+		}
+	}
+
+	content.WriteString("}\n")
+	return content.String()
+}
@@ -1,10 +1,14 @@
 package generator
 
 import (
+	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 
+	xsderrors "github.com/i-icc/xsd2proto/internal/errors"
 	"github.com/i-icc/xsd2proto/internal/model"
 )
 
@@ -13,112 +17,206 @@ type Generator struct {
 	indentLevel   int
 	includeHeader bool
 	version       string
+	syntaxFirst   bool   // Emit the syntax line before the header comment
+	indent        string // One level of indentation
+	sortOutput    bool   // Emit messages and enums sorted by name
+	sourceInfo    bool   // Comment messages and fields with their XSD location
+	commentStyle  CommentStyle
+	importOrder   ImportSortOrder
+	importGroups  bool // Separate well-known and project imports with a blank line
+
+	// proto3Optional limits the optional keyword to scalar and enum fields
+	proto3Optional bool
+	enumNames      map[string]bool // Enums of the file being generated
+
+	// template renders the output instead of defaultTemplate when set
+	template *template.Template
 }
 
-// New creates a new protobuf generator
-func New() *Generator {
-	return &Generator{
+// New creates a new protobuf generator configured by the given options
+func New(opts ...Option) *Generator {
+	g := &Generator{
 		includeHeader: true,
+		syntaxFirst:   true,
+		indent:        "  ",
 	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
-// SetHeaderOptions sets header generation options
-func (g *Generator) SetHeaderOptions(includeHeader bool, version string) {
-	g.includeHeader = includeHeader
-	g.version = version
+// SetIndent sets the string used for one level of indentation
+func (g *Generator) SetIndent(indent string) {
+	g.indent = indent
 }
 
-func (g *Generator) Generate(protoFile *model.ProtoFile) (string, error) {
-	var content strings.Builder
+// SetImportSortOrder sets the order of the import statements
+func (g *Generator) SetImportSortOrder(order ImportSortOrder) {
+	g.importOrder = order
+}
 
-	// Add header comment if enabled
-	if g.includeHeader {
-		content.WriteString("// This proto file was automatically generated from xsd by @https://github.com/i-icc/xsd2proto\n")
-		if g.version != "" {
-			content.WriteString(fmt.Sprintf("// Generated by xsd2proto version %s\n", g.version))
-		}
-		content.WriteString("\n")
+// SetImportGroupSeparator sets whether a blank line separates google/protobuf
+// imports from adjacent project imports
+func (g *Generator) SetImportGroupSeparator(separate bool) {
+	g.importGroups = separate
+}
+
+func (g *Generator) Generate(protoFile *model.ProtoFile) (string, error) {
+	if errs := protoFile.Validate(); len(errs) > 0 {
+		return "", &xsderrors.GenerateError{Msg: "invalid proto file", Err: errors.Join(errs...)}
 	}
 
-	content.WriteString(fmt.Sprintf("syntax = \"%s\";\n\n", protoFile.Syntax))
+	g.enumNames = make(map[string]bool)
+	collectEnumNames(protoFile.Enums, protoFile.Messages, g.enumNames)
 
-	if protoFile.Package != "" {
-		content.WriteString(fmt.Sprintf("package %s;\n\n", protoFile.Package))
+	data, err := g.templateData(protoFile)
+	if err != nil {
+		return "", err
 	}
+	return g.render(data)
+}
 
-	if len(protoFile.Imports) > 0 {
-		for _, imp := range protoFile.Imports {
-			content.WriteString(fmt.Sprintf("import \"%s\";\n", imp))
-		}
-		content.WriteString("\n")
+// orderEnums returns the enums in output order
+func (g *Generator) orderEnums(enums []model.ProtoEnum) []model.ProtoEnum {
+	if !g.sortOutput {
+		return enums
 	}
+	sorted := append([]model.ProtoEnum(nil), enums...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
 
-	if len(protoFile.Options) > 0 {
-		var optionKeys []string
-		for key := range protoFile.Options {
-			optionKeys = append(optionKeys, key)
-		}
-		sort.Strings(optionKeys)
+// orderMessages returns the messages in output order
+func (g *Generator) orderMessages(messages []model.ProtoMessage) []model.ProtoMessage {
+	if !g.sortOutput {
+		return messages
+	}
+	sorted := append([]model.ProtoMessage(nil), messages...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
 
-		for _, key := range optionKeys {
-			content.WriteString(fmt.Sprintf("option %s = \"%s\";\n", key, protoFile.Options[key]))
-		}
-		content.WriteString("\n")
+// sortImports returns the imports in the configured order: by default sorted
+// alphabetically with project imports placed before the google/protobuf
+// well-known types, or all sorted alphabetically
+func (g *Generator) sortImports(imports []string) []string {
+	if g.importOrder == ImportSortAlphabetical {
+		sorted := append([]string(nil), imports...)
+		sort.Strings(sorted)
+		return sorted
 	}
 
-	// Write enums
-	for _, enum := range protoFile.Enums {
-		enumContent, err := g.generateEnum(&enum)
-		if err != nil {
-			return "", fmt.Errorf("failed to generate enum %s: %w", enum.Name, err)
+	var localImports, wellKnownImports []string
+	for _, imp := range imports {
+		if isWellKnownImport(imp) {
+			wellKnownImports = append(wellKnownImports, imp)
+		} else {
+			localImports = append(localImports, imp)
 		}
-		content.WriteString(enumContent)
-		content.WriteString("\n")
 	}
+	sort.Strings(localImports)
+	sort.Strings(wellKnownImports)
 
-	for _, message := range protoFile.Messages {
-		messageContent, err := g.generateMessage(&message, 0)
-		if err != nil {
-			return "", fmt.Errorf("failed to generate message %s: %w", message.Name, err)
+	return append(localImports, wellKnownImports...)
+}
+
+// groupImports splits the sorted imports into the groups separated by a blank
+// line, starting a new group where well-known and project imports meet
+func (g *Generator) groupImports(imports []string) [][]string {
+	if len(imports) == 0 {
+		return nil
+	}
+	if !g.importGroups {
+		return [][]string{imports}
+	}
+	groups := [][]string{{imports[0]}}
+	for i := 1; i < len(imports); i++ {
+		if isWellKnownImport(imports[i]) != isWellKnownImport(imports[i-1]) {
+			groups = append(groups, nil)
 		}
-		content.WriteString(messageContent)
-		content.WriteString("\n")
+		groups[len(groups)-1] = append(groups[len(groups)-1], imports[i])
 	}
+	return groups
+}
 
-	return content.String(), nil
+func isWellKnownImport(imp string) bool {
+	return strings.HasPrefix(imp, "google/protobuf/")
 }
 
 func (g *Generator) generateMessage(message *model.ProtoMessage, indentLevel int) (string, error) {
 	var content strings.Builder
-	indent := strings.Repeat("  ", indentLevel)
+	indent := strings.Repeat(g.indent, indentLevel)
+	inner := indent + g.indent
 
+	if message.Comment != "" {
+		content.WriteString(fmt.Sprintf("%s// %s\n", indent, message.Comment))
+	}
+	content.WriteString(g.sourceComment(message.SourceFile, message.SourceLine, indent))
 	content.WriteString(fmt.Sprintf("%smessage %s {\n", indent, message.Name))
 
-	for _, enum := range message.Enums {
+	if message.IsDeprecated {
+		content.WriteString(fmt.Sprintf("%soption deprecated = true;\n", inner))
+	}
+	for _, option := range message.Options {
+		// protoc rejects an option set twice
+		if message.IsDeprecated && option.Key == "deprecated" {
+			continue
+		}
+		content.WriteString(fmt.Sprintf("%soption %s = %s;\n", inner, option.Key, option.Literal()))
+	}
+
+	if len(message.ReservedNumbers) > 0 {
+		numbers := make([]string, len(message.ReservedNumbers))
+		for i, number := range message.ReservedNumbers {
+			numbers[i] = strconv.Itoa(number)
+		}
+		content.WriteString(fmt.Sprintf("%sreserved %s;\n", inner, strings.Join(numbers, ", ")))
+	}
+	if len(message.ReservedNames) > 0 {
+		names := make([]string, len(message.ReservedNames))
+		for i, name := range message.ReservedNames {
+			names[i] = strconv.Quote(name)
+		}
+		content.WriteString(fmt.Sprintf("%sreserved %s;\n", inner, strings.Join(names, ", ")))
+	}
+
+	for _, enum := range g.orderEnums(message.Enums) {
 		enumContent, err := g.generateEnum(&enum)
 		if err != nil {
-			return "", fmt.Errorf("failed to generate nested enum %s: %w", enum.Name, err)
+			return "", &xsderrors.GenerateError{Msg: "failed to generate nested enum " + enum.Name, Err: err}
 		}
 		lines := strings.Split(strings.TrimSpace(enumContent), "\n")
 		for _, line := range lines {
-			content.WriteString(fmt.Sprintf("%s  %s\n", indent, line))
+			content.WriteString(fmt.Sprintf("%s%s\n", inner, line))
 		}
 		content.WriteString("\n")
 	}
 
-	for _, nestedMessage := range message.Messages {
+	for _, nestedMessage := range g.orderMessages(message.Messages) {
 		nestedContent, err := g.generateMessage(&nestedMessage, indentLevel+1)
 		if err != nil {
-			return "", fmt.Errorf("failed to generate nested message %s: %w", nestedMessage.Name, err)
+			return "", &xsderrors.GenerateError{Msg: "failed to generate nested message " + nestedMessage.Name, Err: err}
 		}
 		content.WriteString(nestedContent)
 		content.WriteString("\n")
 	}
 
-	// Generate fields
-	for _, field := range message.Fields {
-		fieldContent := g.generateField(&field, indentLevel+1)
-		content.WriteString(fieldContent)
+	// Generate fields, grouping consecutive oneof members into a single block
+	for i := 0; i < len(message.Fields); i++ {
+		field := message.Fields[i]
+		if field.Oneof == "" {
+			content.WriteString(g.generateField(&field, indentLevel+1))
+			continue
+		}
+
+		content.WriteString(fmt.Sprintf("%soneof %s {\n", inner, field.Oneof))
+		for ; i < len(message.Fields) && message.Fields[i].Oneof == field.Oneof; i++ {
+			oneofField := message.Fields[i]
+			content.WriteString(g.generateField(&oneofField, indentLevel+2))
+		}
+		content.WriteString(fmt.Sprintf("%s}\n", inner))
+		i--
 	}
 
 	content.WriteString(fmt.Sprintf("%s}\n", indent))
@@ -126,27 +224,47 @@ func (g *Generator) generateMessage(message *model.ProtoMessage, indentLevel int
 }
 
 func (g *Generator) generateField(field *model.ProtoField, indentLevel int) string {
-	indent := strings.Repeat("  ", indentLevel)
+	indent := strings.Repeat(g.indent, indentLevel)
 
 	var label string
 	switch field.Label {
 	case model.FieldLabelRepeated:
 		label = "repeated "
 	case model.FieldLabelOptional:
-		label = "optional "
+		if g.optionalLabel(field) {
+			label = "optional "
+		}
 	case model.FieldLabelRequired:
 		// In proto3, required fields don't have explicit label
 		// All fields are optional by default, but we can omit the label for required semantics
 		label = ""
 	}
+	if field.Oneof != "" {
+		// Fields inside a oneof cannot carry a label
+		label = ""
+	}
+
+	fieldType := field.Type
+	if field.IsMap {
+		// Map fields cannot carry a label
+		label = ""
+		fieldType = fmt.Sprintf("map<%s, %s>", field.KeyType, field.Type)
+	}
 
-	fieldLine := fmt.Sprintf("%s%s%s %s = %d", indent, label, field.Type, field.Name, field.Number)
+	fieldLine := fmt.Sprintf("%s%s%s %s = %d", indent, label, fieldType, field.Name, field.Number)
 
-	if len(field.Options) > 0 {
-		var options []string
-		for key, value := range field.Options {
-			options = append(options, fmt.Sprintf("%s = \"%s\"", key, value))
+	var options []string
+	for key, value := range field.Options {
+		if key == model.AppInfoOptionKey {
+			continue
 		}
+		options = append(options, fmt.Sprintf("%s = \"%s\"", key, value))
+	}
+	if field.IsDeprecated {
+		options = append(options, "deprecated = true")
+	}
+	options = append(options, field.RawOptions...)
+	if len(options) > 0 {
 		sort.Strings(options)
 		fieldLine += fmt.Sprintf(" [%s]", strings.Join(options, ", "))
 	}
@@ -154,21 +272,73 @@ func (g *Generator) generateField(field *model.ProtoField, indentLevel int) stri
 	fieldLine += ";"
 
 	// Add inline comment if present
+	var comments []string
 	if field.Comment != "" {
-		fieldLine += fmt.Sprintf(" // %s", field.Comment)
+		comments = append(comments, field.Comment)
+	}
+	if appInfo, ok := field.Options[model.AppInfoOptionKey]; ok {
+		comments = append(comments, "appinfo: "+appInfo)
+	}
+	facets := g.sourceComment(field.SourceFile, field.SourceLine, indent) + facetComment(field, indent)
+	if len(comments) == 0 {
+		return facets + fieldLine + "\n"
+	}
+	if g.commentStyle == CommentStyleLeading {
+		return fmt.Sprintf("%s%s// %s\n%s\n", facets, indent, strings.Join(comments, "; "), fieldLine)
+	}
+	return fmt.Sprintf("%s%s // %s\n", facets, fieldLine, strings.Join(comments, "; "))
+}
+
+// facetComment returns the comment block documenting the pattern and length
+// facets of a field, one "// key: value" line each, or "" without facets
+func facetComment(field *model.ProtoField, indent string) string {
+	var pattern, length []string
+	for _, constraint := range field.Constraints {
+		switch constraint.Facet {
+		case "pattern":
+			pattern = append(pattern, constraint.Value)
+		case "minLength":
+			length = append(length, "min="+constraint.Value)
+		case "maxLength":
+			length = append(length, "max="+constraint.Value)
+		}
 	}
 
-	fieldLine += "\n"
-	return fieldLine
+	var content strings.Builder
+	for _, value := range pattern {
+		content.WriteString(fmt.Sprintf("%s// pattern: %s\n", indent, value))
+	}
+	if len(length) > 0 {
+		content.WriteString(fmt.Sprintf("%s// length: %s\n", indent, strings.Join(length, " ")))
+	}
+	return content.String()
+}
+
+// sourceComment returns the "// source: <file>:<line>" comment locating a
+// definition in the XSD when source info is enabled and the line is known
+func (g *Generator) sourceComment(file string, line int, indent string) string {
+	if !g.sourceInfo || line == 0 {
+		return ""
+	}
+	if file == "" {
+		return fmt.Sprintf("%s// source: line %d\n", indent, line)
+	}
+	return fmt.Sprintf("%s// source: %s:%d\n", indent, file, line)
 }
 
 func (g *Generator) generateEnum(enum *model.ProtoEnum) (string, error) {
 	var content strings.Builder
 
 	content.WriteString(fmt.Sprintf("enum %s {\n", enum.Name))
+	if enum.AllowAlias {
+		content.WriteString(fmt.Sprintf("%soption allow_alias = true;\n", g.indent))
+	}
 
 	for _, value := range enum.Values {
-		content.WriteString(fmt.Sprintf("  %s = %d;\n", value.Name, value.Number))
+		if value.Comment != "" {
+			content.WriteString(fmt.Sprintf("%s// %s\n", g.indent, value.Comment))
+		}
+		content.WriteString(fmt.Sprintf("%s%s = %d;\n", g.indent, value.Name, value.Number))
 	}
 
 	content.WriteString("}\n")
@@ -184,3 +354,32 @@ func (g *Generator) GenerateToFile(protoFile *model.ProtoFile, outputPath string
 	_ = content
 	return fmt.Errorf("file writing not implemented yet")
 }
+
+// scalarTypes are the proto scalar value types, which have no presence in proto3
+// unless marked optional
+var scalarTypes = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true,
+	"bool": true, "string": true, "bytes": true,
+}
+
+// optionalLabel reports whether an optional field is written with the optional
+// keyword: always, or only for scalars and enums with proto3Optional set
+func (g *Generator) optionalLabel(field *model.ProtoField) bool {
+	return !g.proto3Optional || g.hasScalarType(field)
+}
+
+// hasScalarType reports whether the field holds a scalar or an enum rather than a message
+func (g *Generator) hasScalarType(field *model.ProtoField) bool {
+	return scalarTypes[field.Type] || g.enumNames[field.Type]
+}
+
+// collectEnumNames adds the names of the enums, including nested ones, to names
+func collectEnumNames(enums []model.ProtoEnum, messages []model.ProtoMessage, names map[string]bool) {
+	for _, enum := range enums {
+		names[enum.Name] = true
+	}
+	for _, message := range messages {
+		collectEnumNames(message.Enums, message.Messages, names)
+	}
+}
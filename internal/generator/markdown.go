@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// GenerateMarkdown renders a Markdown reference of the messages and enums of a
+// proto file. Each type gets an H2 heading, followed by its description and a
+// table of its fields or values. Nested types are listed after their parent
+// under their qualified name. Descriptions come from the XSD documentation,
+// or from the proto comment when there is none.
+func GenerateMarkdown(protoFile *model.ProtoFile) string {
+	var content strings.Builder
+
+	title := protoFile.Package
+	if title == "" {
+		title = "API Reference"
+	}
+	content.WriteString(fmt.Sprintf("# %s\n", title))
+
+	for _, enum := range protoFile.Enums {
+		writeMarkdownEnum(&content, &enum, enum.Name)
+	}
+	for _, message := range protoFile.Messages {
+		writeMarkdownMessage(&content, &message, message.Name)
+	}
+	return content.String()
+}
+
+func writeMarkdownMessage(content *strings.Builder, message *model.ProtoMessage, name string) {
+	content.WriteString(fmt.Sprintf("\n## %s\n\n", name))
+	if message.IsDeprecated {
+		content.WriteString("**Deprecated.**\n\n")
+	}
+	if description := markdownDescription(message.Documentation, message.Comment); description != "" {
+		content.WriteString(description + "\n\n")
+	}
+
+	if len(message.Fields) == 0 {
+		content.WriteString("No fields.\n")
+	} else {
+		content.WriteString("| Name | Type | Label | Description |\n")
+		content.WriteString("|------|------|-------|-------------|\n")
+		for _, field := range message.Fields {
+			fieldType := field.Type
+			if field.IsMap {
+				fieldType = fmt.Sprintf("map<%s, %s>", field.KeyType, field.Type)
+			}
+			description := markdownDescription(field.Documentation, field.Comment)
+			if field.IsDeprecated {
+				description = strings.TrimSpace("Deprecated. " + description)
+			}
+			content.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s |\n",
+				field.Name, fieldType, markdownLabel(&field), description))
+		}
+	}
+
+	for _, enum := range message.Enums {
+		writeMarkdownEnum(content, &enum, name+"."+enum.Name)
+	}
+	for _, nested := range message.Messages {
+		writeMarkdownMessage(content, &nested, name+"."+nested.Name)
+	}
+}
+
+func writeMarkdownEnum(content *strings.Builder, enum *model.ProtoEnum, name string) {
+	content.WriteString(fmt.Sprintf("\n## %s\n\n", name))
+	if description := markdownDescription(enum.Documentation, ""); description != "" {
+		content.WriteString(description + "\n\n")
+	}
+
+	content.WriteString("| Name | Number | Description |\n")
+	content.WriteString("|------|--------|-------------|\n")
+	for _, value := range enum.Values {
+		content.WriteString(fmt.Sprintf("| %s | %d | %s |\n", value.Name, value.Number, markdownDescription(value.Comment, "")))
+	}
+}
+
+// markdownLabel returns the label column of a field: the oneof it belongs to,
+// nothing for maps, and its XSD cardinality otherwise
+func markdownLabel(field *model.ProtoField) string {
+	switch {
+	case field.Oneof != "":
+		return "oneof " + field.Oneof
+	case field.IsMap:
+		return ""
+	default:
+		return field.Label.String()
+	}
+}
+
+// markdownDescription returns the documentation, or the comment when there is
+// none, made safe for a single table cell
+func markdownDescription(documentation, comment string) string {
+	description := documentation
+	if description == "" {
+		description = comment
+	}
+	description = strings.Join(strings.Fields(description), " ")
+	return strings.ReplaceAll(description, "|", `\|`)
+}
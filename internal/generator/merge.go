@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// MergeProtoFiles combines several proto files, typically one per namespace, into one.
+// The syntax and package come from the first file, imports and options are
+// deduplicated, and messages or enums defined in more than one file are kept once.
+// A type defined differently in two files, or an option set to two different values,
+// is a conflict and returns an error. Where a type was declared in the XSD does not
+// make its definitions differ.
+func MergeProtoFiles(files []*model.ProtoFile) (*model.ProtoFile, error) {
+	return model.MergeFiles(files, model.MergePolicy{
+		Option: func(existing, option model.ProtoOption) error {
+			if existing != option {
+				return fmt.Errorf("conflicting values for option %s: %s and %s", option.Key, existing.Literal(), option.Literal())
+			}
+			return nil
+		},
+		Duplicate: func(name, existing, kind string, equal bool) error {
+			if existing != kind {
+				return fmt.Errorf("%s is defined both as a message and as an enum", name)
+			}
+			if !equal {
+				return fmt.Errorf("conflicting definitions of %s %s", kind, name)
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,79 @@
+package generator
+
+// CommentStyle controls where field comments are placed
+type CommentStyle int
+
+const (
+	// CommentStyleTrailing places comments after the field on the same line
+	CommentStyleTrailing CommentStyle = iota
+	// CommentStyleLeading places comments on their own line above the field
+	CommentStyleLeading
+)
+
+// ImportSortOrder controls the order of the import statements
+type ImportSortOrder int
+
+const (
+	// ImportSortDependencyFirst places project imports before the google/protobuf
+	// well-known types, each group sorted alphabetically
+	ImportSortDependencyFirst ImportSortOrder = iota
+	// ImportSortAlphabetical sorts all imports alphabetically
+	ImportSortAlphabetical
+)
+
+// Option configures a Generator
+type Option func(*Generator)
+
+// WithIndent sets the string used for one level of indentation
+func WithIndent(indent string) Option {
+	return func(g *Generator) {
+		g.indent = indent
+	}
+}
+
+// WithSortOutput sets whether messages and enums are emitted sorted by name
+// rather than in schema order
+func WithSortOutput(sortOutput bool) Option {
+	return func(g *Generator) {
+		g.sortOutput = sortOutput
+	}
+}
+
+// WithCommentStyle sets where field comments are placed
+func WithCommentStyle(style CommentStyle) Option {
+	return func(g *Generator) {
+		g.commentStyle = style
+	}
+}
+
+// WithSyntaxFirst sets whether the syntax line comes before the header comment
+// Putting syntax first matches the proto style guide and is the default
+func WithSyntaxFirst(syntaxFirst bool) Option {
+	return func(g *Generator) {
+		g.syntaxFirst = syntaxFirst
+	}
+}
+
+// WithHeader sets whether the generated-by header is emitted and the version it reports
+func WithHeader(includeHeader bool, version string) Option {
+	return func(g *Generator) {
+		g.includeHeader = includeHeader
+		g.version = version
+	}
+}
+
+// WithProto3Optional sets whether only scalar and enum fields are marked optional
+// Message fields already track presence, so with this set they are emitted without a label
+func WithProto3Optional(proto3Optional bool) Option {
+	return func(g *Generator) {
+		g.proto3Optional = proto3Optional
+	}
+}
+
+// WithSourceInfo sets whether messages and fields are preceded by a
+// "// source: <file>:<line>" comment locating their XSD declaration
+func WithSourceInfo(sourceInfo bool) Option {
+	return func(g *Generator) {
+		g.sourceInfo = sourceInfo
+	}
+}
@@ -0,0 +1,27 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// ConverterPlugin is a pass over a converted proto file that runs before generation,
+// such as adding field options. Plugins keep optional output features out of the
+// main conversion logic.
+type ConverterPlugin interface {
+	// Name identifies the plugin in error messages
+	Name() string
+	// Process modifies the proto file in place
+	Process(protoFile *model.ProtoFile) error
+}
+
+// ApplyPlugins runs the plugins over the proto file in order
+func ApplyPlugins(protoFile *model.ProtoFile, plugins []ConverterPlugin) error {
+	for _, plugin := range plugins {
+		if err := plugin.Process(protoFile); err != nil {
+			return fmt.Errorf("plugin %s failed: %w", plugin.Name(), err)
+		}
+	}
+	return nil
+}
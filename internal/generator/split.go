@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// SplitProtoFile splits a proto file into one file per top-level message, keyed by
+// file name (<snake_case_message_name>.proto). Each enum is placed in the file of the
+// first message referencing it, or in its own file when no message uses it. Every file
+// imports the well-known types it uses and the files defining the types it references.
+func SplitProtoFile(protoFile *model.ProtoFile) map[string]*model.ProtoFile {
+	files := make(map[string]*model.ProtoFile)
+	definedIn := make(map[string]string)
+
+	for _, message := range protoFile.Messages {
+		fileName := splitFileName(message.Name)
+		file := newSplitFile(protoFile)
+		file.Messages = append(file.Messages, message)
+		files[fileName] = file
+		definedIn[message.Name] = fileName
+	}
+
+	for _, enum := range protoFile.Enums {
+		fileName := ""
+		for _, message := range protoFile.Messages {
+			if containsString(collectFieldTypes(&message), enum.Name) {
+				fileName = definedIn[message.Name]
+				break
+			}
+		}
+		if fileName == "" {
+			fileName = splitFileName(enum.Name)
+			files[fileName] = newSplitFile(protoFile)
+		}
+		files[fileName].Enums = append(files[fileName].Enums, enum)
+		definedIn[enum.Name] = fileName
+	}
+
+	typeMapper := converter.NewTypeMapper()
+	for fileName, file := range files {
+		var fieldTypes []string
+		for _, message := range file.Messages {
+			fieldTypes = append(fieldTypes, collectFieldTypes(&message)...)
+		}
+
+		imports := make(map[string]bool)
+		for _, fieldType := range fieldTypes {
+			if dependency, exists := definedIn[fieldType]; exists && dependency != fileName {
+				imports[dependency] = true
+			}
+		}
+		for _, imp := range typeMapper.GetRequiredImports(fieldTypes) {
+			imports[imp] = true
+		}
+
+		for imp := range imports {
+			file.Imports = append(file.Imports, imp)
+		}
+		sort.Strings(file.Imports)
+	}
+
+	return files
+}
+
+func newSplitFile(protoFile *model.ProtoFile) *model.ProtoFile {
+	return &model.ProtoFile{
+		Syntax:  protoFile.Syntax,
+		Package: protoFile.Package,
+		Options: append([]model.ProtoOption{}, protoFile.Options...),
+	}
+}
+
+// collectFieldTypes returns the field types of a message and its nested messages
+func collectFieldTypes(message *model.ProtoMessage) []string {
+	var types []string
+	for _, field := range message.Fields {
+		types = append(types, field.Type)
+	}
+	for _, nested := range message.Messages {
+		types = append(types, collectFieldTypes(&nested)...)
+	}
+	return types
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+func splitFileName(typeName string) string {
+	var result strings.Builder
+	for i, r := range typeName {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			result.WriteRune('_')
+		}
+		result.WriteRune(r)
+	}
+	return strings.ToLower(result.String()) + ".proto"
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+	"text/template"
+
+	xsderrors "github.com/i-icc/xsd2proto/internal/errors"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// defaultTemplate is the layout of the built-in proto output. Custom templates set
+// with SetTemplate receive the same TemplateData and can use it as a reference.
+const defaultTemplate = `{{define "syntax"}}syntax = "{{.File.Syntax}}";
+
+{{end}}
+{{- if .SyntaxFirst}}{{template "syntax" .}}{{end}}
+{{- if .Header}}{{range .Header}}// {{.}}
+{{end}}
+{{end}}
+{{- if not .SyntaxFirst}}{{template "syntax" .}}{{end}}
+{{- with .File.Package}}package {{.}};
+
+{{end}}
+{{- if .Imports}}{{range $i, $group := .ImportGroups}}{{if $i}}
+{{end}}{{range $group}}import "{{.}}";
+{{end}}{{end}}
+{{end}}
+{{- if .Options}}{{range .Options}}option {{.Key}} = {{.Literal}};
+{{end}}
+{{end}}
+{{- range .Enums}}{{.Proto}}
+{{end}}
+{{- range .Messages}}{{.Proto}}
+{{end}}
+{{- range .File.RawDeclarations}}{{.}}
+
+{{end}}`
+
+var builtinTemplate = template.Must(template.New("proto").Parse(defaultTemplate))
+
+// TemplateData is the value a template is executed with
+type TemplateData struct {
+	// File is the proto file being generated
+	File *model.ProtoFile
+	// Header holds the lines of the generated-by comment, without "// "; empty when disabled
+	Header []string
+	// SyntaxFirst is set when the syntax line comes before the header comment
+	SyntaxFirst bool
+	// Imports are the imports in output order
+	Imports []string
+	// ImportGroups are Imports split where a blank line separates them
+	ImportGroups [][]string
+	// Options are the file options sorted by key
+	Options []model.ProtoOption
+	// Enums and Messages are the top-level types in output order,
+	// each with its rendered proto definition
+	Enums    []TemplateEnum
+	Messages []TemplateMessage
+}
+
+// TemplateEnum is a top-level enum with its proto definition in Proto
+type TemplateEnum struct {
+	model.ProtoEnum
+	Proto string
+}
+
+// TemplateMessage is a top-level message with its proto definition in Proto,
+// including nested types and fields
+type TemplateMessage struct {
+	model.ProtoMessage
+	Proto string
+}
+
+// SetTemplate renders the output with tmpl instead of the built-in layout.
+// A nil template restores the built-in layout.
+func (g *Generator) SetTemplate(tmpl *template.Template) {
+	g.template = tmpl
+}
+
+// templateData collects the data templates are executed with
+func (g *Generator) templateData(protoFile *model.ProtoFile) (*TemplateData, error) {
+	data := &TemplateData{
+		File:        protoFile,
+		SyntaxFirst: g.syntaxFirst,
+		Imports:     g.sortImports(protoFile.Imports),
+	}
+	data.ImportGroups = g.groupImports(data.Imports)
+
+	if g.includeHeader {
+		data.Header = append(data.Header, "This proto file was automatically generated from xsd by @https://github.com/i-icc/xsd2proto")
+		if g.version != "" {
+			data.Header = append(data.Header, "Generated by xsd2proto version "+g.version)
+		}
+	}
+
+	data.Options = append(data.Options, protoFile.Options...)
+	sort.SliceStable(data.Options, func(i, j int) bool { return data.Options[i].Key < data.Options[j].Key })
+
+	for _, enum := range g.orderEnums(protoFile.Enums) {
+		enumContent, err := g.generateEnum(&enum)
+		if err != nil {
+			return nil, &xsderrors.GenerateError{Msg: "failed to generate enum " + enum.Name, Err: err}
+		}
+		data.Enums = append(data.Enums, TemplateEnum{ProtoEnum: enum, Proto: enumContent})
+	}
+
+	for _, message := range g.orderMessages(protoFile.Messages) {
+		messageContent, err := g.generateMessage(&message, 0)
+		if err != nil {
+			return nil, &xsderrors.GenerateError{Msg: "failed to generate message " + message.Name, Err: err}
+		}
+		data.Messages = append(data.Messages, TemplateMessage{ProtoMessage: message, Proto: messageContent})
+	}
+
+	return data, nil
+}
+
+// render executes the custom template, or the built-in one when none is set
+func (g *Generator) render(data *TemplateData) (string, error) {
+	tmpl := g.template
+	if tmpl == nil {
+		tmpl = builtinTemplate
+	}
+
+	var content strings.Builder
+	if err := tmpl.Execute(&content, data); err != nil {
+		return "", &xsderrors.GenerateError{Msg: "failed to execute template " + tmpl.Name(), Err: err}
+	}
+	return content.String(), nil
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// Imports declaring the validation rules
+const (
+	ValidateImport      = "validate/validate.proto"
+	ProtovalidateImport = "buf/validate/validate.proto"
+)
+
+// validateRuleNames maps XSD facets to rule names, which protoc-gen-validate and
+// protovalidate share
+var validateRuleNames = map[string]string{
+	"minLength": "min_len",
+	"maxLength": "max_len",
+	"pattern":   "pattern",
+	"fixed":     "const",
+}
+
+// lengthRuleTypes are the field types with length and pattern rules
+var lengthRuleTypes = map[string]bool{
+	"string": true,
+	"bytes":  true,
+}
+
+// constRuleTypes are the field types with a const rule
+var constRuleTypes = map[string]bool{
+	"string": true, "bytes": true, "bool": true,
+	"int32": true, "int64": true, "uint32": true, "uint64": true,
+	"float": true, "double": true,
+}
+
+// ValidatePlugin turns field constraints into validation field options, e.g.
+// [(validate.rules).string.min_len = 1], and imports the file declaring the rules
+type ValidatePlugin struct {
+	name       string
+	extension  string // Option extension the rules are set on
+	importPath string
+}
+
+// NewValidatePlugin creates a ValidatePlugin emitting protoc-gen-validate rules
+func NewValidatePlugin() *ValidatePlugin {
+	return &ValidatePlugin{name: "validate", extension: "(validate.rules)", importPath: ValidateImport}
+}
+
+// NewProtovalidatePlugin creates a ValidatePlugin emitting protovalidate rules,
+// e.g. [(buf.validate.field).string.const = "v1"]
+func NewProtovalidatePlugin() *ValidatePlugin {
+	return &ValidatePlugin{name: "protovalidate", extension: "(buf.validate.field)", importPath: ProtovalidateImport}
+}
+
+// Name returns the plugin name
+func (p *ValidatePlugin) Name() string {
+	return p.name
+}
+
+// Process adds validate rules to every field with constraints
+func (p *ValidatePlugin) Process(protoFile *model.ProtoFile) error {
+	used := false
+	for i := range protoFile.Messages {
+		if p.processMessage(&protoFile.Messages[i]) {
+			used = true
+		}
+	}
+
+	if used && !containsString(protoFile.Imports, p.importPath) {
+		protoFile.Imports = append(protoFile.Imports, p.importPath)
+	}
+	return nil
+}
+
+// processMessage adds validate rules to the fields of a message and its nested messages
+func (p *ValidatePlugin) processMessage(message *model.ProtoMessage) bool {
+	used := false
+	for i := range message.Fields {
+		field := &message.Fields[i]
+		for _, constraint := range field.Constraints {
+			if rule := p.rule(field, constraint); rule != "" {
+				field.RawOptions = append(field.RawOptions, rule)
+				used = true
+			}
+		}
+	}
+	for i := range message.Messages {
+		if p.processMessage(&message.Messages[i]) {
+			used = true
+		}
+	}
+	return used
+}
+
+// rule returns the field option for a constraint, or "" when the field type has no such rule
+func (p *ValidatePlugin) rule(field *model.ProtoField, constraint model.FieldConstraint) string {
+	name, ok := validateRuleNames[constraint.Facet]
+	if !ok || field.IsMap {
+		return ""
+	}
+	if constraint.Facet == "fixed" && !constRuleTypes[field.Type] {
+		return ""
+	}
+	if constraint.Facet != "fixed" && !lengthRuleTypes[field.Type] {
+		return ""
+	}
+
+	// Patterns and string or bytes constants are string literals
+	value := constraint.Value
+	if constraint.Facet == "pattern" || (constraint.Facet == "fixed" && lengthRuleTypes[field.Type]) {
+		value = strconv.Quote(value)
+	}
+
+	path := field.Type + "." + name
+	if field.Label == model.FieldLabelRepeated {
+		path = "repeated.items." + path
+	}
+	return fmt.Sprintf("%s.%s = %s", p.extension, path, value)
+}
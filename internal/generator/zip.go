@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// GenerateZip generates each proto file and writes it to a zip archive on w,
+// keyed by its path in the archive. The entries are written in path order.
+func (g *Generator) GenerateZip(files map[string]*model.ProtoFile, w io.Writer) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive := zip.NewWriter(w)
+	for _, name := range names {
+		content, err := g.Generate(files[name])
+		if err != nil {
+			return fmt.Errorf("failed to generate %s: %w", name, err)
+		}
+		entry, err := archive.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to the zip archive: %w", name, err)
+		}
+		if _, err := io.WriteString(entry, content); err != nil {
+			return fmt.Errorf("failed to write %s to the zip archive: %w", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finish the zip archive: %w", err)
+	}
+	return nil
+}
+
+// ZipSplitFiles places the files returned by SplitProtoFile in the directory of
+// their package, <snake_case_package>/<snake_case_message_name>.proto, and points
+// the imports between them at the new paths. Files without a package stay at the
+// top of the archive.
+func ZipSplitFiles(files map[string]*model.ProtoFile, protoPackage string) map[string]*model.ProtoFile {
+	dir := strings.ReplaceAll(protoPackage, ".", "_")
+	if dir == "" {
+		return files
+	}
+
+	zipped := make(map[string]*model.ProtoFile, len(files))
+	for fileName, file := range files {
+		for i, imp := range file.Imports {
+			if _, local := files[imp]; local {
+				file.Imports[i] = dir + "/" + imp
+			}
+		}
+		zipped[dir+"/"+fileName] = file
+	}
+	return zipped
+}
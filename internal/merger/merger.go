@@ -0,0 +1,31 @@
+// Package merger combines proto files, e.g. a converted schema with a hand-written proto
+package merger
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// MergeProtoFiles merges b into a and returns the result.
+// The syntax and package come from a, imports are deduplicated and sorted,
+// and the messages, enums, services and extensions of both files are kept with
+// those of a first. A top-level name defined in both files is an error.
+// Options set in both files take the value of b.
+func MergeProtoFiles(a, b *model.ProtoFile) (*model.ProtoFile, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("cannot merge a nil proto file")
+	}
+
+	merged, err := model.MergeFiles([]*model.ProtoFile{a, b}, model.MergePolicy{
+		Duplicate: func(name, existing, kind string, equal bool) error {
+			return fmt.Errorf("duplicate name %s: defined as %s and as %s", name, existing, kind)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(merged.Imports)
+	return merged, nil
+}
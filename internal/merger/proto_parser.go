@@ -0,0 +1,613 @@
+package merger
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// ParseProtoFile reads the top-level declarations of a proto3 file: syntax,
+// package, imports, file options, messages and enums. Comments are dropped.
+// Services and extensions are kept as written in RawDeclarations.
+func ParseProtoFile(r io.Reader) (*model.ProtoFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proto file: %w", err)
+	}
+
+	tokens, err := tokenize(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &protoParser{src: string(data), tokens: tokens}
+	return p.parseFile()
+}
+
+// token is a lexical element of a proto file with its byte offsets in the source
+type token struct {
+	text       string
+	start, end int
+	line       int
+}
+
+// tokenize splits proto source into identifiers, numbers, string literals and
+// punctuation, skipping whitespace and comments
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	line := 1
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			start := i
+			for i++; i < len(src) && src[i] != c; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+				if i < len(src) && src[i] == '\n' {
+					return nil, fmt.Errorf("line %d: unterminated string", line)
+				}
+			}
+			if i >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			i++
+			tokens = append(tokens, token{text: src[start:i], start: start, end: i, line: line})
+		case isIdentChar(c) || (c == '-' && i+1 < len(src) && isIdentChar(src[i+1])):
+			start := i
+			for i++; i < len(src) && isIdentChar(src[i]); i++ {
+			}
+			tokens = append(tokens, token{text: src[start:i], start: start, end: i, line: line})
+		default:
+			tokens = append(tokens, token{text: string(c), start: i, end: i + 1, line: line})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+// isIdentChar reports whether c can be part of an identifier, a full type name or a number
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '.' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// protoParser is a recursive descent parser over the tokens of a proto file
+type protoParser struct {
+	src    string
+	tokens []token
+	pos    int
+}
+
+func (p *protoParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos].text
+}
+
+func (p *protoParser) next() (token, error) {
+	if p.pos >= len(p.tokens) {
+		return token{}, fmt.Errorf("unexpected end of proto file")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *protoParser) expect(text string) error {
+	t, err := p.next()
+	if err != nil {
+		return fmt.Errorf("expected %q: %w", text, err)
+	}
+	if t.text != text {
+		return fmt.Errorf("line %d: expected %q, got %q", t.line, text, t.text)
+	}
+	return nil
+}
+
+// name reads an identifier or a number
+func (p *protoParser) name() (token, error) {
+	t, err := p.next()
+	if err != nil {
+		return t, err
+	}
+	if !isIdentChar(t.text[0]) && t.text[0] != '-' {
+		return t, fmt.Errorf("line %d: expected a name, got %q", t.line, t.text)
+	}
+	return t, nil
+}
+
+// stringLiteral reads a quoted string and returns its value
+func (p *protoParser) stringLiteral() (string, error) {
+	t, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if t.text[0] != '"' && t.text[0] != '\'' {
+		return "", fmt.Errorf("line %d: expected a string, got %q", t.line, t.text)
+	}
+	return unquote(t.text), nil
+}
+
+// unquote strips the quotes of a string literal and resolves its escapes
+func unquote(literal string) string {
+	if literal[0] == '\'' {
+		literal = `"` + strings.ReplaceAll(literal[1:len(literal)-1], `"`, `\"`) + `"`
+	}
+	if value, err := strconv.Unquote(literal); err == nil {
+		return value
+	}
+	return literal[1 : len(literal)-1]
+}
+
+func (p *protoParser) parseFile() (*model.ProtoFile, error) {
+	file := &model.ProtoFile{Syntax: "proto3"}
+
+	for p.pos < len(p.tokens) {
+		t, _ := p.next()
+		switch t.text {
+		case ";":
+		case "syntax":
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			syntax, err := p.stringLiteral()
+			if err != nil {
+				return nil, err
+			}
+			if syntax != "proto3" {
+				return nil, fmt.Errorf("line %d: unsupported syntax %q", t.line, syntax)
+			}
+			file.Syntax = syntax
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case "package":
+			pkg, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			file.Package = pkg.text
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case "import":
+			if p.peek() == "public" || p.peek() == "weak" {
+				p.pos++
+			}
+			path, err := p.stringLiteral()
+			if err != nil {
+				return nil, err
+			}
+			file.Imports = append(file.Imports, path)
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case "option":
+			key, value, err := p.parseOption()
+			if err != nil {
+				return nil, err
+			}
+			if value[0] == '"' || value[0] == '\'' {
+				file.SetOption(model.StringOption(key, unquote(value)))
+			} else {
+				file.SetOption(model.IdentOption(key, value))
+			}
+		case "message":
+			message, err := p.parseMessage()
+			if err != nil {
+				return nil, err
+			}
+			file.Messages = append(file.Messages, *message)
+		case "enum":
+			enum, err := p.parseEnum()
+			if err != nil {
+				return nil, err
+			}
+			file.Enums = append(file.Enums, *enum)
+		case "service", "extend":
+			declaration, err := p.parseRawDeclaration()
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", t.line, t.text, err)
+			}
+			file.RawDeclarations = append(file.RawDeclarations, declaration)
+		default:
+			return nil, fmt.Errorf("line %d: unsupported top-level declaration %q", t.line, t.text)
+		}
+	}
+
+	return file, nil
+}
+
+// parseRawDeclaration reads the name and braced body after a service or extend
+// keyword and returns the whole declaration as written, keeping its comments
+func (p *protoParser) parseRawDeclaration() (string, error) {
+	start := p.pos - 1
+	if err := p.skipValue("{"); err != nil {
+		return "", err
+	}
+	if p.pos == start+1 {
+		return "", fmt.Errorf("missing name")
+	}
+	p.pos++
+	if err := p.skipValue("}"); err != nil {
+		return "", err
+	}
+	p.pos++
+	return p.span(start, p.pos), nil
+}
+
+// parseOption reads "name = value ;" after the option keyword and returns
+// the option name and the value as written
+func (p *protoParser) parseOption() (string, string, error) {
+	start := p.pos
+	for p.peek() != "=" {
+		if _, err := p.next(); err != nil {
+			return "", "", err
+		}
+	}
+	key := p.span(start, p.pos)
+	p.pos++
+
+	valueStart := p.pos
+	if err := p.skipValue(";"); err != nil {
+		return "", "", err
+	}
+	value := p.span(valueStart, p.pos)
+	p.pos++
+	if value == "" {
+		return "", "", fmt.Errorf("option %s has no value", key)
+	}
+	return key, value, nil
+}
+
+// skipValue advances to the next stop token outside of braces
+func (p *protoParser) skipValue(stops ...string) error {
+	depth := 0
+	for {
+		text := p.peek()
+		if text == "" {
+			return fmt.Errorf("unexpected end of proto file")
+		}
+		if depth == 0 {
+			for _, stop := range stops {
+				if text == stop {
+					return nil
+				}
+			}
+		}
+		switch text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		}
+		p.pos++
+	}
+}
+
+// span returns the source text of the tokens in [from, to)
+func (p *protoParser) span(from, to int) string {
+	if from >= to {
+		return ""
+	}
+	return p.src[p.tokens[from].start:p.tokens[to-1].end]
+}
+
+func (p *protoParser) parseMessage() (*model.ProtoMessage, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	message := &model.ProtoMessage{Name: name.text}
+	if err := p.parseMessageBody(message, ""); err != nil {
+		return nil, fmt.Errorf("message %s: %w", message.Name, err)
+	}
+	return message, nil
+}
+
+// parseMessageBody reads the declarations of a message up to its closing brace.
+// Fields read inside a oneof get the oneof name.
+func (p *protoParser) parseMessageBody(message *model.ProtoMessage, oneof string) error {
+	for {
+		t, err := p.next()
+		if err != nil {
+			return err
+		}
+
+		switch t.text {
+		case "}":
+			return nil
+		case ";":
+		case "message":
+			nested, err := p.parseMessage()
+			if err != nil {
+				return err
+			}
+			message.Messages = append(message.Messages, *nested)
+		case "enum":
+			enum, err := p.parseEnum()
+			if err != nil {
+				return err
+			}
+			message.Enums = append(message.Enums, *enum)
+		case "oneof":
+			oneofName, err := p.name()
+			if err != nil {
+				return err
+			}
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.parseMessageBody(message, oneofName.text); err != nil {
+				return fmt.Errorf("oneof %s: %w", oneofName.text, err)
+			}
+		case "option":
+			key, value, err := p.parseOption()
+			if err != nil {
+				return err
+			}
+			switch {
+			case key == "deprecated":
+				message.IsDeprecated = value == "true"
+			case value[0] == '"' || value[0] == '\'':
+				message.Options = append(message.Options, model.StringOption(key, unquote(value)))
+			default:
+				message.Options = append(message.Options, model.IdentOption(key, value))
+			}
+		case "reserved":
+			if err := p.parseReserved(message); err != nil {
+				return err
+			}
+		default:
+			p.pos--
+			field, err := p.parseField()
+			if err != nil {
+				return err
+			}
+			field.Oneof = oneof
+			message.Fields = append(message.Fields, *field)
+		}
+	}
+}
+
+// parseReserved reads the field numbers, ranges or names of a reserved statement
+func (p *protoParser) parseReserved(message *model.ProtoMessage) error {
+	for {
+		t, err := p.next()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case t.text[0] == '"' || t.text[0] == '\'':
+			message.ReservedNames = append(message.ReservedNames, unquote(t.text))
+		default:
+			from, err := strconv.Atoi(t.text)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid reserved field number %q", t.line, t.text)
+			}
+			to := from
+			if p.peek() == "to" {
+				p.pos++
+				end, err := p.name()
+				if err != nil {
+					return err
+				}
+				if to, err = strconv.Atoi(end.text); err != nil {
+					return fmt.Errorf("line %d: unsupported reserved range end %q", end.line, end.text)
+				}
+			}
+			for number := from; number <= to; number++ {
+				message.ReservedNumbers = append(message.ReservedNumbers, number)
+			}
+		}
+
+		separator, err := p.next()
+		if err != nil {
+			return err
+		}
+		if separator.text == ";" {
+			return nil
+		}
+		if separator.text != "," {
+			return fmt.Errorf("line %d: expected \",\" or \";\", got %q", separator.line, separator.text)
+		}
+	}
+}
+
+// parseField reads "[label] type name = number [options];" or a map field
+func (p *protoParser) parseField() (*model.ProtoField, error) {
+	field := &model.ProtoField{Label: model.FieldLabelRequired}
+
+	switch p.peek() {
+	case "optional":
+		field.Label = model.FieldLabelOptional
+		p.pos++
+	case "repeated":
+		field.Label = model.FieldLabelRepeated
+		p.pos++
+	case "required":
+		return nil, fmt.Errorf("line %d: required fields are not supported in proto3", p.tokens[p.pos].line)
+	}
+
+	typeName, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	field.Type = typeName.text
+
+	if field.Type == "map" && p.peek() == "<" {
+		p.pos++
+		key, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		value, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(">"); err != nil {
+			return nil, err
+		}
+		field.IsMap = true
+		field.KeyType = key.text
+		field.Type = value.text
+		field.Label = model.FieldLabelRepeated
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	field.Name = name.text
+
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	number, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if field.Number, err = strconv.Atoi(number.text); err != nil {
+		return nil, fmt.Errorf("line %d: invalid field number %q", number.line, number.text)
+	}
+
+	if p.peek() == "[" {
+		p.pos++
+		if err := p.parseFieldOptions(field); err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// parseFieldOptions reads the options between brackets. deprecated sets
+// IsDeprecated, other string options go to Options and the rest to RawOptions.
+func (p *protoParser) parseFieldOptions(field *model.ProtoField) error {
+	for {
+		start := p.pos
+		if err := p.skipValue(",", "]"); err != nil {
+			return err
+		}
+		option := p.span(start, p.pos)
+
+		if key, value, ok := strings.Cut(option, "="); ok {
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			switch {
+			case key == "deprecated":
+				field.IsDeprecated = value == "true"
+			case !strings.HasPrefix(key, "(") && value != "" && (value[0] == '"' || value[0] == '\''):
+				if field.Options == nil {
+					field.Options = make(map[string]string)
+				}
+				field.Options[key] = unquote(value)
+			default:
+				field.RawOptions = append(field.RawOptions, option)
+			}
+		} else if option != "" {
+			return fmt.Errorf("invalid field option %q", option)
+		}
+
+		t, _ := p.next()
+		if t.text == "]" {
+			return nil
+		}
+	}
+}
+
+func (p *protoParser) parseEnum() (*model.ProtoEnum, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	enum := &model.ProtoEnum{Name: name.text}
+	for {
+		t, err := p.next()
+		if err != nil {
+			return nil, fmt.Errorf("enum %s: %w", enum.Name, err)
+		}
+
+		switch t.text {
+		case "}":
+			return enum, nil
+		case ";":
+		case "option":
+			key, value, err := p.parseOption()
+			if err != nil {
+				return nil, fmt.Errorf("enum %s: %w", enum.Name, err)
+			}
+			if key != "allow_alias" {
+				return nil, fmt.Errorf("enum %s: unsupported option %s", enum.Name, key)
+			}
+			enum.AllowAlias = value == "true"
+		case "reserved":
+			// Reserved enum values are not kept in the model
+			if err := p.skipValue(";"); err != nil {
+				return nil, err
+			}
+			p.pos++
+		default:
+			if err := p.expect("="); err != nil {
+				return nil, fmt.Errorf("enum %s: %w", enum.Name, err)
+			}
+			number, err := p.name()
+			if err != nil {
+				return nil, fmt.Errorf("enum %s: %w", enum.Name, err)
+			}
+			value, err := strconv.Atoi(number.text)
+			if err != nil {
+				return nil, fmt.Errorf("enum %s: line %d: invalid value number %q", enum.Name, number.line, number.text)
+			}
+			if p.peek() == "[" {
+				// Enum value options are not kept in the model
+				if err := p.skipValue("]"); err != nil {
+					return nil, err
+				}
+				p.pos++
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, fmt.Errorf("enum %s: %w", enum.Name, err)
+			}
+			enum.Values = append(enum.Values, model.ProtoEnumValue{Name: t.text, Number: value})
+		}
+	}
+}
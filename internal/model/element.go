@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AppInfoSourceFieldNumber is the appinfo source that pins the proto field number of an element
+const AppInfoSourceFieldNumber = "proto-field-number"
+
+// UnmarshalXML decodes an element, recording its line, and reads the field number
+// hint from its appinfo
+func (e *Element) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainElement Element
+	line, _ := d.InputPos()
+	if err := d.DecodeElement((*plainElement)(e), &start); err != nil {
+		return err
+	}
+	e.Line = line
+
+	if e.Annotation == nil {
+		return nil
+	}
+	for _, appInfo := range e.Annotation.AppInfo {
+		if appInfo.Source != AppInfoSourceFieldNumber {
+			continue
+		}
+		value := strings.TrimSpace(appInfo.Content)
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 1 {
+			return fmt.Errorf("element %s: invalid %s appinfo %q", e.Name, AppInfoSourceFieldNumber, value)
+		}
+		if err := CheckFieldNumber(number); err != nil {
+			return fmt.Errorf("element %s: %s appinfo: %w", e.Name, AppInfoSourceFieldNumber, err)
+		}
+		e.ProtoFieldNumber = number
+	}
+	return nil
+}
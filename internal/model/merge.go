@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergePolicy decides what happens when merged files define the same option or
+// top-level type
+type MergePolicy struct {
+	// Option is called for an option an earlier file already set. The merged
+	// file takes the later value unless it returns an error.
+	Option func(existing, option ProtoOption) error
+	// Duplicate is called for a top-level name an earlier file already defined.
+	// existing and kind are "message" or "enum", and equal reports whether both
+	// definitions are the same apart from their XSD locations. The earlier
+	// definition is kept unless it returns an error.
+	Duplicate func(name, existing, kind string, equal bool) error
+}
+
+// MergeFiles combines proto files in order. The syntax and package come from
+// the first file, imports are deduplicated in the order they are first seen,
+// and raw declarations written identically in several files are kept once.
+// Options and type names defined in more than one file are resolved by policy.
+func MergeFiles(files []*ProtoFile, policy MergePolicy) (*ProtoFile, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no proto files to merge")
+	}
+	for i, file := range files {
+		if file == nil {
+			return nil, fmt.Errorf("proto file %d is nil", i)
+		}
+	}
+
+	merged := &ProtoFile{
+		Syntax:  files[0].Syntax,
+		Package: files[0].Package,
+	}
+
+	seenImports := make(map[string]bool)
+	seenDeclarations := make(map[string]bool)
+	messages := make(map[string]ProtoMessage)
+	enums := make(map[string]ProtoEnum)
+
+	// duplicate reports whether name is already defined, resolving it with the policy
+	duplicate := func(name, kind string, equal func() bool) (bool, error) {
+		existing := ""
+		if _, ok := messages[name]; ok {
+			existing = "message"
+		} else if _, ok := enums[name]; ok {
+			existing = "enum"
+		}
+		if existing == "" {
+			return false, nil
+		}
+		return true, policy.Duplicate(name, existing, kind, existing == kind && equal())
+	}
+
+	for _, file := range files {
+		for _, imp := range file.Imports {
+			if !seenImports[imp] {
+				seenImports[imp] = true
+				merged.Imports = append(merged.Imports, imp)
+			}
+		}
+
+		for _, option := range file.Options {
+			if existing, ok := merged.Option(option.Key); ok && policy.Option != nil {
+				if err := policy.Option(existing, option); err != nil {
+					return nil, err
+				}
+			}
+			merged.SetOption(option)
+		}
+
+		for _, enum := range file.Enums {
+			found, err := duplicate(enum.Name, "enum", func() bool {
+				return reflect.DeepEqual(enums[enum.Name], enum)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				merged.Enums = append(merged.Enums, enum)
+				enums[enum.Name] = enum
+			}
+		}
+
+		for _, message := range file.Messages {
+			found, err := duplicate(message.Name, "message", func() bool {
+				return reflect.DeepEqual(withoutSource(messages[message.Name]), withoutSource(message))
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				merged.Messages = append(merged.Messages, message)
+				messages[message.Name] = message
+			}
+		}
+
+		for _, declaration := range file.RawDeclarations {
+			if !seenDeclarations[declaration] {
+				seenDeclarations[declaration] = true
+				merged.RawDeclarations = append(merged.RawDeclarations, declaration)
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// withoutSource returns a copy of the message with the XSD locations of it, its
+// fields and its nested messages cleared
+func withoutSource(message ProtoMessage) ProtoMessage {
+	message.SourceFile, message.SourceLine = "", 0
+	fields := make([]ProtoField, len(message.Fields))
+	for i, field := range message.Fields {
+		field.SourceFile, field.SourceLine = "", 0
+		fields[i] = field
+	}
+	message.Fields = fields
+	nested := make([]ProtoMessage, len(message.Messages))
+	for i, child := range message.Messages {
+		nested[i] = withoutSource(child)
+	}
+	message.Messages = nested
+	return message
+}
@@ -0,0 +1,39 @@
+package model
+
+import "encoding/xml"
+
+// The declarations record the line of their start tag, or of its end when the tag
+// spans several lines, so that generated definitions can point back to the XSD.
+
+// UnmarshalXML decodes a complex type and records its line
+func (t *ComplexType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainComplexType ComplexType
+	line, _ := d.InputPos()
+	if err := d.DecodeElement((*plainComplexType)(t), &start); err != nil {
+		return err
+	}
+	t.Line = line
+	return nil
+}
+
+// UnmarshalXML decodes a simple type and records its line
+func (t *SimpleType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainSimpleType SimpleType
+	line, _ := d.InputPos()
+	if err := d.DecodeElement((*plainSimpleType)(t), &start); err != nil {
+		return err
+	}
+	t.Line = line
+	return nil
+}
+
+// UnmarshalXML decodes an attribute and records its line
+func (a *Attribute) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainAttribute Attribute
+	line, _ := d.InputPos()
+	if err := d.DecodeElement((*plainAttribute)(a), &start); err != nil {
+		return err
+	}
+	a.Line = line
+	return nil
+}
@@ -1,13 +1,65 @@
 package model
 
+import "fmt"
+
 // ProtoFile represents a complete protobuf file
 type ProtoFile struct {
 	Syntax   string
 	Package  string
 	Imports  []string
-	Options  map[string]string
+	Options  []ProtoOption
 	Messages []ProtoMessage
 	Enums    []ProtoEnum
+	// RawDeclarations are top-level service and extend blocks read from an
+	// existing proto file, written out as they are after the messages
+	RawDeclarations []string
+}
+
+// ProtoOption is a file option. Quoted values are strings; others are written as
+// they are, e.g. the SPEED constant of optimize_for or true.
+type ProtoOption struct {
+	Key    string
+	Value  string
+	Quoted bool
+}
+
+// StringOption returns a file option with a string value, e.g. go_package
+func StringOption(key, value string) ProtoOption {
+	return ProtoOption{Key: key, Value: value, Quoted: true}
+}
+
+// IdentOption returns a file option with an unquoted value, e.g. optimize_for = SPEED
+func IdentOption(key, value string) ProtoOption {
+	return ProtoOption{Key: key, Value: value}
+}
+
+// Literal returns the value as written in a proto file
+func (o ProtoOption) Literal() string {
+	if o.Quoted {
+		return `"` + o.Value + `"`
+	}
+	return o.Value
+}
+
+// Option returns the file option with the given key
+func (f *ProtoFile) Option(key string) (ProtoOption, bool) {
+	for _, option := range f.Options {
+		if option.Key == key {
+			return option, true
+		}
+	}
+	return ProtoOption{}, false
+}
+
+// SetOption adds a file option, replacing the value of an option with the same key
+func (f *ProtoFile) SetOption(option ProtoOption) {
+	for i := range f.Options {
+		if f.Options[i].Key == option.Key {
+			f.Options[i] = option
+			return
+		}
+	}
+	f.Options = append(f.Options, option)
 }
 
 // ProtoMessage represents a protobuf message definition
@@ -16,6 +68,19 @@ type ProtoMessage struct {
 	Fields   []ProtoField
 	Messages []ProtoMessage // nested messages
 	Enums    []ProtoEnum    // nested enums
+
+	IsDeprecated  bool   // Marked as deprecated in the XSD annotation
+	Comment       string // Comment emitted on the line before the message
+	Documentation string // xs:documentation of the XSD type, used by GenerateMarkdown but not emitted in the proto
+
+	// Options are emitted at the top of the message body, after option deprecated
+	Options []ProtoOption
+
+	ReservedNumbers []int    // Field numbers of removed fields, emitted as reserved
+	ReservedNames   []string // Field names of removed fields, emitted as reserved
+
+	SourceFile string // XSD file declaring the type, when parsed from a file
+	SourceLine int    // Line of the XSD declaration, 0 when unknown
 }
 
 // ProtoField represents a field in a protobuf message
@@ -26,18 +91,67 @@ type ProtoField struct {
 	Label   FieldLabel // optional, required, repeated
 	Options map[string]string
 	Comment string // Inline comment for the field
+	IsMap   bool   // Render as map<KeyType, Type>
+	KeyType string // Key type for map fields
+	Oneof   string // Name of the oneof group containing the field
+
+	// Constraints are the XSD facets restricting the field value
+	Constraints []FieldConstraint
+	// RawOptions are field options emitted as written, e.g. (validate.rules).string.min_len = 1
+	RawOptions []string
+
+	IsDeprecated  bool   // Marked as deprecated in the XSD annotation
+	Documentation string // xs:documentation of the element or attribute
+
+	SourceFile string // XSD file declaring the element or attribute, when parsed from a file
+	SourceLine int    // Line of the XSD declaration, 0 when unknown
 }
 
+// FieldConstraint is a value constraint taken from an XSD facet
+type FieldConstraint struct {
+	Facet string // XSD facet name, e.g. minLength
+	Value string
+}
+
+// AppInfoOptionKey is the ProtoField.Options key holding xs:appinfo content
+// The generator emits it as a comment since arbitrary options need a custom descriptor
+const AppInfoOptionKey = "xsd_appinfo"
+
 // ProtoEnum represents a protobuf enum definition
 type ProtoEnum struct {
-	Name   string
-	Values []ProtoEnumValue
+	Name       string
+	Values     []ProtoEnumValue
+	AllowAlias bool // Several values share a number, which needs option allow_alias
+
+	Documentation string // xs:documentation of the XSD simple type
 }
 
 // ProtoEnumValue represents a value in a protobuf enum
 type ProtoEnumValue struct {
-	Name   string
-	Number int
+	Name    string
+	Number  int
+	Comment string // Documentation of the XSD enumeration, emitted on the line before the value
+}
+
+// Protobuf field numbers run from 1 to MaxFieldNumber, without the numbers from
+// FirstReservedFieldNumber to LastReservedFieldNumber kept for the implementation
+const (
+	MaxFieldNumber           = 536870911
+	FirstReservedFieldNumber = 19000
+	LastReservedFieldNumber  = 19999
+)
+
+// CheckFieldNumber returns an error when protobuf does not accept number as a field number
+func CheckFieldNumber(number int) error {
+	switch {
+	case number < 1:
+		return fmt.Errorf("field number %d must be at least 1", number)
+	case number > MaxFieldNumber:
+		return fmt.Errorf("field number %d exceeds the maximum %d", number, MaxFieldNumber)
+	case number >= FirstReservedFieldNumber && number <= LastReservedFieldNumber:
+		return fmt.Errorf("field number %d is in the range %d to %d reserved by protobuf", number, FirstReservedFieldNumber, LastReservedFieldNumber)
+	}
+	return nil
 }
 
 // FieldLabel represents the label of a protobuf field
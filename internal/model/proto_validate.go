@@ -0,0 +1,102 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// protoScalarTypes are the proto3 scalar value types
+var protoScalarTypes = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true,
+	"bool": true, "string": true, "bytes": true,
+}
+
+// optionImports declare field options only, never field types
+var optionImports = map[string]bool{
+	"validate/validate.proto":     true,
+	"buf/validate/validate.proto": true,
+}
+
+// Validate checks the proto file for internal inconsistencies: unnamed messages,
+// field numbers protobuf does not accept or that repeat within a message, enums
+// whose first value is not 0, and fields of message or enum types the file does
+// not define. All violations are
+// returned. Qualified type names such as google.protobuf.Timestamp are assumed
+// to come from imports; unqualified ones are only checked when the file imports
+// nothing that could define them.
+func (f *ProtoFile) Validate() []error {
+	var errs []error
+
+	defined := make(map[string]bool)
+	collectDefinedTypes("", f.Messages, f.Enums, defined)
+	checkTypes := true
+	for _, imp := range f.Imports {
+		if !strings.HasPrefix(imp, "google/protobuf/") && !optionImports[imp] {
+			checkTypes = false
+		}
+	}
+
+	for i := range f.Enums {
+		errs = append(errs, validateEnum(&f.Enums[i])...)
+	}
+	for i := range f.Messages {
+		errs = append(errs, f.validateMessage(&f.Messages[i], "", defined, checkTypes)...)
+	}
+	return errs
+}
+
+// collectDefinedTypes records the simple and scope-qualified names of all messages and enums
+func collectDefinedTypes(scope string, messages []ProtoMessage, enums []ProtoEnum, defined map[string]bool) {
+	for _, enum := range enums {
+		defined[enum.Name] = true
+		defined[scope+enum.Name] = true
+	}
+	for _, message := range messages {
+		defined[message.Name] = true
+		defined[scope+message.Name] = true
+		collectDefinedTypes(scope+message.Name+".", message.Messages, message.Enums, defined)
+	}
+}
+
+func validateEnum(enum *ProtoEnum) []error {
+	if len(enum.Values) == 0 {
+		return []error{fmt.Errorf("enum %s has no values", enum.Name)}
+	}
+	if enum.Values[0].Number != 0 {
+		return []error{fmt.Errorf("enum %s: first value %s is %d, proto3 requires 0", enum.Name, enum.Values[0].Name, enum.Values[0].Number)}
+	}
+	return nil
+}
+
+func (f *ProtoFile) validateMessage(message *ProtoMessage, scope string, defined map[string]bool, checkTypes bool) []error {
+	var errs []error
+	if message.Name == "" {
+		errs = append(errs, fmt.Errorf("message with empty name found in %q", strings.TrimSuffix(scope, ".")))
+	}
+	name := scope + message.Name
+
+	numbers := make(map[int]string)
+	for _, field := range message.Fields {
+		if err := CheckFieldNumber(field.Number); err != nil {
+			errs = append(errs, fmt.Errorf("message %s: field %s: %w", name, field.Name, err))
+		}
+		if other, taken := numbers[field.Number]; taken {
+			errs = append(errs, fmt.Errorf("message %s: fields %s and %s share number %d", name, other, field.Name, field.Number))
+		} else {
+			numbers[field.Number] = field.Name
+		}
+
+		if checkTypes && !strings.Contains(field.Type, ".") && !protoScalarTypes[field.Type] && !defined[field.Type] {
+			errs = append(errs, fmt.Errorf("message %s: field %s has undefined type %s", name, field.Name, field.Type))
+		}
+	}
+
+	for i := range message.Enums {
+		errs = append(errs, validateEnum(&message.Enums[i])...)
+	}
+	for i := range message.Messages {
+		errs = append(errs, f.validateMessage(&message.Messages[i], name+".", defined, checkTypes)...)
+	}
+	return errs
+}
@@ -0,0 +1,105 @@
+package model
+
+import "encoding/xml"
+
+type particleKind int
+
+const (
+	particleElement particleKind = iota
+	particleChoice
+	particleSequence
+)
+
+// UnmarshalXML decodes a sequence while recording the document order of its children
+func (s *Sequence) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "minOccurs":
+			s.MinOccurs = attr.Value
+		case "maxOccurs":
+			s.MaxOccurs = attr.Value
+		}
+	}
+
+	var order []particleKind
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "element":
+				var element Element
+				if err := d.DecodeElement(&element, &t); err != nil {
+					return err
+				}
+				s.Elements = append(s.Elements, element)
+				order = append(order, particleElement)
+			case "choice":
+				var choice Choice
+				if err := d.DecodeElement(&choice, &t); err != nil {
+					return err
+				}
+				s.Choices = append(s.Choices, choice)
+				order = append(order, particleChoice)
+			case "sequence":
+				var sequence Sequence
+				if err := d.DecodeElement(&sequence, &t); err != nil {
+					return err
+				}
+				s.Sequences = append(s.Sequences, sequence)
+				order = append(order, particleSequence)
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			s.buildChildren(order)
+			return nil
+		}
+	}
+}
+
+// buildChildren points Children into the decoded slices once they no longer grow
+func (s *Sequence) buildChildren(order []particleKind) {
+	var elementIndex, choiceIndex, sequenceIndex int
+	s.Children = make([]Particle, 0, len(order))
+	for _, kind := range order {
+		switch kind {
+		case particleElement:
+			s.Children = append(s.Children, Particle{Element: &s.Elements[elementIndex]})
+			elementIndex++
+		case particleChoice:
+			s.Children = append(s.Children, Particle{Choice: &s.Choices[choiceIndex]})
+			choiceIndex++
+		case particleSequence:
+			s.Children = append(s.Children, Particle{Sequence: &s.Sequences[sequenceIndex]})
+			sequenceIndex++
+		}
+	}
+}
+
+// SequenceOf returns a sequence holding copies of the particles in order
+func SequenceOf(particles ...Particle) *Sequence {
+	s := &Sequence{}
+	order := make([]particleKind, 0, len(particles))
+	for _, particle := range particles {
+		switch {
+		case particle.Element != nil:
+			s.Elements = append(s.Elements, *particle.Element)
+			order = append(order, particleElement)
+		case particle.Choice != nil:
+			s.Choices = append(s.Choices, *particle.Choice)
+			order = append(order, particleChoice)
+		case particle.Sequence != nil:
+			s.Sequences = append(s.Sequences, *particle.Sequence)
+			order = append(order, particleSequence)
+		}
+	}
+	s.buildChildren(order)
+	return s
+}
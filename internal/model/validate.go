@@ -0,0 +1,95 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validate checks the schema for unnamed and duplicate declarations
+// All issues are reported at once, joined into a single error.
+func (s *Schema) Validate() error {
+	if s == nil {
+		return fmt.Errorf("schema is nil")
+	}
+
+	var errs []error
+
+	elementNames := make(map[string]int)
+	for _, element := range s.Elements {
+		if element.Name == "" {
+			errs = append(errs, fmt.Errorf("element with empty name found"))
+			continue
+		}
+		elementNames[element.Name]++
+		if elementNames[element.Name] == 2 {
+			errs = append(errs, fmt.Errorf("duplicate element name %s", element.Name))
+		}
+	}
+
+	// A complex and a simple type sharing a name are renamed apart by the
+	// converter, so only duplicates of the same kind are reported
+	complexTypeNames := make(map[string]int)
+	for _, complexType := range s.ComplexTypes {
+		if complexType.Name == "" {
+			errs = append(errs, fmt.Errorf("complexType with empty name found"))
+			continue
+		}
+		complexTypeNames[complexType.Name]++
+		if complexTypeNames[complexType.Name] == 2 {
+			errs = append(errs, fmt.Errorf("duplicate type name %s", complexType.Name))
+		}
+	}
+
+	simpleTypeNames := make(map[string]int)
+	for _, simpleType := range s.SimpleTypes {
+		if simpleType.Name == "" {
+			errs = append(errs, fmt.Errorf("simpleType with empty name found"))
+			continue
+		}
+		simpleTypeNames[simpleType.Name]++
+		if simpleTypeNames[simpleType.Name] == 2 {
+			errs = append(errs, fmt.Errorf("duplicate type name %s", simpleType.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Warnings reports questionable but valid declarations of the schema and its
+// imports, such as abstract elements that no element can substitute
+func (s *Schema) Warnings() []string {
+	var warnings []string
+
+	schemas := s.allSchemas(make(map[*Schema]bool))
+	members := make(map[string]bool)
+	for _, schema := range schemas {
+		for _, element := range schema.Elements {
+			if head := element.SubstitutionGroup; head != "" {
+				members[head[strings.LastIndex(head, ":")+1:]] = true
+			}
+		}
+	}
+
+	for _, schema := range schemas {
+		for _, element := range schema.Elements {
+			if element.IsAbstract() && !members[element.Name] {
+				warnings = append(warnings, fmt.Sprintf("abstract element %s has no substitution group members", element.Name))
+			}
+		}
+	}
+	return warnings
+}
+
+// allSchemas returns the schema followed by its imported schemas, each once
+func (s *Schema) allSchemas(visited map[*Schema]bool) []*Schema {
+	if s == nil || visited[s] {
+		return nil
+	}
+	visited[s] = true
+	schemas := []*Schema{s}
+	for _, imported := range s.ImportedSchemas {
+		schemas = append(schemas, imported.allSchemas(visited)...)
+	}
+	return schemas
+}
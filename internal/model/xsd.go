@@ -10,11 +10,34 @@ type Schema struct {
 	AttributeFormDefault string        `xml:"attributeFormDefault,attr"`
 	Imports              []Import      `xml:"import"`
 	Includes             []Include     `xml:"include"`
+	Redefines            []Redefine    `xml:"redefine"`
 	Elements             []Element     `xml:"element"`
 	ComplexTypes         []ComplexType `xml:"complexType"`
 	SimpleTypes          []SimpleType  `xml:"simpleType"`
 
+	// Attrs holds the remaining attributes, including the xmlns prefix declarations
+	Attrs []xml.Attr `xml:",any,attr"`
+
 	ImportedSchemas []*Schema `xml:"-"`
+
+	// Location is the file the schema was read from, when parsed from a file
+	Location string `xml:"-"`
+	// Unsupported lists the XSD constructs of the document that are not converted, e.g. xs:group
+	Unsupported []string `xml:"-"`
+}
+
+// NamespaceForPrefix returns the namespace bound to a prefix on the schema element
+// The empty prefix returns the default namespace declared with xmlns
+func (s *Schema) NamespaceForPrefix(prefix string) string {
+	for _, attr := range s.Attrs {
+		if prefix == "" && attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			return attr.Value
+		}
+		if prefix != "" && attr.Name.Space == "xmlns" && attr.Name.Local == prefix {
+			return attr.Value
+		}
+	}
+	return ""
 }
 
 // Element represents an XSD element definition
@@ -25,14 +48,117 @@ type Element struct {
 	MaxOccurs   string       `xml:"maxOccurs,attr"`
 	ComplexType *ComplexType `xml:"complexType"`
 	SimpleType  *SimpleType  `xml:"simpleType"`
+	Annotation  *Annotation  `xml:"annotation"`
+	Ref         string       `xml:"ref,attr"`
+	Default     string       `xml:"default,attr"`
+	Fixed       string       `xml:"fixed,attr"`
+	Nillable    string       `xml:"nillable,attr"`
+
+	SubstitutionGroup string `xml:"substitutionGroup,attr"` // Head element this element may replace
+	Abstract          string `xml:"abstract,attr"`          // "true" when only substitution group members may appear
+	Block             string `xml:"block,attr"`             // Derivations that may not replace the element
+	Final             string `xml:"final,attr"`             // Derivations that may not be used in substitution groups
+
+	// Identity constraints over the element content, which proto cannot express
+	Keys    []IdentityConstraint `xml:"key"`
+	KeyRefs []IdentityConstraint `xml:"keyref"`
+	Uniques []IdentityConstraint `xml:"unique"`
+
+	ProtoFieldNumber int `xml:"-"` // Field number pinned with a proto-field-number appinfo
+	Line             int `xml:"-"` // Line of the declaration in the XSD document
+}
+
+// IsAbstract reports whether the element is declared abstract
+func (e *Element) IsAbstract() bool {
+	return e.Abstract == "true" || e.Abstract == "1"
+}
+
+// IsRef reports whether the element refers to a top-level element declaration
+func (e *Element) IsRef() bool {
+	return e.Ref != ""
+}
+
+// IsAnonymous reports whether the element declares its type inline
+func (e *Element) IsAnonymous() bool {
+	return e.ComplexType != nil || e.SimpleType != nil
+}
+
+// HasInlineType reports whether the element declares an inline complex type,
+// which is converted to a message of its own
+func (e *Element) HasInlineType() bool {
+	return e.ComplexType != nil
 }
 
 // ComplexType represents an XSD complex type definition
 type ComplexType struct {
-	Name       string      `xml:"name,attr"`
-	Sequence   *Sequence   `xml:"sequence"`
-	Choice     *Choice     `xml:"choice"`
-	Attributes []Attribute `xml:"attribute"`
+	Name         string        `xml:"name,attr"`
+	Sequence     *Sequence     `xml:"sequence"`
+	Choice       *Choice       `xml:"choice"`
+	Attributes   []Attribute   `xml:"attribute"`
+	AnyAttribute *AnyAttribute `xml:"anyAttribute"`
+	Annotation   *Annotation   `xml:"annotation"`
+	Abstract     string        `xml:"abstract,attr"` // "true" when the type cannot be used directly
+	Mixed        string        `xml:"mixed,attr"`    // "true" when text may appear between the child elements
+	Block        string        `xml:"block,attr"`    // Derivations that may not be used in place of the type
+	Final        string        `xml:"final,attr"`    // Derivations that may not be derived from the type
+	Assertions   []Assertion   `xml:"assert"`        // XSD 1.1 assertions on the content
+
+	// ComplexContent derives the type from another complex type
+	ComplexContent *ComplexContent `xml:"complexContent"`
+
+	Line int `xml:"-"` // Line of the declaration in the XSD document
+}
+
+// ComplexContent represents the complex content of a type derived from another complex type
+type ComplexContent struct {
+	Extension *Extension `xml:"extension"`
+}
+
+// Extension represents a derivation that adds content to the fields of a base type
+type Extension struct {
+	Base         string        `xml:"base,attr"`
+	Sequence     *Sequence     `xml:"sequence"`
+	Choice       *Choice       `xml:"choice"`
+	Attributes   []Attribute   `xml:"attribute"`
+	AnyAttribute *AnyAttribute `xml:"anyAttribute"`
+}
+
+// IsAbstract reports whether the complex type is declared abstract
+func (ct *ComplexType) IsAbstract() bool {
+	return ct.Abstract == "true" || ct.Abstract == "1"
+}
+
+// Extension returns the complexContent extension the type is derived by, or nil
+func (ct *ComplexType) Extension() *Extension {
+	if ct.ComplexContent == nil {
+		return nil
+	}
+	return ct.ComplexContent.Extension
+}
+
+// OwnContent returns a complex type holding the content the type declares itself:
+// that of its extension for a derived type, and the type itself otherwise.
+// The sequence, choice and attributes are shared with the original.
+func (ct *ComplexType) OwnContent() *ComplexType {
+	extension := ct.Extension()
+	if extension == nil {
+		return ct
+	}
+	return &ComplexType{
+		Name:         ct.Name,
+		Sequence:     extension.Sequence,
+		Choice:       extension.Choice,
+		Attributes:   extension.Attributes,
+		AnyAttribute: extension.AnyAttribute,
+		Annotation:   ct.Annotation,
+		Abstract:     ct.Abstract,
+		Mixed:        ct.Mixed,
+	}
+}
+
+// IsMixed reports whether the complex type has mixed content
+func (ct *ComplexType) IsMixed() bool {
+	return ct.Mixed == "true" || ct.Mixed == "1"
 }
 
 // SimpleType represents an XSD simple type definition
@@ -41,27 +167,51 @@ type SimpleType struct {
 	Restriction *Restriction `xml:"restriction"`
 	Union       *Union       `xml:"union"`
 	List        *List        `xml:"list"`
+	Annotation  *Annotation  `xml:"annotation"`
+	Line        int          `xml:"-"` // Line of the declaration in the XSD document
 }
 
 // Sequence represents an ordered group of elements
 type Sequence struct {
-	Elements  []Element `xml:"element"`
-	MinOccurs string    `xml:"minOccurs,attr"`
-	MaxOccurs string    `xml:"maxOccurs,attr"`
+	Elements  []Element  `xml:"element"`
+	Choices   []Choice   `xml:"choice"`
+	Sequences []Sequence `xml:"sequence"`
+	MinOccurs string     `xml:"minOccurs,attr"`
+	MaxOccurs string     `xml:"maxOccurs,attr"`
+
+	// Children holds the elements, choices and nested sequences in document order
+	Children []Particle `xml:"-"`
+}
+
+// Particle represents a single child of a sequence; exactly one field is set
+type Particle struct {
+	Element  *Element
+	Choice   *Choice
+	Sequence *Sequence
 }
 
 // Choice represents a choice between multiple elements
 type Choice struct {
-	Elements  []Element `xml:"element"`
-	MinOccurs string    `xml:"minOccurs,attr"`
-	MaxOccurs string    `xml:"maxOccurs,attr"`
+	Elements  []Element  `xml:"element"`
+	Sequences []Sequence `xml:"sequence"` // Alternatives made of several elements
+	MinOccurs string     `xml:"minOccurs,attr"`
+	MaxOccurs string     `xml:"maxOccurs,attr"`
 }
 
 // Attribute represents an XSD attribute
 type Attribute struct {
-	Name string `xml:"name,attr"`
-	Type string `xml:"type,attr"`
-	Use  string `xml:"use,attr"`
+	Name       string      `xml:"name,attr"`
+	Type       string      `xml:"type,attr"`
+	Use        string      `xml:"use,attr"`
+	Fixed      string      `xml:"fixed,attr"`
+	Annotation *Annotation `xml:"annotation"`
+	Line       int         `xml:"-"` // Line of the declaration in the XSD document
+}
+
+// AnyAttribute represents an attribute wildcard allowing arbitrary attributes
+type AnyAttribute struct {
+	Namespace       string `xml:"namespace,attr"`
+	ProcessContents string `xml:"processContents,attr"`
 }
 
 // Restriction represents type restrictions
@@ -71,6 +221,18 @@ type Restriction struct {
 	Pattern      *Pattern      `xml:"pattern"`
 	MinLength    *Length       `xml:"minLength"`
 	MaxLength    *Length       `xml:"maxLength"`
+	Assertions   []Assertion   `xml:"assertion"` // XSD 1.1 assertions on the value
+}
+
+// IdentityConstraint represents an xs:key, xs:keyref or xs:unique constraint
+type IdentityConstraint struct {
+	Name  string `xml:"name,attr"`
+	Refer string `xml:"refer,attr"` // Key referenced by an xs:keyref
+}
+
+// Assertion represents an XSD 1.1 xs:assert or xs:assertion
+type Assertion struct {
+	Test string `xml:"test,attr"`
 }
 
 // Pattern represents a pattern restriction
@@ -85,7 +247,8 @@ type Length struct {
 
 // Enumeration represents an enumeration value
 type Enumeration struct {
-	Value string `xml:"value,attr"`
+	Value      string      `xml:"value,attr"`
+	Annotation *Annotation `xml:"annotation"`
 }
 
 // Union represents a union of types
@@ -98,6 +261,24 @@ type List struct {
 	ItemType string `xml:"itemType,attr"`
 }
 
+// Annotation represents an xs:annotation block
+type Annotation struct {
+	Documentation []Documentation `xml:"documentation"`
+	AppInfo       []AppInfo       `xml:"appinfo"`
+}
+
+// Documentation represents human-readable documentation in an annotation
+type Documentation struct {
+	Source  string `xml:"source,attr"`
+	Content string `xml:",chardata"`
+}
+
+// AppInfo represents machine-readable information in an annotation
+type AppInfo struct {
+	Source  string `xml:"source,attr"`
+	Content string `xml:",chardata"`
+}
+
 // Import represents an XSD import directive
 type Import struct {
 	Namespace      string `xml:"namespace,attr"`
@@ -108,3 +289,11 @@ type Import struct {
 type Include struct {
 	SchemaLocation string `xml:"schemaLocation,attr"`
 }
+
+// Redefine represents an XSD redefine directive, which includes a schema while
+// replacing some of its types
+type Redefine struct {
+	SchemaLocation string        `xml:"schemaLocation,attr"`
+	ComplexTypes   []ComplexType `xml:"complexType"`
+	SimpleTypes    []SimpleType  `xml:"simpleType"`
+}
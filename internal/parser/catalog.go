@@ -0,0 +1,174 @@
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SchemaResolver maps an import or include to a local file. It returns an empty
+// path when it does not know the schema, leaving resolution to the parser.
+type SchemaResolver interface {
+	Resolve(namespace, schemaLocation, baseDir string) (string, error)
+}
+
+// SetResolver sets the resolver consulted for every import and include before
+// the schemaLocation and namespace-derived paths
+func (p *Parser) SetResolver(r SchemaResolver) {
+	p.resolver = r
+}
+
+// CatalogResolver resolves schemas through an OASIS XML Catalog. Namespaces are
+// looked up in uri and rewriteURI entries, and schema locations in system and
+// rewriteSystem entries and then uri entries. Relative targets are relative to
+// the catalog file that declares them.
+type CatalogResolver struct {
+	uris           map[string]string
+	systems        map[string]string
+	rewriteURIs    []catalogRewrite
+	rewriteSystems []catalogRewrite
+}
+
+type catalogRewrite struct {
+	prefix  string
+	replace string
+}
+
+// catalogEntries are the entries of a catalog or of a group within it
+type catalogEntries struct {
+	URIs []struct {
+		Name string `xml:"name,attr"`
+		URI  string `xml:"uri,attr"`
+	} `xml:"uri"`
+	Systems []struct {
+		SystemID string `xml:"systemId,attr"`
+		URI      string `xml:"uri,attr"`
+	} `xml:"system"`
+	RewriteURIs []struct {
+		Prefix  string `xml:"uriStartString,attr"`
+		Replace string `xml:"rewritePrefix,attr"`
+	} `xml:"rewriteURI"`
+	RewriteSystems []struct {
+		Prefix  string `xml:"systemIdStartString,attr"`
+		Replace string `xml:"rewritePrefix,attr"`
+	} `xml:"rewriteSystem"`
+	NextCatalogs []struct {
+		Catalog string `xml:"catalog,attr"`
+	} `xml:"nextCatalog"`
+	Groups []catalogEntries `xml:"group"`
+}
+
+// NewCatalogResolver reads an OASIS XML Catalog file and the catalogs it chains
+// to with nextCatalog
+func NewCatalogResolver(catalogPath string) (*CatalogResolver, error) {
+	r := &CatalogResolver{
+		uris:    make(map[string]string),
+		systems: make(map[string]string),
+	}
+	if err := r.load(catalogPath, make(map[string]bool)); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *CatalogResolver) load(catalogPath string, loaded map[string]bool) error {
+	absPath, err := filepath.Abs(catalogPath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path for %s: %w", catalogPath, err)
+	}
+	if loaded[absPath] {
+		return nil
+	}
+	loaded[absPath] = true
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to read catalog: %w", err)
+	}
+	var entries catalogEntries
+	if err := xml.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse catalog %s: %w", catalogPath, err)
+	}
+	return r.add(&entries, filepath.Dir(absPath), loaded)
+}
+
+// add records the entries, keeping the first mapping of each name as the catalog
+// specification requires
+func (r *CatalogResolver) add(entries *catalogEntries, catalogDir string, loaded map[string]bool) error {
+	target := func(uri string) string {
+		uri = strings.TrimPrefix(uri, "file://")
+		if filepath.IsAbs(uri) || strings.Contains(uri, "://") {
+			return uri
+		}
+		return filepath.Join(catalogDir, uri)
+	}
+
+	for _, entry := range entries.URIs {
+		if _, exists := r.uris[entry.Name]; !exists {
+			r.uris[entry.Name] = target(entry.URI)
+		}
+	}
+	for _, entry := range entries.Systems {
+		if _, exists := r.systems[entry.SystemID]; !exists {
+			r.systems[entry.SystemID] = target(entry.URI)
+		}
+	}
+	for _, entry := range entries.RewriteURIs {
+		r.rewriteURIs = append(r.rewriteURIs, catalogRewrite{prefix: entry.Prefix, replace: target(entry.Replace)})
+	}
+	for _, entry := range entries.RewriteSystems {
+		r.rewriteSystems = append(r.rewriteSystems, catalogRewrite{prefix: entry.Prefix, replace: target(entry.Replace)})
+	}
+	for i := range entries.Groups {
+		if err := r.add(&entries.Groups[i], catalogDir, loaded); err != nil {
+			return err
+		}
+	}
+	for _, next := range entries.NextCatalogs {
+		if err := r.load(target(next.Catalog), loaded); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resolve returns the local file the catalog maps the namespace or schema location to
+func (r *CatalogResolver) Resolve(namespace, schemaLocation, baseDir string) (string, error) {
+	if namespace != "" {
+		if path, ok := r.uris[namespace]; ok {
+			return path, nil
+		}
+		if path, ok := rewrite(r.rewriteURIs, namespace); ok {
+			return path, nil
+		}
+	}
+	if schemaLocation != "" {
+		if path, ok := r.systems[schemaLocation]; ok {
+			return path, nil
+		}
+		if path, ok := rewrite(r.rewriteSystems, schemaLocation); ok {
+			return path, nil
+		}
+		if path, ok := r.uris[schemaLocation]; ok {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// rewrite applies the rewrite entry with the longest matching prefix
+func rewrite(rewrites []catalogRewrite, name string) (string, bool) {
+	best := -1
+	for i, rw := range rewrites {
+		if strings.HasPrefix(name, rw.prefix) && (best < 0 || len(rw.prefix) > len(rewrites[best].prefix)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	rest := strings.TrimPrefix(name, rewrites[best].prefix)
+	return filepath.Join(rewrites[best].replace, filepath.FromSlash(rest)), true
+}
@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"strings"
+
+	xsderrors "github.com/i-icc/xsd2proto/internal/errors"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// ValidateCompleteness checks that every type reference of the schema and its
+// imports names a built-in XSD type or a type declared in one of the schemas:
+// the types of elements and attributes, the bases of extensions and
+// restrictions, and the item and member types of lists and unions. Each
+// reference that cannot be resolved is returned as an *UnresolvedTypeError.
+// Types are matched by local name, as the converter looks them up.
+func (p *Parser) ValidateCompleteness(schema *model.Schema) []error {
+	if schema == nil {
+		return nil
+	}
+
+	checker := &completenessChecker{declared: make(map[string]bool)}
+	schemas := collectSchemaTree(schema, make(map[*model.Schema]bool))
+	for _, s := range schemas {
+		for _, complexType := range s.ComplexTypes {
+			checker.declared[complexType.Name] = true
+		}
+		for _, simpleType := range s.SimpleTypes {
+			checker.declared[simpleType.Name] = true
+		}
+	}
+
+	for _, s := range schemas {
+		checker.schema = s
+		for i := range s.Elements {
+			checker.checkElement(&s.Elements[i], "")
+		}
+		for i := range s.ComplexTypes {
+			checker.checkComplexType(&s.ComplexTypes[i], "complexType "+s.ComplexTypes[i].Name)
+		}
+		for i := range s.SimpleTypes {
+			checker.checkSimpleType(&s.SimpleTypes[i], "simpleType "+s.SimpleTypes[i].Name)
+		}
+	}
+	return checker.errs
+}
+
+// collectSchemaTree returns the schema and its imports, each once
+func collectSchemaTree(schema *model.Schema, visited map[*model.Schema]bool) []*model.Schema {
+	if schema == nil || visited[schema] {
+		return nil
+	}
+	visited[schema] = true
+	schemas := []*model.Schema{schema}
+	for _, imported := range schema.ImportedSchemas {
+		schemas = append(schemas, collectSchemaTree(imported, visited)...)
+	}
+	return schemas
+}
+
+// completenessChecker collects the unresolved type references of a schema tree
+type completenessChecker struct {
+	declared map[string]bool // Local names of the complex and simple types of all schemas
+	schema   *model.Schema   // Schema whose declarations are being checked, for its prefixes
+	errs     []error
+}
+
+// checkType records typeName as unresolved unless it is built in or declared
+func (c *completenessChecker) checkType(typeName, usedIn string) {
+	if typeName == "" {
+		return
+	}
+	prefix, local := "", typeName
+	if i := strings.Index(typeName, ":"); i >= 0 {
+		prefix, local = typeName[:i], typeName[i+1:]
+	}
+
+	namespace := c.schema.NamespaceForPrefix(prefix)
+	if namespace == xsdNamespace || (namespace == "" && (prefix == "xs" || prefix == "xsd")) {
+		return
+	}
+	if c.declared[local] {
+		return
+	}
+	c.errs = append(c.errs, &xsderrors.UnresolvedTypeError{TypeName: typeName, UsedIn: usedIn})
+}
+
+// within returns the description of a declaration nested in parent
+func within(parent, declaration string) string {
+	if parent == "" {
+		return declaration
+	}
+	return parent + ", " + declaration
+}
+
+func (c *completenessChecker) checkElement(element *model.Element, parent string) {
+	usedIn := within(parent, "element "+element.Name)
+	c.checkType(element.Type, usedIn)
+	if element.ComplexType != nil {
+		c.checkComplexType(element.ComplexType, usedIn)
+	}
+	if element.SimpleType != nil {
+		c.checkSimpleType(element.SimpleType, usedIn)
+	}
+}
+
+func (c *completenessChecker) checkComplexType(complexType *model.ComplexType, usedIn string) {
+	if extension := complexType.Extension(); extension != nil {
+		c.checkType(extension.Base, usedIn)
+	}
+	content := complexType.OwnContent()
+	if content.Sequence != nil {
+		c.checkSequence(content.Sequence, usedIn)
+	}
+	if content.Choice != nil {
+		c.checkChoice(content.Choice, usedIn)
+	}
+	for _, attribute := range content.Attributes {
+		c.checkType(attribute.Type, within(usedIn, "attribute "+attribute.Name))
+	}
+}
+
+func (c *completenessChecker) checkSequence(sequence *model.Sequence, usedIn string) {
+	for _, child := range sequence.Children {
+		switch {
+		case child.Element != nil:
+			c.checkElement(child.Element, usedIn)
+		case child.Choice != nil:
+			c.checkChoice(child.Choice, usedIn)
+		case child.Sequence != nil:
+			c.checkSequence(child.Sequence, usedIn)
+		}
+	}
+}
+
+func (c *completenessChecker) checkChoice(choice *model.Choice, usedIn string) {
+	for i := range choice.Elements {
+		c.checkElement(&choice.Elements[i], usedIn)
+	}
+	for i := range choice.Sequences {
+		c.checkSequence(&choice.Sequences[i], usedIn)
+	}
+}
+
+func (c *completenessChecker) checkSimpleType(simpleType *model.SimpleType, usedIn string) {
+	if simpleType.Restriction != nil {
+		c.checkType(simpleType.Restriction.Base, usedIn)
+	}
+	if simpleType.List != nil {
+		c.checkType(simpleType.List.ItemType, usedIn)
+	}
+	if simpleType.Union != nil {
+		for _, member := range strings.Fields(simpleType.Union.MemberTypes) {
+			c.checkType(member, usedIn)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// FuzzParse feeds arbitrary input to Parse, which must return either a schema
+// or an error without panicking or leaving goroutines behind
+func FuzzParse(f *testing.F) {
+	seeds, err := filepath.Glob(filepath.Join("..", "..", "examples", "*", "*.xsd"))
+	if err != nil {
+		f.Fatalf("Failed to list example schemas: %v", err)
+	}
+	for _, seed := range seeds {
+		data, err := os.ReadFile(seed)
+		if err != nil {
+			f.Fatalf("Failed to read %s: %v", seed, err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte(""))
+	f.Add([]byte(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"/>`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		goroutines := runtime.NumGoroutine()
+
+		p := New()
+		schema, err := p.Parse(bytes.NewReader(data))
+		if err == nil {
+			if schema == nil {
+				t.Fatal("Parse returned neither a schema nor an error")
+			}
+			_, _ = p.Validate(schema)
+		} else if schema != nil {
+			t.Fatalf("Parse returned a schema along with error %v", err)
+		}
+
+		// Give goroutines started by the fuzzing engine a moment to settle
+		for deadline := time.Now().Add(100 * time.Millisecond); runtime.NumGoroutine() > goroutines; {
+			if time.Now().After(deadline) {
+				t.Fatalf("Parse leaked goroutines: %d before, %d after", goroutines, runtime.NumGoroutine())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFetchTimeout bounds each download of an HTTPResolver
+const DefaultFetchTimeout = 30 * time.Second
+
+// HTTPResolver fetches schemas with an http or https schemaLocation and stores
+// them in a cache directory, so later runs work without network access. Each
+// schema is kept as <CacheDir>/<sha256 of the URL>/<file name of the URL>;
+// relative locations inside a fetched schema resolve against its URL.
+type HTTPResolver struct {
+	Client   *http.Client
+	CacheDir string
+
+	mu        sync.Mutex
+	sources   map[string]string // URL of each fetched schema, by its cache directory
+	temporary bool              // CacheDir was created by NewHTTPResolver and is removed by Close
+}
+
+// NewHTTPResolver creates a resolver downloading with the given timeout into
+// cacheDir. An empty cacheDir uses a temporary directory that Close removes.
+func NewHTTPResolver(timeout time.Duration, cacheDir string) (*HTTPResolver, error) {
+	temporary := cacheDir == ""
+	if temporary {
+		dir, err := os.MkdirTemp("", "xsd2proto-schemas")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create schema cache: %w", err)
+		}
+		cacheDir = dir
+	}
+	cacheDir, err := filepath.Abs(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve schema cache: %w", err)
+	}
+	return &HTTPResolver{
+		Client:    &http.Client{Timeout: timeout},
+		CacheDir:  cacheDir,
+		temporary: temporary,
+	}, nil
+}
+
+// Close removes the temporary cache directory and the schemas fetched into it.
+// A cache directory given to NewHTTPResolver is kept for later runs.
+func (r *HTTPResolver) Close() error {
+	if !r.temporary {
+		return nil
+	}
+	if err := os.RemoveAll(r.CacheDir); err != nil {
+		return fmt.Errorf("failed to remove schema cache: %w", err)
+	}
+	return nil
+}
+
+// Resolve downloads remote schemas, or returns them from the cache
+// Other locations are left to the parser.
+func (r *HTTPResolver) Resolve(namespace, schemaLocation, baseDir string) (string, error) {
+	location, ok := r.remoteLocation(schemaLocation, baseDir)
+	if !ok {
+		return "", nil
+	}
+
+	sum := sha256.Sum256([]byte(location))
+	dir, err := filepath.Abs(filepath.Join(r.CacheDir, hex.EncodeToString(sum[:])))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve schema cache: %w", err)
+	}
+	name := path.Base(strings.SplitN(location, "?", 2)[0])
+	if name == "" || name == "/" || name == "." {
+		name = "schema.xsd"
+	}
+	cachedPath := filepath.Join(dir, name)
+
+	r.mu.Lock()
+	if r.sources == nil {
+		r.sources = make(map[string]string)
+	}
+	r.sources[dir] = location
+	r.mu.Unlock()
+
+	if _, err := os.Stat(cachedPath); err == nil {
+		return cachedPath, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to read schema cache: %w", err)
+	}
+
+	if err := r.fetch(location, cachedPath); err != nil {
+		return "", err
+	}
+	return cachedPath, nil
+}
+
+// remoteLocation returns the absolute URL of a schema location when it is remote,
+// either directly or relative to a fetched schema
+func (r *HTTPResolver) remoteLocation(schemaLocation, baseDir string) (string, bool) {
+	if schemaLocation == "" {
+		return "", false
+	}
+	if strings.HasPrefix(schemaLocation, "http://") || strings.HasPrefix(schemaLocation, "https://") {
+		return schemaLocation, true
+	}
+
+	dir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return "", false
+	}
+	r.mu.Lock()
+	source, fetched := r.sources[dir]
+	r.mu.Unlock()
+	if !fetched {
+		return "", false
+	}
+	base, err := url.Parse(source)
+	if err != nil {
+		return "", false
+	}
+	ref, err := url.Parse(filepath.ToSlash(schemaLocation))
+	if err != nil {
+		return "", false
+	}
+	return base.ResolveReference(ref).String(), true
+}
+
+// fetch downloads a schema and stores it atomically at cachedPath
+func (r *HTTPResolver) fetch(location, cachedPath string) error {
+	client := r.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultFetchTimeout}
+	}
+
+	resp, err := client.Get(location)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch %s: %s", location, resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachedPath), 0755); err != nil {
+		return fmt.Errorf("failed to create schema cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cachedPath), ".download-*")
+	if err != nil {
+		return fmt.Errorf("failed to create schema cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to fetch %s: %w", location, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write schema cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), cachedPath); err != nil {
+		return fmt.Errorf("failed to write schema cache: %w", err)
+	}
+	return nil
+}
+
+// ResolverChain tries each resolver in turn until one knows the schema
+type ResolverChain []SchemaResolver
+
+// Resolve returns the first path found by a resolver of the chain
+func (c ResolverChain) Resolve(namespace, schemaLocation, baseDir string) (string, error) {
+	for _, resolver := range c {
+		path, err := resolver.Resolve(namespace, schemaLocation, baseDir)
+		if err != nil || path != "" {
+			return path, err
+		}
+	}
+	return "", nil
+}
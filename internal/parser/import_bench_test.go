@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeImportingSchema writes a root schema importing n synthetic schemas, each
+// declaring a number of complex types, and returns the root path
+func writeImportingSchema(tb testing.TB, dir string, n int) string {
+	tb.Helper()
+	var imports strings.Builder
+	for i := 0; i < n; i++ {
+		namespace := fmt.Sprintf("http://example.com/part%d", i)
+		var types strings.Builder
+		for j := 0; j < 200; j++ {
+			fmt.Fprintf(&types, `  <xs:complexType name="Type%d"><xs:sequence><xs:element name="value" type="xs:string"/></xs:sequence></xs:complexType>`+"\n", j)
+		}
+		part := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="%s">
+%s</xs:schema>`, namespace, types.String())
+		name := fmt.Sprintf("part%d.xsd", i)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(part), 0644); err != nil {
+			tb.Fatalf("Failed to write %s: %v", name, err)
+		}
+		fmt.Fprintf(&imports, `  <xs:import namespace="%s" schemaLocation="%s"/>`+"\n", namespace, name)
+	}
+
+	root := filepath.Join(dir, "root.xsd")
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/root">
+` + imports.String() + `</xs:schema>`
+	if err := os.WriteFile(root, []byte(content), 0644); err != nil {
+		tb.Fatalf("Failed to write root schema: %v", err)
+	}
+	return root
+}
+
+func benchmarkParseImports(b *testing.B, maxParallel int) {
+	root := writeImportingSchema(b, b.TempDir(), 10)
+	p := New()
+	p.SetMaxParallelImports(maxParallel)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.ParseFileWithImports(root); err != nil {
+			b.Fatalf("Parse failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkParseImportsSequential(b *testing.B) {
+	benchmarkParseImports(b, 1)
+}
+
+func BenchmarkParseImportsParallel(b *testing.B) {
+	benchmarkParseImports(b, 8)
+}
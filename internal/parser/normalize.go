@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// NormalizeSchema promotes the anonymous types of nested elements to named
+// top-level types, so that every field refers to its type by name. A promoted
+// type is named after its element with a "Type" suffix, e.g. an inline type of
+// element customer becomes customerType, and a number is appended when that
+// name is taken. Inline complex types of top-level elements stay in place since
+// they are already converted to messages named after the element; the types
+// nested inside them are promoted. Imported and included schemas are normalized
+// as well. The schema is modified in place and returned.
+func (p *Parser) NormalizeSchema(s *model.Schema) *model.Schema {
+	p.normalizeSchema(s, make(map[*model.Schema]bool))
+	return s
+}
+
+func (p *Parser) normalizeSchema(s *model.Schema, visited map[*model.Schema]bool) {
+	if s == nil || visited[s] {
+		return
+	}
+	visited[s] = true
+
+	n := &normalizer{taken: make(map[string]bool)}
+	for _, complexType := range s.ComplexTypes {
+		n.taken[strings.ToLower(complexType.Name)] = true
+	}
+	for _, simpleType := range s.SimpleTypes {
+		n.taken[strings.ToLower(simpleType.Name)] = true
+	}
+	for _, element := range s.Elements {
+		n.taken[strings.ToLower(element.Name)] = true
+	}
+
+	for i := range s.ComplexTypes {
+		n.walkComplexType(&s.ComplexTypes[i])
+	}
+	for i := range s.Elements {
+		element := &s.Elements[i]
+		if element.ComplexType != nil {
+			n.walkComplexType(element.ComplexType)
+		} else {
+			n.promote(element)
+		}
+	}
+
+	s.ComplexTypes = append(s.ComplexTypes, n.complexTypes...)
+	s.SimpleTypes = append(s.SimpleTypes, n.simpleTypes...)
+
+	for _, imported := range s.ImportedSchemas {
+		p.normalizeSchema(imported, visited)
+	}
+}
+
+// normalizer collects the types promoted from one schema
+type normalizer struct {
+	taken        map[string]bool // Lower-cased names of the types and elements of the schema
+	complexTypes []model.ComplexType
+	simpleTypes  []model.SimpleType
+}
+
+// walkComplexType promotes the inline types of the elements of a complex type
+func (n *normalizer) walkComplexType(complexType *model.ComplexType) {
+	complexType = complexType.OwnContent()
+	if complexType.Sequence != nil {
+		n.walkSequence(complexType.Sequence)
+	}
+	if complexType.Choice != nil {
+		n.walkChoice(complexType.Choice)
+	}
+}
+
+// walkSequence promotes the inline types of a sequence, including its nested
+// choices and sequences. Children point into these slices, so they follow.
+func (n *normalizer) walkSequence(sequence *model.Sequence) {
+	for i := range sequence.Elements {
+		n.promote(&sequence.Elements[i])
+	}
+	for i := range sequence.Choices {
+		n.walkChoice(&sequence.Choices[i])
+	}
+	for i := range sequence.Sequences {
+		n.walkSequence(&sequence.Sequences[i])
+	}
+}
+
+func (n *normalizer) walkChoice(choice *model.Choice) {
+	for i := range choice.Elements {
+		n.promote(&choice.Elements[i])
+	}
+	for i := range choice.Sequences {
+		n.walkSequence(&choice.Sequences[i])
+	}
+}
+
+// promote moves the inline type of an element to the top-level lists and refers
+// to it by name. Types nested in a promoted complex type are promoted after it.
+func (n *normalizer) promote(element *model.Element) {
+	switch {
+	case element.ComplexType != nil:
+		complexType := *element.ComplexType
+		complexType.Name = n.uniqueName(element.Name + "Type")
+		element.ComplexType = nil
+		element.Type = complexType.Name
+
+		// Keep the parent before the types nested in it
+		index := len(n.complexTypes)
+		n.complexTypes = append(n.complexTypes, model.ComplexType{})
+		n.walkComplexType(&complexType)
+		n.complexTypes[index] = complexType
+	case element.SimpleType != nil:
+		simpleType := *element.SimpleType
+		simpleType.Name = n.uniqueName(element.Name + "Type")
+		element.SimpleType = nil
+		element.Type = simpleType.Name
+		n.simpleTypes = append(n.simpleTypes, simpleType)
+	}
+}
+
+// uniqueName returns name, or name with the first free number appended
+func (n *normalizer) uniqueName(name string) string {
+	candidate := name
+	for i := 2; n.taken[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	n.taken[strings.ToLower(candidate)] = true
+	return candidate
+}
@@ -1,20 +1,51 @@
 package parser
 
 import (
+	"bytes"
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 
+	xsderrors "github.com/i-icc/xsd2proto/internal/errors"
 	"github.com/i-icc/xsd2proto/internal/model"
 )
 
-type Parser struct{}
+// DefaultMaxRecursionDepth is the deepest nesting of imports, includes and
+// redefines that ParseFileWithImports follows by default
+const DefaultMaxRecursionDepth = 50
+
+type Parser struct {
+	maxParallelImports int            // Imports and includes of one schema parsed at the same time
+	maxRecursionDepth  int            // Deepest nesting of imports, includes and redefines followed
+	resolver           SchemaResolver // Consulted first for the file of every import and include
+}
 
 func New() *Parser {
-	return &Parser{}
+	return &Parser{maxParallelImports: runtime.NumCPU(), maxRecursionDepth: DefaultMaxRecursionDepth}
+}
+
+// SetMaxRecursionDepth sets how deeply nested imports, includes and redefines are
+// followed before parsing fails with a *errors.MaxRecursionDepthError. A schema
+// imported by the parsed file is at depth 1. 0 or less restores the default of 50.
+func (p *Parser) SetMaxRecursionDepth(n int) {
+	if n <= 0 {
+		n = DefaultMaxRecursionDepth
+	}
+	p.maxRecursionDepth = n
+}
+
+// SetMaxParallelImports sets how many imports and includes of a schema are read
+// and decoded concurrently. 1 or less parses them one at a time. The resulting
+// schema tree is the same either way.
+func (p *Parser) SetMaxParallelImports(n int) {
+	p.maxParallelImports = n
 }
 
 func (p *Parser) ParseFile(filePath string) (*model.Schema, error) {
@@ -24,112 +55,426 @@ func (p *Parser) ParseFile(filePath string) (*model.Schema, error) {
 	}
 	defer file.Close()
 
-	return p.Parse(file)
+	schema, err := p.Parse(file)
+	var parseErr *xsderrors.ParseError
+	if errors.As(err, &parseErr) {
+		parseErr.File = filePath
+	}
+	return schema, err
 }
 
+// Parse reads an XSD document. Syntax and structure errors are reported as
+// *errors.ParseError with the line and column where decoding stopped.
 func (p *Parser) Parse(reader io.Reader) (*model.Schema, error) {
-	var schema model.Schema
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XSD: %w", err)
+	}
 
-	decoder := xml.NewDecoder(reader)
+	var schema model.Schema
+	decoder := xml.NewDecoder(bytes.NewReader(data))
 	if err := decoder.Decode(&schema); err != nil {
-		return nil, fmt.Errorf("failed to parse XSD: %w", err)
+		return nil, newParseError(data, decoder.InputOffset(), err)
 	}
+	schema.Unsupported = unsupportedFeatures(data)
 
 	return &schema, nil
 }
 
-func (p *Parser) Validate(schema *model.Schema) error {
-	if schema == nil {
-		return fmt.Errorf("schema is nil")
+// newParseError locates a decoding error at the given byte offset of the document
+func newParseError(data []byte, offset int64, err error) *xsderrors.ParseError {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
 	}
+	consumed := data[:offset]
+	line := bytes.Count(consumed, []byte("\n")) + 1
+	column := len(consumed) - bytes.LastIndexByte(consumed, '\n')
 
-	elementNames := make(map[string]int)
-	for _, element := range schema.Elements {
-		if element.Name == "" {
-			return fmt.Errorf("element with empty name found")
-		}
-		elementNames[element.Name]++
+	msg := err.Error()
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		msg = syntaxErr.Msg
 	}
+	return &xsderrors.ParseError{Line: line, Column: column, Msg: "failed to parse XSD: " + msg, Err: err}
+}
 
-	typeNames := make(map[string]int)
-	for _, complexType := range schema.ComplexTypes {
-		if complexType.Name == "" {
-			return fmt.Errorf("complexType with empty name found")
-		}
-		typeNames[complexType.Name]++
+// ParseBytes parses an XSD document held in memory
+func (p *Parser) ParseBytes(data []byte) (*model.Schema, error) {
+	return p.Parse(bytes.NewReader(data))
+}
+
+// ParseString parses an XSD document given as a string
+func (p *Parser) ParseString(s string) (*model.Schema, error) {
+	return p.Parse(strings.NewReader(s))
+}
+
+// Validate checks the schema, reporting all issues at once
+// Warnings describe valid declarations that are likely mistakes, such as abstract
+// elements without substitution group members; they do not fail validation.
+func (p *Parser) Validate(schema *model.Schema) ([]string, error) {
+	if err := schema.Validate(); err != nil {
+		return nil, err
 	}
+	return schema.Warnings(), nil
+}
 
-	for _, simpleType := range schema.SimpleTypes {
-		if simpleType.Name == "" {
-			return fmt.Errorf("simpleType with empty name found")
-		}
-		typeNames[simpleType.Name]++
+func (p *Parser) ParseFileWithImports(filePath string) (*model.Schema, error) {
+	return p.ParseFileWithImportsContext(context.Background(), filePath)
+}
+
+// ParseFileWithImportsContext parses an XSD file with its imports/includes, stopping
+// with the context's error once it is cancelled or its deadline passes
+func (p *Parser) ParseFileWithImportsContext(ctx context.Context, filePath string) (*model.Schema, error) {
+	state := &importState{
+		processedFiles: make(map[string]bool),
+		prefetched:     make(map[string]*parsedFile),
 	}
+	return p.parseFileRecursive(ctx, filePath, state, 0)
+}
 
-	return nil
+// importState tracks the files of one ParseFileWithImports call
+// Files are assembled into the schema tree depth-first in document order, as if
+// parsed sequentially; the siblings of each level are only read and decoded ahead
+// in parallel, so the tree does not depend on goroutine scheduling.
+type importState struct {
+	processedFiles map[string]bool
+
+	mu         sync.Mutex
+	prefetched map[string]*parsedFile // Files decoded ahead, by absolute path
 }
 
-func (p *Parser) ParseFileWithImports(filePath string) (*model.Schema, error) {
-	processedFiles := make(map[string]bool)
-	return p.parseFileRecursive(filePath, processedFiles)
+// parsedFile is the result of decoding a file ahead of its turn
+type parsedFile struct {
+	schema *model.Schema
+	err    error
 }
 
-func (p *Parser) parseFileRecursive(filePath string, processedFiles map[string]bool) (*model.Schema, error) {
+// schemaReference is an import or include of a schema, resolved to a file
+type schemaReference struct {
+	path      string
+	isInclude bool
+	label     string // Location named in error messages
+}
+
+// parseFileRecursive parses a file and the files it references, depth levels
+// below the file ParseFileWithImports started from
+func (p *Parser) parseFileRecursive(ctx context.Context, filePath string, state *importState, depth int) (*model.Schema, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("parsing %s aborted: %w", filePath, err)
+	}
+	if depth > p.maxRecursionDepth {
+		return nil, &xsderrors.MaxRecursionDepthError{Depth: depth, File: filePath}
+	}
+
 	absPath, err := filepath.Abs(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get absolute path for %s: %w", filePath, err)
 	}
 
-	if processedFiles[absPath] {
+	if state.processedFiles[absPath] {
 		return nil, nil
 	}
-	processedFiles[absPath] = true
+	state.processedFiles[absPath] = true
 
-	schema, err := p.ParseFile(filePath)
+	schema, err := state.take(absPath)
+	if schema == nil && err == nil {
+		schema, err = p.ParseFile(filePath)
+	}
 	if err != nil {
 		return nil, err
 	}
+	schema.Location = absPath
 
-	baseDir := filepath.Dir(filePath)
-	for _, imp := range schema.Imports {
-		var importPath string
-
-		if imp.SchemaLocation != "" {
-			importPath = filepath.Join(baseDir, imp.SchemaLocation)
-		} else if imp.Namespace != "" {
-			derivedPath := p.deriveFilePathFromNamespace(imp.Namespace, baseDir)
-			if derivedPath != "" {
-				importPath = derivedPath
+	if err := p.applyRedefines(ctx, schema, filepath.Dir(filePath), state, depth); err != nil {
+		return nil, err
+	}
+
+	references, err := p.schemaReferences(schema, filepath.Dir(filePath))
+	if err != nil {
+		return nil, err
+	}
+	p.prefetch(ctx, references, state)
+
+	for _, ref := range references {
+		referencedSchema, err := p.parseFileRecursive(ctx, ref.path, state, depth+1)
+		if err != nil {
+			if ref.isInclude {
+				return nil, fmt.Errorf("failed to process include %s: %w", ref.label, err)
+			}
+			return nil, fmt.Errorf("failed to process import %s: %w", ref.label, err)
+		}
+		if referencedSchema != nil {
+			schema.ImportedSchemas = append(schema.ImportedSchemas, referencedSchema)
+		}
+	}
+
+	return schema, nil
+}
+
+// applyRedefines includes the schemas named by redefine directives into the
+// redefining schema, with the types of each redefine block replacing the types
+// of the same name. The redefined types thus keep their original names. A
+// redefined type derives from its original, which no other type can refer to,
+// so the original is merged into it: an extension adds its content after that
+// of the original, and a restriction overrides the facets of the original.
+func (p *Parser) applyRedefines(ctx context.Context, schema *model.Schema, baseDir string, state *importState, depth int) error {
+	for _, redefine := range schema.Redefines {
+		if redefine.SchemaLocation == "" {
+			continue
+		}
+		redefinePath, err := p.resolveLocation("", redefine.SchemaLocation, baseDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve redefine %s: %w", redefine.SchemaLocation, err)
+		}
+		base, err := p.parseFileRecursive(ctx, redefinePath, state, depth+1)
+		if err == nil && base == nil {
+			// Already reached through another directive; its types are still needed here
+			base, err = p.ParseFile(redefinePath)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to process redefine %s: %w", redefine.SchemaLocation, err)
+		}
+
+		complexTypes := make(map[string]model.ComplexType)
+		for _, complexType := range redefine.ComplexTypes {
+			complexTypes[complexType.Name] = complexType
+		}
+		simpleTypes := make(map[string]model.SimpleType)
+		for _, simpleType := range redefine.SimpleTypes {
+			simpleTypes[simpleType.Name] = simpleType
+		}
+
+		var mergedComplexTypes []model.ComplexType
+		for _, complexType := range base.ComplexTypes {
+			if redefined, ok := complexTypes[complexType.Name]; ok {
+				complexType = redefineComplexType(complexType, redefined)
 			}
+			mergedComplexTypes = append(mergedComplexTypes, complexType)
+		}
+		var mergedSimpleTypes []model.SimpleType
+		for _, simpleType := range base.SimpleTypes {
+			if redefined, ok := simpleTypes[simpleType.Name]; ok {
+				simpleType = redefineSimpleType(simpleType, redefined)
+			}
+			mergedSimpleTypes = append(mergedSimpleTypes, simpleType)
+		}
+
+		schema.ComplexTypes = append(mergedComplexTypes, schema.ComplexTypes...)
+		schema.SimpleTypes = append(mergedSimpleTypes, schema.SimpleTypes...)
+		schema.Elements = append(base.Elements, schema.Elements...)
+		schema.ImportedSchemas = append(schema.ImportedSchemas, base.ImportedSchemas...)
+	}
+	return nil
+}
+
+// redefineComplexType merges the original of a redefined complex type into the
+// redefinition when it extends the original
+func redefineComplexType(original, redefined model.ComplexType) model.ComplexType {
+	extension := redefined.Extension()
+	if extension == nil || !isSelfReference(extension.Base, redefined.Name) {
+		return redefined
+	}
+
+	content := original.OwnContent()
+	merged := redefined
+	merged.ComplexContent = nil
+	merged.Sequence, merged.Choice = concatContent(content, extension)
+	merged.Attributes = append(append([]model.Attribute{}, content.Attributes...), extension.Attributes...)
+	merged.AnyAttribute = extension.AnyAttribute
+	if merged.AnyAttribute == nil {
+		merged.AnyAttribute = content.AnyAttribute
+	}
+	if merged.Annotation == nil {
+		merged.Annotation = original.Annotation
+	}
+	if merged.Mixed == "" {
+		merged.Mixed = original.Mixed
+	}
+
+	// An original derived from another type keeps that derivation
+	if base := original.Extension(); base != nil {
+		merged.ComplexContent = &model.ComplexContent{Extension: &model.Extension{
+			Base:         base.Base,
+			Sequence:     merged.Sequence,
+			Choice:       merged.Choice,
+			Attributes:   merged.Attributes,
+			AnyAttribute: merged.AnyAttribute,
+		}}
+		merged.Sequence, merged.Choice = nil, nil
+		merged.Attributes, merged.AnyAttribute = nil, nil
+	}
+	return merged
+}
+
+// concatContent returns the content of the original followed by that of the extension
+// Content on one side only is kept as it is.
+func concatContent(original *model.ComplexType, extension *model.Extension) (*model.Sequence, *model.Choice) {
+	before := contentParticles(original.Sequence, original.Choice)
+	after := contentParticles(extension.Sequence, extension.Choice)
+	switch {
+	case len(after) == 0:
+		return original.Sequence, original.Choice
+	case len(before) == 0:
+		return extension.Sequence, extension.Choice
+	}
+	return model.SequenceOf(append(before, after...)...), nil
+}
+
+// contentParticles returns the particles of a content model, with the children of
+// a sequence that occurs exactly once spliced in
+func contentParticles(sequence *model.Sequence, choice *model.Choice) []model.Particle {
+	var particles []model.Particle
+	if sequence != nil {
+		if sequence.MinOccurs == "" && sequence.MaxOccurs == "" {
+			particles = append(particles, sequence.Children...)
+		} else {
+			particles = append(particles, model.Particle{Sequence: sequence})
+		}
+	}
+	if choice != nil {
+		particles = append(particles, model.Particle{Choice: choice})
+	}
+	return particles
+}
+
+// redefineSimpleType merges the original of a redefined simple type into the
+// redefinition when it restricts the original. The facets of the redefinition
+// replace those of the original, and its assertions are added to them.
+func redefineSimpleType(original, redefined model.SimpleType) model.SimpleType {
+	restriction := redefined.Restriction
+	if restriction == nil || !isSelfReference(restriction.Base, redefined.Name) {
+		return redefined
+	}
+
+	merged := original
+	merged.Line = redefined.Line
+	if redefined.Annotation != nil {
+		merged.Annotation = redefined.Annotation
+	}
+	if original.Restriction == nil {
+		// A list or union keeps its definition; the facets have no counterpart
+		return merged
+	}
+
+	facets := *original.Restriction
+	if len(restriction.Enumerations) > 0 {
+		facets.Enumerations = restriction.Enumerations
+	}
+	if restriction.Pattern != nil {
+		facets.Pattern = restriction.Pattern
+	}
+	if restriction.MinLength != nil {
+		facets.MinLength = restriction.MinLength
+	}
+	if restriction.MaxLength != nil {
+		facets.MaxLength = restriction.MaxLength
+	}
+	facets.Assertions = append(append([]model.Assertion{}, original.Restriction.Assertions...), restriction.Assertions...)
+	merged.Restriction = &facets
+	return merged
+}
+
+// isSelfReference reports whether a base type QName names the type itself
+func isSelfReference(base, name string) bool {
+	return base[strings.LastIndex(base, ":")+1:] == name
+}
+
+// schemaReferences resolves the imports and then the includes of a schema to files
+// Imports whose file does not exist are skipped.
+func (p *Parser) schemaReferences(schema *model.Schema, baseDir string) ([]schemaReference, error) {
+	var references []schemaReference
+	for _, imp := range schema.Imports {
+		importPath, err := p.resolveLocation(imp.Namespace, imp.SchemaLocation, baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve import %s: %w", imp.Namespace, err)
+		}
+		if importPath == "" && imp.Namespace != "" {
+			importPath = p.deriveFilePathFromNamespace(imp.Namespace, baseDir)
 		}
 
 		if importPath != "" {
 			if _, err := os.Stat(importPath); err == nil {
-				importedSchema, err := p.parseFileRecursive(importPath, processedFiles)
-				if err != nil {
-					return nil, fmt.Errorf("failed to process import %s: %w", importPath, err)
-				}
-				if importedSchema != nil {
-					schema.ImportedSchemas = append(schema.ImportedSchemas, importedSchema)
-				}
+				references = append(references, schemaReference{path: importPath, label: importPath})
 			}
 		}
 	}
 
 	for _, inc := range schema.Includes {
 		if inc.SchemaLocation != "" {
-			includePath := filepath.Join(baseDir, inc.SchemaLocation)
-			includedSchema, err := p.parseFileRecursive(includePath, processedFiles)
+			includePath, err := p.resolveLocation("", inc.SchemaLocation, baseDir)
 			if err != nil {
-				return nil, fmt.Errorf("failed to process include %s: %w", inc.SchemaLocation, err)
-			}
-			if includedSchema != nil {
-				schema.ImportedSchemas = append(schema.ImportedSchemas, includedSchema)
+				return nil, fmt.Errorf("failed to resolve include %s: %w", inc.SchemaLocation, err)
 			}
+			references = append(references, schemaReference{path: includePath, isInclude: true, label: inc.SchemaLocation})
 		}
 	}
+	return references, nil
+}
 
-	return schema, nil
+// resolveLocation returns the file of a schema from the resolver, falling back to
+// the schemaLocation relative to baseDir. It is empty when neither applies.
+func (p *Parser) resolveLocation(namespace, schemaLocation, baseDir string) (string, error) {
+	if p.resolver != nil {
+		path, err := p.resolver.Resolve(namespace, schemaLocation, baseDir)
+		if err != nil || path != "" {
+			return path, err
+		}
+	}
+	if schemaLocation != "" {
+		return filepath.Join(baseDir, schemaLocation), nil
+	}
+	return "", nil
+}
+
+// prefetch decodes the referenced files not seen yet concurrently, at most
+// maxParallelImports at a time, and waits for all of them
+func (p *Parser) prefetch(ctx context.Context, references []schemaReference, state *importState) {
+	if p.maxParallelImports <= 1 || len(references) < 2 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, p.maxParallelImports)
+	for _, ref := range references {
+		absPath, err := filepath.Abs(ref.path)
+		if err != nil {
+			continue
+		}
+		state.mu.Lock()
+		_, seen := state.prefetched[absPath]
+		if seen || state.processedFiles[absPath] {
+			state.mu.Unlock()
+			continue
+		}
+		result := &parsedFile{}
+		state.prefetched[absPath] = result
+		state.mu.Unlock()
+
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			if ctx.Err() != nil {
+				return
+			}
+			result.schema, result.err = p.ParseFile(path)
+		}(ref.path)
+	}
+	wg.Wait()
+}
+
+// take returns and forgets the prefetched result for a file
+// Both results are nil when the file was not prefetched.
+func (s *importState) take(absPath string) (*model.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok := s.prefetched[absPath]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.prefetched, absPath)
+	return result.schema, result.err
 }
 
 func (p *Parser) deriveFilePathFromNamespace(namespace, baseDir string) string {
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"sort"
+)
+
+const xsdNamespace = "http://www.w3.org/2001/XMLSchema"
+
+// supportedElements are the XSD elements read into the schema model
+var supportedElements = map[string]bool{
+	"schema": true, "import": true, "include": true, "redefine": true,
+	"element": true, "complexType": true, "simpleType": true,
+	"sequence": true, "choice": true, "attribute": true, "anyAttribute": true,
+	"annotation": true, "documentation": true, "appinfo": true,
+	"restriction": true, "enumeration": true, "pattern": true, "minLength": true, "maxLength": true,
+	"union": true, "list": true, "complexContent": true, "extension": true,
+}
+
+// unsupportedFeatures returns the XSD elements of a document that are not read
+// into the schema model, as sorted "xs:<name>" strings
+func unsupportedFeatures(data []byte) []string {
+	seen := make(map[string]bool)
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Space != xsdNamespace {
+			continue
+		}
+		switch {
+		case start.Name.Local == "documentation" || start.Name.Local == "appinfo":
+			// Annotation content is free-form and may hold anything. A failed skip
+			// leaves the decoder at the error, which ends the loop.
+			_ = decoder.Skip()
+		case !supportedElements[start.Name.Local]:
+			seen["xs:"+start.Name.Local] = true
+		}
+	}
+
+	features := make([]string, 0, len(seen))
+	for feature := range seen {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	return features
+}
@@ -0,0 +1,198 @@
+// Package stats summarizes what a conversion read from the XSD and generated.
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// Stats counts the declarations of a schema and its imports together with the
+// definitions of the proto file converted from it
+type Stats struct {
+	Elements     int // Element declarations and references, at any depth
+	ComplexTypes int // Named and anonymous complex types
+	SimpleTypes  int // Named simple types
+	Enums        int // Generated enums, including nested ones
+	Messages     int // Generated messages, including nested ones
+	Fields       int // Fields of all generated messages
+	Imports      int // Imports of the proto file
+
+	// Warnings lists XSD type names that map to the same proto name, so that all
+	// but one of them were renamed
+	Warnings []string
+}
+
+// ComputeStats counts the schema declarations and the proto definitions
+func ComputeStats(schema *model.Schema, protoFile *model.ProtoFile) Stats {
+	var stats Stats
+	var typeNames []string
+
+	visited := make(map[*model.Schema]bool)
+	var walkSchema func(s *model.Schema)
+	walkSchema = func(s *model.Schema) {
+		if s == nil || visited[s] {
+			return
+		}
+		visited[s] = true
+
+		stats.SimpleTypes += len(s.SimpleTypes)
+		for _, simpleType := range s.SimpleTypes {
+			typeNames = append(typeNames, simpleType.Name)
+		}
+		for i := range s.ComplexTypes {
+			typeNames = append(typeNames, s.ComplexTypes[i].Name)
+			stats.countComplexType(&s.ComplexTypes[i])
+		}
+		for i := range s.Elements {
+			if s.Elements[i].HasInlineType() {
+				typeNames = append(typeNames, s.Elements[i].Name)
+			}
+			stats.countElement(&s.Elements[i])
+		}
+		for _, imported := range s.ImportedSchemas {
+			walkSchema(imported)
+		}
+	}
+	walkSchema(schema)
+
+	if protoFile != nil {
+		stats.Imports = len(protoFile.Imports)
+		stats.Enums += len(protoFile.Enums)
+		for i := range protoFile.Messages {
+			stats.countMessage(&protoFile.Messages[i])
+		}
+	}
+
+	stats.Warnings = nameCollisions(typeNames)
+	return stats
+}
+
+func (s *Stats) countElement(element *model.Element) {
+	s.Elements++
+	if element.ComplexType != nil {
+		s.countComplexType(element.ComplexType)
+	}
+}
+
+func (s *Stats) countComplexType(complexType *model.ComplexType) {
+	s.ComplexTypes++
+	complexType = complexType.OwnContent()
+	if complexType.Sequence != nil {
+		s.countSequence(complexType.Sequence)
+	}
+	if complexType.Choice != nil {
+		s.countChoice(complexType.Choice)
+	}
+}
+
+func (s *Stats) countSequence(sequence *model.Sequence) {
+	for i := range sequence.Elements {
+		s.countElement(&sequence.Elements[i])
+	}
+	for i := range sequence.Choices {
+		s.countChoice(&sequence.Choices[i])
+	}
+	for i := range sequence.Sequences {
+		s.countSequence(&sequence.Sequences[i])
+	}
+}
+
+func (s *Stats) countChoice(choice *model.Choice) {
+	for i := range choice.Elements {
+		s.countElement(&choice.Elements[i])
+	}
+	for i := range choice.Sequences {
+		s.countSequence(&choice.Sequences[i])
+	}
+}
+
+func (s *Stats) countMessage(message *model.ProtoMessage) {
+	s.Messages++
+	s.Fields += len(message.Fields)
+	s.Enums += len(message.Enums)
+	for i := range message.Messages {
+		s.countMessage(&message.Messages[i])
+	}
+}
+
+// nameCollisions reports type names that differ only in case or separators and
+// therefore format to the same proto name
+func nameCollisions(names []string) []string {
+	byKey := make(map[string][]string)
+	var keys []string
+	for _, name := range names {
+		key := strings.Map(func(r rune) rune {
+			if r == '_' || r == '-' || r == '.' {
+				return -1
+			}
+			return unicode.ToLower(r)
+		}, name)
+		if key == "" {
+			continue
+		}
+		if len(byKey[key]) == 0 {
+			keys = append(keys, key)
+		}
+		if !containsName(byKey[key], name) {
+			byKey[key] = append(byKey[key], name)
+		}
+	}
+	sort.Strings(keys)
+
+	var warnings []string
+	for _, key := range keys {
+		if colliding := byKey[key]; len(colliding) > 1 {
+			quoted := make([]string, len(colliding))
+			for i, name := range colliding {
+				quoted[i] = fmt.Sprintf("%q", name)
+			}
+			warnings = append(warnings, fmt.Sprintf("types %s map to the same proto name", strings.Join(quoted, ", ")))
+		}
+	}
+	return warnings
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Write prints the statistics as an aligned table followed by the warnings
+func (s Stats) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	rows := []struct {
+		label string
+		count int
+	}{
+		{"XSD elements", s.Elements},
+		{"XSD complex types", s.ComplexTypes},
+		{"XSD simple types", s.SimpleTypes},
+		{"Messages generated", s.Messages},
+		{"Enums generated", s.Enums},
+		{"Fields generated", s.Fields},
+		{"Imports", s.Imports},
+	}
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s:\t%d\n", row.label, row.count)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, warning := range s.Warnings {
+		if _, err := fmt.Fprintf(w, "Warning: %s\n", warning); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package xsd2proto
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProcessManifest converts every XSD file listed in the manifest at manifestPath
+// with the same options, each to the output path derived from its name. The
+// manifest lists one path per line, relative to the manifest's directory or
+// absolute; blank lines and lines starting with # are skipped. A failing file
+// does not stop the others: the paths written and the errors of all failing
+// files, each naming its input, are returned together. Options naming a single
+// output file, such as ReportPath, are rejected, since every file of the
+// manifest would overwrite it.
+func ProcessManifest(manifestPath string, opts ConvertOptions) ([]string, []error) {
+	switch {
+	case opts.OutputPath != "" && !opts.SplitFiles:
+		return nil, []error{fmt.Errorf("cannot set an output path for a manifest; outputs are named after their inputs")}
+	case opts.Output != nil:
+		return nil, []error{fmt.Errorf("cannot process a manifest when writing to a stream")}
+	case opts.OutputZipPath != "":
+		return nil, []error{fmt.Errorf("cannot write a zip archive for a manifest")}
+	}
+	singleOutputs := []struct{ name, path string }{
+		{"report", opts.ReportPath},
+		{"trace", opts.TracePath},
+		{"descriptor", opts.DescriptorPath},
+		{"DOT graph", opts.DotOutputPath},
+		{"Markdown docs", opts.MarkdownDocsPath},
+		{"type graph", opts.TypeGraphPath},
+	}
+	for _, output := range singleOutputs {
+		// A type graph on stdout is written once per file without overwriting
+		if output.path != "" && output.path != "-" {
+			return nil, []error{fmt.Errorf("cannot write a %s file for a manifest; each file would overwrite %s", output.name, output.path)}
+		}
+	}
+
+	inputPaths, err := readManifest(manifestPath)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var written []string
+	var errs []error
+	for _, inputPath := range inputPaths {
+		paths, err := convertFile(inputPath, opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", inputPath, err))
+			continue
+		}
+		written = append(written, paths...)
+	}
+	return written, errs
+}
+
+// readManifest returns the XSD paths listed in a manifest, resolving relative
+// paths against the manifest's directory
+func readManifest(manifestPath string) ([]string, error) {
+	file, err := os.Open(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer file.Close()
+
+	var inputPaths []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(filepath.Dir(manifestPath), line)
+		}
+		inputPaths = append(inputPaths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return inputPaths, nil
+}
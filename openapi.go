@@ -0,0 +1,52 @@
+package xsd2proto
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// openAPIOutputPath returns where the OpenAPI companion of the proto output goes:
+// next to the proto file, or in the output directory when splitting files
+func openAPIOutputPath(inputPath string, opts ConvertOptions) (string, error) {
+	if opts.SplitFiles {
+		name := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+		return filepath.Join(opts.OutputPath, name+".openapi.yaml"), nil
+	}
+	outputPath := opts.OutputPath
+	if outputPath == "" {
+		if inputPath == "" {
+			return "", fmt.Errorf("an output path is required for OpenAPI extensions when writing to a stream")
+		}
+		outputPath = DefaultOutputPath(inputPath)
+	}
+	return strings.TrimSuffix(outputPath, ".proto") + ".openapi.yaml", nil
+}
+
+// writeOpenAPIExtensions writes the OpenAPI companion file when requested
+// The converter must be the one that converted the schema into protoFile.
+func writeOpenAPIExtensions(conv *converter.Converter, schema *model.Schema, protoFile *model.ProtoFile, inputPath string, opts ConvertOptions) error {
+	if !opts.EmitOpenAPI || !opts.writesFiles() {
+		return nil
+	}
+	path, err := openAPIOutputPath(inputPath, opts)
+	if err != nil {
+		return err
+	}
+
+	title := protoFile.Package
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(path), ".openapi.yaml")
+	}
+	content := conv.GenerateOpenAPIExtensions(schema, title, GetVersion())
+	if err := writeToFile(path, content); err != nil {
+		return fmt.Errorf("failed to write OpenAPI extensions: %w", err)
+	}
+	if opts.Verbose {
+		opts.logf("Successfully generated %s\n", path)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package xsd2proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/model"
+	"github.com/i-icc/xsd2proto/internal/stats"
+)
+
+// ConversionReport summarizes a successful conversion for tools and CI jobs.
+// It is written as JSON with the ReportPath option (--report).
+type ConversionReport struct {
+	InputPaths  []string `json:"input_paths"`  // XSD files converted; empty when reading from a stream
+	OutputPaths []string `json:"output_paths"` // Proto files written; empty when writing to a stream
+
+	Schema SchemaReport `json:"schema"`
+	Proto  ProtoReport  `json:"proto"`
+
+	// Renames lists the types whose proto name was already taken, so they were
+	// emitted under a numbered name
+	Renames []TypeRename `json:"renames"`
+	// Unsupported lists the XSD constructs of the schemas that were not converted, e.g. xs:group
+	Unsupported []string `json:"unsupported"`
+	// ResolvedImports lists the files read for imports, includes and redefines
+	ResolvedImports []string `json:"resolved_imports"`
+	// Sources locates the XSD declarations of the messages and their fields
+	Sources []SourceLocation `json:"sources"`
+
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// SchemaReport counts the declarations of the XSD schemas and their imports
+type SchemaReport struct {
+	Elements     int `json:"elements"`
+	ComplexTypes int `json:"complex_types"`
+	SimpleTypes  int `json:"simple_types"`
+}
+
+// ProtoReport counts the definitions of the generated proto file
+type ProtoReport struct {
+	Messages int `json:"messages"`
+	Enums    int `json:"enums"`
+	Fields   int `json:"fields"`
+	Imports  int `json:"imports"`
+}
+
+// TypeRename is an XSD type emitted under another proto name
+type TypeRename struct {
+	Original string `json:"original"`
+	Renamed  string `json:"renamed"`
+}
+
+// SourceLocation is the XSD declaration of a message, or of one of its fields.
+// Nested messages are named with their parents, as in Order.Line.
+type SourceLocation struct {
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+	File    string `json:"file,omitempty"` // Empty when the XSD was read from a stream
+	Line    int    `json:"line"`
+}
+
+var (
+	lastReportMu sync.Mutex
+	lastReport   *ConversionReport
+)
+
+// LastConversionReport returns the report of the most recent successful
+// conversion of this process, or nil when there has been none
+func LastConversionReport() *ConversionReport {
+	lastReportMu.Lock()
+	defer lastReportMu.Unlock()
+	return lastReport
+}
+
+// finishReport builds the report of a successful conversion, keeps it for
+// LastConversionReport and writes it to opts.ReportPath when set
+func finishReport(start time.Time, inputPaths, written []string, roots []*model.Schema, protoFile *model.ProtoFile, convs []*converter.Converter, opts ConvertOptions) error {
+	counts := stats.ComputeStats(&model.Schema{ImportedSchemas: roots}, protoFile)
+	report := &ConversionReport{
+		InputPaths:      append([]string{}, inputPaths...),
+		OutputPaths:     append([]string{}, written...),
+		Schema:          SchemaReport{Elements: counts.Elements, ComplexTypes: counts.ComplexTypes, SimpleTypes: counts.SimpleTypes},
+		Proto:           ProtoReport{Messages: counts.Messages, Enums: counts.Enums, Fields: counts.Fields, Imports: counts.Imports},
+		Renames:         []TypeRename{},
+		Unsupported:     []string{},
+		ResolvedImports: []string{},
+		Sources:         sourceLocations(protoFile.Messages, ""),
+	}
+
+	for _, conv := range convs {
+		for original, renamed := range conv.GetCollisionRenames() {
+			report.Renames = append(report.Renames, TypeRename{Original: original, Renamed: renamed})
+		}
+	}
+	sort.Slice(report.Renames, func(i, j int) bool { return report.Renames[i].Original < report.Renames[j].Original })
+
+	unsupported := make(map[string]bool)
+	visited := make(map[*model.Schema]bool)
+	var walk func(s *model.Schema, imported bool)
+	walk = func(s *model.Schema, imported bool) {
+		if visited[s] {
+			return
+		}
+		visited[s] = true
+		if imported && s.Location != "" {
+			report.ResolvedImports = append(report.ResolvedImports, s.Location)
+		}
+		for _, feature := range s.Unsupported {
+			if !unsupported[feature] {
+				unsupported[feature] = true
+				report.Unsupported = append(report.Unsupported, feature)
+			}
+		}
+		for _, child := range s.ImportedSchemas {
+			walk(child, true)
+		}
+	}
+	for _, root := range roots {
+		walk(root, false)
+	}
+	sort.Strings(report.Unsupported)
+	sort.Strings(report.ResolvedImports)
+
+	report.DurationMs = time.Since(start).Milliseconds()
+
+	lastReportMu.Lock()
+	lastReport = report
+	lastReportMu.Unlock()
+
+	if opts.ReportPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode conversion report: %w", err)
+	}
+	if err := writeToFile(opts.ReportPath, string(data)+"\n"); err != nil {
+		return fmt.Errorf("failed to write conversion report: %w", err)
+	}
+	if opts.Verbose {
+		opts.logf("Successfully generated %s\n", opts.ReportPath)
+	}
+	return nil
+}
+
+// sourceLocations lists the known XSD locations of the messages, their fields and
+// their nested messages
+func sourceLocations(messages []model.ProtoMessage, scope string) []SourceLocation {
+	locations := []SourceLocation{}
+	for _, message := range messages {
+		name := scope + message.Name
+		if message.SourceLine > 0 {
+			locations = append(locations, SourceLocation{Message: name, File: message.SourceFile, Line: message.SourceLine})
+		}
+		for _, field := range message.Fields {
+			if field.SourceLine > 0 {
+				locations = append(locations, SourceLocation{Message: name, Field: field.Name, File: field.SourceFile, Line: field.SourceLine})
+			}
+		}
+		locations = append(locations, sourceLocations(message.Messages, name+".")...)
+	}
+	return locations
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const abstractXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/shapes"
+           targetNamespace="http://example.com/shapes">
+
+    <xs:complexType name="Shape" abstract="true">
+        <xs:sequence>
+            <xs:element name="color" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:complexType name="Circle">
+        <xs:sequence>
+            <xs:element name="radius" type="xs:double"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:element name="Figure" abstract="true">
+        <xs:complexType>
+            <xs:attribute name="id" type="xs:string" use="required"/>
+        </xs:complexType>
+    </xs:element>
+
+</xs:schema>`
+
+// TestAbstractTypeComment tests that abstract complex types and elements are marked in the output
+func TestAbstractTypeComment(t *testing.T) {
+	content := generateProto(t, abstractXSD, nil)
+
+	expected := []string{
+		"// abstract type — do not instantiate directly\nmessage Shape {\n",
+		"// abstract type — do not instantiate directly\nmessage Figure {\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", exp, content)
+		}
+	}
+	if strings.Contains(content, "directly\nmessage Circle {") {
+		t.Errorf("Circle is not abstract\nActual content:\n%s", content)
+	}
+}
+
+// TestAbstractElementWarning tests that an abstract element without substitution group members is warned about
+func TestAbstractElementWarning(t *testing.T) {
+	p := parser.New()
+	schema, err := p.ParseString(abstractXSD)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	warnings, err := p.Validate(schema)
+	if err != nil {
+		t.Fatalf("Expected validation to pass, got %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "abstract element Figure has no substitution group members") {
+		t.Errorf("Expected a warning for Figure, got %v", warnings)
+	}
+
+	withMember := strings.Replace(abstractXSD, "</xs:schema>",
+		`<xs:element name="Square" substitutionGroup="tns:Figure"/></xs:schema>`, 1)
+	schema, err = p.ParseString(withMember)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	warnings, err = p.Validate(schema)
+	if err != nil {
+		t.Fatalf("Expected validation to pass, got %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings once Figure has a member, got %v", warnings)
+	}
+}
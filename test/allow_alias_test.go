@@ -0,0 +1,74 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const allowAliasXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/status">
+
+    <xs:simpleType name="Status">
+        <xs:restriction base="xs:QName">
+            <xs:enumeration value="active"/>
+            <xs:enumeration value="ACTIVE"/>
+            <xs:enumeration value="closed"/>
+        </xs:restriction>
+    </xs:simpleType>
+
+</xs:schema>`
+
+// TestAllowAlias tests that values differing only in case share a number under allow_alias
+func TestAllowAlias(t *testing.T) {
+	conv := converter.New()
+	conv.SetAllowAlias(true)
+	content := generateProto(t, allowAliasXSD, conv)
+
+	expected := `enum Status {
+  option allow_alias = true;
+  STATUS_UNSPECIFIED = 0;
+  STATUS_ACTIVE = 1;
+  STATUS_ACTIVE1 = 1;
+  STATUS_CLOSED = 2;
+}`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+}
+
+// TestAllowAliasDisabled tests that every value keeps its own number by default
+func TestAllowAliasDisabled(t *testing.T) {
+	content := generateProto(t, allowAliasXSD, nil)
+
+	if strings.Contains(content, "allow_alias") {
+		t.Errorf("Expected no allow_alias option by default\nActual content:\n%s", content)
+	}
+	if !strings.Contains(content, "STATUS_ACTIVE1 = 2;") || !strings.Contains(content, "STATUS_CLOSED = 3;") {
+		t.Errorf("Expected distinct numbers for every value\nActual content:\n%s", content)
+	}
+}
+
+// TestAllowAliasFlag tests the --allow-alias flag end to end
+func TestAllowAliasFlag(t *testing.T) {
+	cli := buildCLI(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "status.xsd")
+	if err := os.WriteFile(xsdPath, []byte(allowAliasXSD), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	output, err := exec.Command(cli, "--allow-alias", "--stdout", xsdPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(string(output), "enum Status {\n  option allow_alias = true;\n") {
+		t.Errorf("Expected allow_alias as the first enum statement\nActual output:\n%s", output)
+	}
+}
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAnyAttributeMapField tests that xs:anyAttribute produces a map<string, string> field
+func TestAnyAttributeMapField(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/wildcard"
+           elementFormDefault="qualified">
+
+    <xs:complexType name="Extensible">
+        <xs:sequence>
+            <xs:element name="name" type="xs:string"/>
+        </xs:sequence>
+        <xs:attribute name="id" type="xs:string" use="required"/>
+        <xs:anyAttribute namespace="##other" processContents="lax"/>
+    </xs:complexType>
+
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	expectedParts := []string{
+		"message Extensible {",
+		"string name = 1;",
+		"string id = 2;",
+		"map<string, string> attributes = 3;",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(content, part) {
+			t.Errorf("Generated proto should contain: %s\nActual content:\n%s", part, content)
+		}
+	}
+
+	if strings.Contains(content, "struct.proto") {
+		t.Error("Map field should not require google/protobuf/struct.proto")
+	}
+}
@@ -0,0 +1,49 @@
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAppInfoComment tests that xs:appinfo content is emitted as a field comment
+func TestAppInfoComment(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/appinfo">
+
+    <xs:complexType name="User">
+        <xs:sequence>
+            <xs:element name="userId" type="xs:long">
+                <xs:annotation>
+                    <xs:appinfo>db_column=user_id</xs:appinfo>
+                </xs:annotation>
+            </xs:element>
+            <xs:element name="login" type="xs:string"/>
+        </xs:sequence>
+        <xs:attribute name="role" type="xs:string">
+            <xs:annotation>
+                <xs:documentation>Role of the user</xs:documentation>
+                <xs:appinfo source="validation">required_if=admin</xs:appinfo>
+            </xs:annotation>
+        </xs:attribute>
+    </xs:complexType>
+
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	expectedParts := []string{
+		"int64 user_id = 1; // appinfo: db_column=user_id\n",
+		"string login = 2;\n",
+		"optional string role = 3; // appinfo: required_if=admin\n",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(content, part) {
+			t.Errorf("Generated proto should contain: %q\nActual content:\n%s", part, content)
+		}
+	}
+
+	if strings.Contains(content, "xsd_appinfo") {
+		t.Errorf("appinfo should not be emitted as a field option\nActual content:\n%s", content)
+	}
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const attributeFixedTestXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Envelope">
+        <xs:sequence>
+            <xs:element name="body" type="xs:string"/>
+        </xs:sequence>
+        <xs:attribute name="version" type="xs:string" fixed="v1.0"/>
+        <xs:attribute name="revision" type="xs:int" fixed="3" use="required"/>
+    </xs:complexType>
+</xs:schema>`
+
+func convertAttributeFixed(t *testing.T, args ...string) string {
+	t.Helper()
+	cli := buildCLI(t)
+
+	xsdPath := filepath.Join(t.TempDir(), "envelope.xsd")
+	if err := os.WriteFile(xsdPath, []byte(attributeFixedTestXSD), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	args = append(args, "--no-header", "--stdout", xsdPath)
+	output, err := exec.Command(cli, args...).Output()
+	if err != nil {
+		t.Fatalf("CLI failed: %v", err)
+	}
+	return string(output)
+}
+
+// TestAttributeFixedComment tests that fixed attribute values are noted in a comment
+func TestAttributeFixedComment(t *testing.T) {
+	result := convertAttributeFixed(t)
+
+	for _, want := range []string{
+		"optional string version = 2; // fixed: \"v1.0\"\n",
+		"int32 revision = 3; // fixed: \"3\"\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "buf.validate") {
+		t.Errorf("Validate rules should only be emitted on request\nGot:\n%s", result)
+	}
+}
+
+// TestAttributeFixedValidateRule tests that --emit-validate-annotations adds a const rule
+func TestAttributeFixedValidateRule(t *testing.T) {
+	result := convertAttributeFixed(t, "--emit-validate-annotations")
+
+	for _, want := range []string{
+		`import "buf/validate/validate.proto";`,
+		`optional string version = 2 [(buf.validate.field).string.const = "v1.0"]; // fixed: "v1.0"`,
+		`int32 revision = 3 [(buf.validate.field).int32.const = 3];`,
+		"string body = 1;\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+}
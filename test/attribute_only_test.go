@@ -0,0 +1,58 @@
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+const attributeOnlyXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/config"
+           targetNamespace="http://example.com/config">
+
+    <xs:complexType name="Config">
+        <xs:attribute name="timeout" type="xs:int"/>
+        <xs:attribute name="host" type="xs:string" use="required"/>
+    </xs:complexType>
+
+    <xs:complexType name="Service">
+        <xs:sequence>
+            <xs:element name="name" type="xs:string"/>
+            <xs:element name="config" type="tns:Config"/>
+        </xs:sequence>
+        <xs:attribute name="enabled" type="xs:boolean"/>
+    </xs:complexType>
+
+    <xs:element name="Limits">
+        <xs:complexType>
+            <xs:attribute name="limit" type="xs:long"/>
+        </xs:complexType>
+    </xs:element>
+
+</xs:schema>`
+
+// TestAttributeOnlyComplexType tests that a complex type without element content converts its attributes
+func TestAttributeOnlyComplexType(t *testing.T) {
+	content := generateProto(t, attributeOnlyXSD, nil)
+
+	expected := []string{
+		"message Config {\n  optional int32 timeout = 1;\n  string host = 2;\n}\n",
+		"message Limits {\n  optional int64 limit = 1;\n}\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", exp, content)
+		}
+	}
+}
+
+// TestAttributeOnlyComplexTypeAsField tests that sequence fields, including one of an
+// attribute-only type, come before the attribute fields
+func TestAttributeOnlyComplexTypeAsField(t *testing.T) {
+	content := generateProto(t, attributeOnlyXSD, nil)
+
+	expected := "message Service {\n  string name = 1;\n  Config config = 2;\n  optional bool enabled = 3;\n}\n"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+}
@@ -0,0 +1,44 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const attributeOrderXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/order">
+
+    <xs:complexType name="Item">
+        <xs:sequence>
+            <xs:element name="name" type="xs:string"/>
+            <xs:element name="price" type="xs:double"/>
+        </xs:sequence>
+        <xs:attribute name="id" type="xs:string" use="required"/>
+    </xs:complexType>
+
+</xs:schema>`
+
+// TestAttributesAfterSequenceByDefault tests the default attribute placement
+func TestAttributesAfterSequenceByDefault(t *testing.T) {
+	content := generateProto(t, attributeOrderXSD, nil)
+
+	expected := "message Item {\n  string name = 1;\n  double price = 2;\n  string id = 3;\n}\n"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+}
+
+// TestAttributesFirst tests that attributes take the first field numbers when requested
+func TestAttributesFirst(t *testing.T) {
+	conv := converter.New()
+	conv.SetAttributesFirst(true)
+	content := generateProto(t, attributeOrderXSD, conv)
+
+	expected := "message Item {\n  string id = 1;\n  string name = 2;\n  double price = 3;\n}\n"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+}
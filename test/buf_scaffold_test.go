@@ -0,0 +1,69 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+// TestBufScaffold tests that buf.yaml and buf.gen.yaml are written next to the proto file
+func TestBufScaffold(t *testing.T) {
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "person.xsd")
+	if err := os.WriteFile(xsdPath, []byte(fieldMapXSD("name")), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	outputDir := filepath.Join(dir, "gen")
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = filepath.Join(outputDir, "person.proto")
+	opts.ProtoPackage = "com.example.people.v1"
+	opts.GoPackage = "github.com/example/people"
+	opts.BufScaffold = true
+	if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	bufYAML, err := os.ReadFile(filepath.Join(outputDir, "buf.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read buf.yaml: %v", err)
+	}
+	for _, exp := range []string{"version: v1\n", "name: buf.build/example/people\n", "#   person.proto\n"} {
+		if !strings.Contains(string(bufYAML), exp) {
+			t.Errorf("Expected %q in buf.yaml\nActual content:\n%s", exp, bufYAML)
+		}
+	}
+
+	// The proto file listed in buf.yaml is the one written
+	if _, err := os.Stat(filepath.Join(outputDir, "person.proto")); err != nil {
+		t.Errorf("Expected the proto file referenced by buf.yaml to exist: %v", err)
+	}
+
+	bufGenYAML, err := os.ReadFile(filepath.Join(outputDir, "buf.gen.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read buf.gen.yaml: %v", err)
+	}
+	for _, exp := range []string{
+		"version: v1\n",
+		"    default: github.com/example/people\n",
+		"  - plugin: go\n    out: gen/go\n",
+	} {
+		if !strings.Contains(string(bufGenYAML), exp) {
+			t.Errorf("Expected %q in buf.gen.yaml\nActual content:\n%s", exp, bufGenYAML)
+		}
+	}
+}
+
+// TestBufScaffoldStream tests that the scaffold is rejected when writing to a stream
+func TestBufScaffoldStream(t *testing.T) {
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.Output = &strings.Builder{}
+	opts.BufScaffold = true
+	err := xsd2proto.ConvertReader(strings.NewReader(fieldMapXSD("name")), opts)
+	if err == nil || !strings.Contains(err.Error(), "buf scaffold") {
+		t.Errorf("Expected a buf scaffold error, got %v", err)
+	}
+}
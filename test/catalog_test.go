@@ -0,0 +1,115 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// writeCatalogFixture writes a catalog with a chained catalog and the schemas it maps
+func writeCatalogFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"catalog.xml": `<?xml version="1.0"?>
+<catalog xmlns="urn:oasis:names:tc:entity:xmlns:xml:catalog">
+  <uri name="http://example.corp.com/ns/v2" uri="schemas/ns-v2.xsd"/>
+  <group>
+    <system systemId="http://example.corp.com/common.xsd" uri="schemas/common.xsd"/>
+  </group>
+  <nextCatalog catalog="vendor/catalog.xml"/>
+</catalog>`,
+		"vendor/catalog.xml": `<?xml version="1.0"?>
+<catalog xmlns="urn:oasis:names:tc:entity:xmlns:xml:catalog">
+  <rewriteURI uriStartString="http://vendor.example.com/" rewritePrefix="xsd/"/>
+</catalog>`,
+		"schemas/ns-v2.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.corp.com/ns/v2">
+  <xs:complexType name="Account">
+    <xs:sequence><xs:element name="number" type="xs:string"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`,
+		"main.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:ns="http://example.corp.com/ns/v2"
+           targetNamespace="http://example.corp.com/billing">
+  <xs:import namespace="http://example.corp.com/ns/v2"/>
+  <xs:complexType name="Invoice">
+    <xs:sequence><xs:element name="account" type="ns:Account"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// TestCatalogResolver tests that catalog entries resolve namespaces and locations to local paths
+func TestCatalogResolver(t *testing.T) {
+	dir := writeCatalogFixture(t)
+	resolver, err := parser.NewCatalogResolver(filepath.Join(dir, "catalog.xml"))
+	if err != nil {
+		t.Fatalf("Failed to load catalog: %v", err)
+	}
+
+	tests := []struct {
+		namespace, location, expected string
+	}{
+		{"http://example.corp.com/ns/v2", "", filepath.Join(dir, "schemas/ns-v2.xsd")},
+		{"", "http://example.corp.com/common.xsd", filepath.Join(dir, "schemas/common.xsd")},
+		{"http://vendor.example.com/types/v1.xsd", "", filepath.Join(dir, "vendor/xsd/types/v1.xsd")},
+		{"http://unknown.example.com", "local.xsd", ""},
+	}
+	for _, tt := range tests {
+		path, err := resolver.Resolve(tt.namespace, tt.location, dir)
+		if err != nil {
+			t.Errorf("Resolve(%q, %q) failed: %v", tt.namespace, tt.location, err)
+			continue
+		}
+		if path != tt.expected {
+			t.Errorf("Resolve(%q, %q) = %q, expected %q", tt.namespace, tt.location, path, tt.expected)
+		}
+	}
+}
+
+// TestCatalogResolvesImports tests that the parser imports schemas found through the catalog
+func TestCatalogResolvesImports(t *testing.T) {
+	dir := writeCatalogFixture(t)
+	resolver, err := parser.NewCatalogResolver(filepath.Join(dir, "catalog.xml"))
+	if err != nil {
+		t.Fatalf("Failed to load catalog: %v", err)
+	}
+
+	p := parser.New()
+	p.SetResolver(resolver)
+	schema, err := p.ParseFileWithImports(filepath.Join(dir, "main.xsd"))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	if len(schema.ImportedSchemas) != 1 {
+		t.Fatalf("Expected the catalog to resolve one import, got %d", len(schema.ImportedSchemas))
+	}
+
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	var names []string
+	for _, message := range protoFile.Messages {
+		names = append(names, message.Name)
+	}
+	if !strings.Contains(strings.Join(names, ","), "Account") {
+		t.Errorf("Expected the imported Account message, got %v", names)
+	}
+}
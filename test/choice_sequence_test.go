@@ -0,0 +1,100 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const choiceSequenceXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/payment">
+
+    <xs:complexType name="Payment">
+        <xs:sequence>
+            <xs:element name="amount" type="xs:decimal"/>
+            <xs:choice>
+                <xs:sequence>
+                    <xs:element name="cardNumber" type="xs:string"/>
+                    <xs:element name="expiry" type="xs:string"/>
+                </xs:sequence>
+                <xs:sequence>
+                    <xs:element name="iban" type="xs:string"/>
+                    <xs:element name="bic" type="xs:string"/>
+                </xs:sequence>
+            </xs:choice>
+            <xs:element name="note" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+
+</xs:schema>`
+
+// TestChoiceSequences tests that each sequence of a choice becomes a nested message in a oneof
+func TestChoiceSequences(t *testing.T) {
+	content := generateProto(t, choiceSequenceXSD, nil)
+
+	expected := `message Payment {
+  message Payment_1Choice {
+    string card_number = 1;
+    string expiry = 2;
+  }
+
+  message Payment_2Choice {
+    string iban = 1;
+    string bic = 2;
+  }
+
+  double amount = 1;
+  oneof choice {
+    Payment_1Choice sequence_1 = 2;
+    Payment_2Choice sequence_2 = 3;
+  }
+  string note = 4;
+}`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+}
+
+// TestChoiceSequencesParsed tests that the parser keeps sequences directly inside a choice
+func TestChoiceSequencesParsed(t *testing.T) {
+	schema, err := parser.New().ParseString(choiceSequenceXSD)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	choice := schema.ComplexTypes[0].Sequence.Choices[0]
+	if len(choice.Sequences) != 2 {
+		t.Fatalf("Expected 2 sequences in the choice, got %d", len(choice.Sequences))
+	}
+	if got := choice.Sequences[1].Children[0].Element.Name; got != "iban" {
+		t.Errorf("Expected the second sequence to start with iban, got %s", got)
+	}
+}
+
+// TestChoiceSequencesDescriptor tests that the nested messages form a valid descriptor
+func TestChoiceSequencesDescriptor(t *testing.T) {
+	schema, err := parser.New().ParseString(choiceSequenceXSD)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	descriptor, err := generator.GenerateDescriptor(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to build descriptor: %v", err)
+	}
+	descriptor.Name = proto.String("payment.proto")
+	if _, err := protodesc.NewFile(descriptor, protoregistry.GlobalFiles); err != nil {
+		t.Errorf("Descriptor is not well-formed: %v", err)
+	}
+}
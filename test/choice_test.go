@@ -0,0 +1,163 @@
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMultipleChoiceGroupsInSequence tests that each xs:choice in a sequence becomes its own oneof
+func TestMultipleChoiceGroupsInSequence(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/choice"
+           elementFormDefault="qualified">
+
+    <xs:complexType name="Payment">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string"/>
+            <xs:choice>
+                <xs:element name="card" type="xs:string"/>
+                <xs:element name="iban" type="xs:string"/>
+            </xs:choice>
+            <xs:element name="amount" type="xs:double"/>
+            <xs:choice>
+                <xs:element name="email" type="xs:string"/>
+                <xs:element name="phone" type="xs:string"/>
+            </xs:choice>
+            <xs:element name="note" type="xs:string" minOccurs="0"/>
+        </xs:sequence>
+    </xs:complexType>
+
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	expected := `message Payment {
+  string id = 1;
+  oneof choice {
+    string card = 2;
+    string iban = 3;
+  }
+  double amount = 4;
+  oneof choice_2 {
+    string email = 5;
+    string phone = 6;
+  }
+  optional string note = 7;
+}
+`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+}
+
+// TestRepeatedChoiceInSequence tests that a repeated choice in a sequence keeps its
+// repetition as a list of wrapper messages instead of single optional fields
+func TestRepeatedChoiceInSequence(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/choice">
+    <xs:complexType name="Basket">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string"/>
+            <xs:choice maxOccurs="unbounded">
+                <xs:element name="a" type="xs:string"/>
+                <xs:element name="b" type="xs:int"/>
+            </xs:choice>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	if !strings.Contains(content, "message Basket {\n  string id = 1;\n  repeated BasketItem items = 2;\n}") {
+		t.Errorf("Expected a repeated items field in Basket:\n%s", content)
+	}
+	if strings.Contains(content, "optional string a") || strings.Contains(content, "optional int32 b") {
+		t.Errorf("Expected the alternatives not to be single optional fields:\n%s", content)
+	}
+}
+
+// TestTopLevelChoice tests that a complex type's direct xs:choice becomes a oneof
+func TestTopLevelChoice(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/choice"
+           elementFormDefault="qualified">
+
+    <xs:complexType name="Contact">
+        <xs:choice>
+            <xs:element name="email" type="xs:string"/>
+            <xs:element name="phone" type="xs:string"/>
+            <xs:element name="aliases" type="xs:string" maxOccurs="unbounded"/>
+        </xs:choice>
+    </xs:complexType>
+
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	expected := `message Contact {
+  oneof choice {
+    string email = 1;
+    string phone = 2;
+  }
+  repeated string aliases = 3;
+}
+`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+}
+
+// TestChoiceOfElementRefs tests that ref-based choice elements become typed oneof variants
+func TestChoiceOfElementRefs(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/pets"
+           targetNamespace="http://example.com/pets"
+           elementFormDefault="qualified">
+
+    <xs:complexType name="DogType">
+        <xs:sequence>
+            <xs:element name="breed" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:element name="Cat">
+        <xs:complexType>
+            <xs:sequence>
+                <xs:element name="lives" type="xs:int"/>
+            </xs:sequence>
+        </xs:complexType>
+    </xs:element>
+    <xs:element name="Dog" type="tns:DogType"/>
+
+    <xs:complexType name="Owner">
+        <xs:sequence>
+            <xs:element name="name" type="xs:string"/>
+            <xs:choice>
+                <xs:element ref="tns:Cat"/>
+                <xs:element ref="tns:Dog"/>
+            </xs:choice>
+        </xs:sequence>
+    </xs:complexType>
+
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	expected := `message Owner {
+  string name = 1;
+  oneof choice {
+    Cat cat = 2;
+    DogType dog = 3;
+  }
+}
+`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+	if !strings.Contains(content, "message Cat {") {
+		t.Errorf("Referenced element with an inline type should become a message\nActual content:\n%s", content)
+	}
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const cloneOrderXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/order">
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="orderId" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+const cloneCustomerXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/customer">
+    <xs:complexType name="Customer">
+        <xs:sequence>
+            <xs:element name="customerName" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="total" type="xs:int"/>
+        </xs:sequence>
+    </xs:complexType>
+    <xs:element name="Invoice">
+        <xs:complexType>
+            <xs:sequence>
+                <xs:element name="number" type="xs:string"/>
+            </xs:sequence>
+        </xs:complexType>
+    </xs:element>
+</xs:schema>`
+
+// TestConverterClone tests that clones keep the configuration but not the naming state
+func TestConverterClone(t *testing.T) {
+	base := converter.New()
+	base.SetFieldNamingStyle(true, false)
+	base.SetFieldNumberBase(10)
+
+	first := generateProto(t, cloneOrderXSD, base)
+	if !strings.Contains(first, "string orderId = 10;") {
+		t.Fatalf("Expected camelCase field numbered from 10\nActual content:\n%s", first)
+	}
+
+	// The base converter has registered Order; a clone starts without it
+	content := generateProto(t, cloneOrderXSD, base.Clone())
+	if !strings.Contains(content, "message Order {") || !strings.Contains(content, "string orderId = 10;") {
+		t.Errorf("Expected the clone to keep the settings and name Order afresh\nActual content:\n%s", content)
+	}
+}
+
+// TestTypeMapperClone tests that custom mappings added to a clone stay in the clone
+func TestTypeMapperClone(t *testing.T) {
+	base := converter.NewTypeMapper()
+	base.AddCustomMapping("Money", "string")
+
+	clone := base.Clone()
+	clone.AddCustomMapping("Percent", "double")
+
+	if protoType, _ := clone.MapXSDType("Money"); protoType != "string" {
+		t.Errorf("Expected the clone to keep Money -> string, got %s", protoType)
+	}
+	if protoType, _ := base.MapXSDType("Percent"); protoType == "double" {
+		t.Error("Expected mappings added to the clone not to reach the original")
+	}
+}
+
+// TestConverterCloneConcurrent tests that clones convert in parallel with independent
+// outputs; run with -race to check for data races
+func TestConverterCloneConcurrent(t *testing.T) {
+	base := converter.New()
+	base.SetFieldNamingStyle(true, false)
+
+	inputs := []string{cloneOrderXSD, cloneCustomerXSD}
+	schemas := make([]*model.Schema, len(inputs))
+	for i, input := range inputs {
+		schema, err := parser.New().ParseString(input)
+		if err != nil {
+			t.Fatalf("Failed to parse XSD: %v", err)
+		}
+		schemas[i] = schema
+	}
+
+	const rounds = 20
+	outputs := make([][]string, len(schemas))
+	errs := make(chan error, rounds*len(schemas))
+	var wg sync.WaitGroup
+	for i := range schemas {
+		outputs[i] = make([]string, rounds)
+		for round := 0; round < rounds; round++ {
+			wg.Add(1)
+			go func(conv *converter.Converter, i, round int) {
+				defer wg.Done()
+				protoFile, err := conv.Convert(schemas[i])
+				if err != nil {
+					errs <- err
+					return
+				}
+				content, err := generator.New(generator.WithHeader(false, "")).Generate(protoFile)
+				if err != nil {
+					errs <- err
+					return
+				}
+				outputs[i][round] = content
+			}(base.Clone(), i, round)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Concurrent conversion failed: %v", err)
+	}
+
+	for i, expected := range []string{"string orderId = 1;", "string customerName = 1;"} {
+		for round, content := range outputs[i] {
+			if !strings.Contains(content, expected) || !strings.Contains(content, "message Order {") {
+				t.Errorf("Schema %d round %d: expected %q and message Order\nActual content:\n%s", i, round, expected, content)
+			}
+			if content != outputs[i][0] {
+				t.Errorf("Schema %d round %d differs from the first round", i, round)
+			}
+		}
+	}
+}
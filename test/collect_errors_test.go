@@ -0,0 +1,74 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	xsderrors "github.com/i-icc/xsd2proto/internal/errors"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestCollectErrors tests that collect mode reports every failing type at once
+func TestCollectErrors(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/errors">
+  <xs:complexType name="FirstBad">
+    <xs:sequence>
+      <xs:element name="a" type="xs:string">
+        <xs:annotation><xs:appinfo source="proto-field-number">1</xs:appinfo></xs:annotation>
+      </xs:element>
+      <xs:element name="b" type="xs:string">
+        <xs:annotation><xs:appinfo source="proto-field-number">1</xs:appinfo></xs:annotation>
+      </xs:element>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="Good">
+    <xs:sequence>
+      <xs:element name="name" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="SecondBad">
+    <xs:sequence>
+      <xs:element name="x" type="xs:int">
+        <xs:annotation><xs:appinfo source="proto-field-number">2</xs:appinfo></xs:annotation>
+      </xs:element>
+      <xs:element name="y" type="xs:int">
+        <xs:annotation><xs:appinfo source="proto-field-number">2</xs:appinfo></xs:annotation>
+      </xs:element>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+	schema, err := parser.New().ParseString(xsdContent)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	// Without collect mode the first failure stops the conversion
+	if _, err := converter.New().Convert(schema); err == nil || errors.As(err, new(*xsderrors.MultiError)) {
+		t.Fatalf("Expected a single error, got %v", err)
+	}
+
+	conv := converter.New()
+	conv.SetCollectErrors(true)
+	protoFile, err := conv.Convert(schema)
+
+	var multiErr *xsderrors.MultiError
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("Expected a MultiError, got %T: %v", err, err)
+	}
+	if len(multiErr.Unwrap()) != 2 || len(conv.ErrorList()) != 2 {
+		t.Fatalf("Expected exactly two errors, got %v", multiErr.Unwrap())
+	}
+	for i, typeName := range []string{"FirstBad", "SecondBad"} {
+		var convertErr *xsderrors.ConvertError
+		if !errors.As(multiErr.Errors[i], &convertErr) || convertErr.TypeName != typeName {
+			t.Errorf("Expected error %d to name %s, got %v", i, typeName, multiErr.Errors[i])
+		}
+	}
+
+	if protoFile == nil || len(protoFile.Messages) != 1 || protoFile.Messages[0].Name != "Good" {
+		t.Errorf("Expected a partial proto file with the Good message, got %+v", protoFile)
+	}
+}
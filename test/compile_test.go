@@ -0,0 +1,63 @@
+//go:build integration
+
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+// TestExamplesCompileWithProtoc tests that the proto generated from every example
+// XSD is accepted by protoc. Run it with: go test -tags integration ./test
+func TestExamplesCompileWithProtoc(t *testing.T) {
+	setupTest(t)
+
+	protoc, err := exec.LookPath("protoc")
+	if err != nil {
+		t.Skip("protoc is not installed")
+	}
+	if _, err := exec.LookPath("protoc-gen-go"); err != nil {
+		t.Skip("protoc-gen-go is not installed")
+	}
+
+	// Each example's entry point has its expected proto next to it; other XSDs are imports
+	expectedProtos, err := filepath.Glob("examples/*/*.proto")
+	if err != nil {
+		t.Fatalf("Failed to list examples: %v", err)
+	}
+	if len(expectedProtos) == 0 {
+		t.Fatal("No examples found")
+	}
+
+	for _, expectedProto := range expectedProtos {
+		xsdPath := strings.TrimSuffix(expectedProto, ".proto") + ".xsd"
+		name := filepath.Base(filepath.Dir(expectedProto))
+
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			protoName := filepath.Base(expectedProto)
+
+			opts := xsd2proto.DefaultConvertOptions()
+			opts.OutputPath = filepath.Join(dir, protoName)
+			opts.GoPackage = "example.com/xsd2proto/" + strings.ReplaceAll(name, "_", "")
+			if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+
+			goOut := filepath.Join(dir, "go")
+			if err := os.Mkdir(goOut, 0755); err != nil {
+				t.Fatalf("Failed to create output directory: %v", err)
+			}
+			cmd := exec.Command(protoc, "--proto_path="+dir, "--go_out="+goOut, protoName)
+			if output, err := cmd.CombinedOutput(); err != nil {
+				content, _ := os.ReadFile(opts.OutputPath)
+				t.Fatalf("protoc failed: %v\nOutput: %s\nGenerated proto:\n%s", err, output, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const brokenReferenceXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="http://example.com/orders"
+           targetNamespace="http://example.com/orders">
+  <xs:simpleType name="Code">
+    <xs:restriction base="xs:string"/>
+  </xs:simpleType>
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="code" type="tns:Code"/>
+      <xs:element name="customer" type="tns:Customer"/>
+      <xs:element name="lines">
+        <xs:complexType>
+          <xs:attribute name="unit" type="tns:Unit"/>
+        </xs:complexType>
+      </xs:element>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:element name="order" type="tns:Order"/>
+</xs:schema>`
+
+// TestValidateCompleteness tests that every unresolvable type reference is reported
+func TestValidateCompleteness(t *testing.T) {
+	p := parser.New()
+	schema, err := p.Parse(strings.NewReader(brokenReferenceXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	errs := p.ValidateCompleteness(schema)
+	expected := []xsd2proto.UnresolvedTypeError{
+		{TypeName: "tns:Customer", UsedIn: "complexType Order, element customer"},
+		{TypeName: "tns:Unit", UsedIn: "complexType Order, element lines, attribute unit"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %v", len(expected), errs)
+	}
+	for i, err := range errs {
+		var unresolved *xsd2proto.UnresolvedTypeError
+		if !errors.As(err, &unresolved) || *unresolved != expected[i] {
+			t.Errorf("Expected %+v, got %v", expected[i], err)
+		}
+	}
+
+	// The simple example declares every type it uses
+	setupTest(t)
+	schema, err = p.ParseFileWithImports("examples/001_simple/simple.xsd")
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	if errs := p.ValidateCompleteness(schema); len(errs) != 0 {
+		t.Errorf("Expected no unresolved types, got %v", errs)
+	}
+}
+
+// TestCLIStrict tests that unresolved types are warnings unless --strict is given,
+// which stops before conversion
+func TestCLIStrict(t *testing.T) {
+	cli := buildCLI(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "orders.xsd")
+	if err := os.WriteFile(xsdPath, []byte(brokenReferenceXSD), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The generator still rejects the undefined types, after the warnings
+	output, _ := exec.Command(cli, "-o", filepath.Join(dir, "orders.proto"), xsdPath).CombinedOutput()
+	if !strings.Contains(string(output), "Warning: unresolved type tns:Customer in complexType Order, element customer") {
+		t.Errorf("Expected an unresolved type warning\nActual output:\n%s", output)
+	}
+
+	output, err := exec.Command(cli, "--strict", "-o", filepath.Join(dir, "strict.proto"), xsdPath).CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected --strict to fail\nActual output:\n%s", output)
+	}
+	if !strings.Contains(string(output), "schema validation failed") || !strings.Contains(string(output), "unresolved type tns:Unit") {
+		t.Errorf("Expected the unresolved types in the error\nActual output:\n%s", output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "strict.proto")); !os.IsNotExist(err) {
+		t.Errorf("Expected no output file with --strict, got %v", err)
+	}
+}
+
+// TestUnresolvedTypeWarningOutput tests that unresolved type warnings go to
+// WarningOutput, and nowhere when it is unset
+func TestUnresolvedTypeWarningOutput(t *testing.T) {
+	// The generator still rejects the undefined types, after the warnings
+	var warnings bytes.Buffer
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.Output = &bytes.Buffer{}
+	opts.WarningOutput = &warnings
+	xsd2proto.ConvertReader(strings.NewReader(brokenReferenceXSD), opts)
+	if !strings.Contains(warnings.String(), "Warning: unresolved type tns:Customer in complexType Order, element customer") {
+		t.Errorf("Expected an unresolved type warning in WarningOutput, got:\n%s", warnings.String())
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	opts.WarningOutput = nil
+	xsd2proto.ConvertReader(strings.NewReader(brokenReferenceXSD), opts)
+	os.Stderr = stderr
+	w.Close()
+	printed, _ := io.ReadAll(r)
+	if len(printed) != 0 {
+		t.Errorf("Expected no warnings on stderr without WarningOutput, got:\n%s", printed)
+	}
+}
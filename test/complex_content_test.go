@@ -0,0 +1,164 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+const extensionChainXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/vehicles"
+           targetNamespace="http://example.com/vehicles">
+  <xs:complexType name="Car">
+    <xs:complexContent>
+      <xs:extension base="tns:MotorVehicle">
+        <xs:sequence>
+          <xs:element name="doors" type="xs:int"/>
+        </xs:sequence>
+      </xs:extension>
+    </xs:complexContent>
+  </xs:complexType>
+  <xs:complexType name="Vehicle">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string"/>
+      <xs:element name="wheels" type="xs:int"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="MotorVehicle">
+    <xs:complexContent>
+      <xs:extension base="tns:Vehicle">
+        <xs:sequence>
+          <xs:element name="engine" type="xs:string"/>
+        </xs:sequence>
+        <xs:attribute name="fuel" type="xs:string"/>
+      </xs:extension>
+    </xs:complexContent>
+  </xs:complexType>
+</xs:schema>`
+
+// TestComplexContentExtensionChain tests that inherited fields come first with the
+// numbers of the base message, through three levels of derivation
+func TestComplexContentExtensionChain(t *testing.T) {
+	content := generateProto(t, extensionChainXSD, nil)
+
+	expected := []string{
+		"message Vehicle {\n" +
+			"  string id = 1;\n" +
+			"  int32 wheels = 2;\n" +
+			"}",
+		"message MotorVehicle {\n" +
+			"  string id = 1;\n" +
+			"  int32 wheels = 2;\n" +
+			"  string engine = 3;\n" +
+			"  optional string fuel = 4;\n" +
+			"}",
+		"message Car {\n" +
+			"  string id = 1;\n" +
+			"  int32 wheels = 2;\n" +
+			"  string engine = 3;\n" +
+			"  optional string fuel = 4;\n" +
+			"  int32 doors = 5;\n" +
+			"}",
+	}
+	for _, message := range expected {
+		if !strings.Contains(content, message) {
+			t.Errorf("Expected:\n%s\nActual content:\n%s", message, content)
+		}
+	}
+}
+
+// TestComplexContentExtensionFieldOffset tests that derived fields continue after
+// the highest inherited number
+func TestComplexContentExtensionFieldOffset(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/hints">
+  <xs:complexType name="Base">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string">
+        <xs:annotation><xs:appinfo source="proto-field-number">10</xs:appinfo></xs:annotation>
+      </xs:element>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="Derived">
+    <xs:complexContent>
+      <xs:extension base="Base">
+        <xs:sequence>
+          <xs:element name="name" type="xs:string"/>
+        </xs:sequence>
+      </xs:extension>
+    </xs:complexContent>
+  </xs:complexType>
+</xs:schema>`
+	content := generateProto(t, xsd, nil)
+
+	if !strings.Contains(content, "message Derived {\n  string id = 10;\n  string name = 11;\n}") {
+		t.Errorf("Expected derived fields numbered after the base fields\nActual content:\n%s", content)
+	}
+}
+
+// TestComplexContentExtensionImportedBase tests a base type declared in an imported schema
+func TestComplexContentExtensionImportedBase(t *testing.T) {
+	dir := t.TempDir()
+	common := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/common">
+  <xs:complexType name="Entity">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+	main := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:c="http://example.com/common"
+           targetNamespace="http://example.com/orders">
+  <xs:import namespace="http://example.com/common" schemaLocation="common.xsd"/>
+  <xs:complexType name="Order">
+    <xs:complexContent>
+      <xs:extension base="c:Entity">
+        <xs:sequence>
+          <xs:element name="total" type="xs:decimal"/>
+        </xs:sequence>
+      </xs:extension>
+    </xs:complexContent>
+  </xs:complexType>
+</xs:schema>`
+	if err := os.WriteFile(filepath.Join(dir, "common.xsd"), []byte(common), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mainPath := filepath.Join(dir, "orders.xsd")
+	if err := os.WriteFile(mainPath, []byte(main), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var output strings.Builder
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.Output = &output
+	if err := xsd2proto.ConvertFile(mainPath, opts); err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+	if !strings.Contains(output.String(), "message Order {\n  string id = 1;\n  double total = 2;\n}") {
+		t.Errorf("Expected the imported base fields first\nActual content:\n%s", output.String())
+	}
+}
+
+// TestComplexContentExtensionMissingBase tests that an unknown base type is an error
+func TestComplexContentExtensionMissingBase(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:complexType name="Orphan">
+    <xs:complexContent>
+      <xs:extension base="Missing"/>
+    </xs:complexContent>
+  </xs:complexType>
+</xs:schema>`
+	var output strings.Builder
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.Output = &output
+	err := xsd2proto.ConvertReader(strings.NewReader(xsd), opts)
+	if err == nil || !strings.Contains(err.Error(), "base type Missing not found") {
+		t.Errorf("Expected a missing base type error, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+func loadSampleSchema(tb testing.TB) *model.Schema {
+	tb.Helper()
+
+	schemaPath := "examples/005_name_collision/name_collision.xsd"
+	if _, err := os.Stat(schemaPath); err != nil {
+		// Benchmarks run from the test directory since they cannot use setupTest
+		schemaPath = filepath.Join("..", schemaPath)
+	}
+
+	schema, err := parser.New().ParseFileWithImports(schemaPath)
+	if err != nil {
+		tb.Fatalf("Failed to parse XSD: %v", err)
+	}
+	return schema
+}
+
+func generateWith(tb testing.TB, conv *converter.Converter, schema *model.Schema) string {
+	tb.Helper()
+
+	protoFile, err := conv.Convert(schema)
+	if err != nil {
+		tb.Fatalf("Failed to convert schema: %v", err)
+	}
+	gen := generator.New(generator.WithHeader(false, ""))
+	content, err := gen.Generate(protoFile)
+	if err != nil {
+		tb.Fatalf("Failed to generate proto: %v", err)
+	}
+	return content
+}
+
+// TestConverterReset tests that a reset converter produces identical output
+func TestConverterReset(t *testing.T) {
+	schema := loadSampleSchema(t)
+
+	conv := converter.New()
+	conv.SetFieldNamingStyle(true, false)
+	first := generateWith(t, conv, schema)
+
+	// Without a reset, names from the first run are still taken
+	if second := generateWith(t, conv, schema); second == first {
+		t.Fatal("Converting twice without Reset should rename colliding types")
+	}
+
+	conv.Reset()
+	if third := generateWith(t, conv, schema); third != first {
+		t.Errorf("Output after Reset should match the first conversion\nfirst:\n%s\nafter reset:\n%s", first, third)
+	}
+}
+
+// BenchmarkConvertWithReset converts the same schema reusing one converter
+func BenchmarkConvertWithReset(b *testing.B) {
+	schema := loadSampleSchema(b)
+	conv := converter.New()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		conv.Reset()
+		if _, err := conv.Convert(schema); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkConvertWithNew converts the same schema with a new converter each time
+func BenchmarkConvertWithNew(b *testing.B) {
+	schema := loadSampleSchema(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		conv := converter.New()
+		if _, err := conv.Convert(schema); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const dateTimeXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/events">
+  <xs:complexType name="Event">
+    <xs:sequence>
+      <xs:element name="day" type="xs:date"/>
+      <xs:element name="start" type="xs:time"/>
+      <xs:element name="createdAt" type="xs:dateTime"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// TestDateAndTimeAsString tests that xs:date and xs:time map to commented strings
+// by default while xs:dateTime stays a Timestamp
+func TestDateAndTimeAsString(t *testing.T) {
+	content := generateProto(t, dateTimeXSD, nil)
+
+	expected := []string{
+		"  string day = 1; // XSD date\n",
+		"  string start = 2; // XSD time\n",
+		"  google.protobuf.Timestamp created_at = 3;\n",
+		`import "google/protobuf/timestamp.proto";`,
+	}
+	for _, part := range expected {
+		if !strings.Contains(content, part) {
+			t.Errorf("Expected %q in output:\n%s", part, content)
+		}
+	}
+}
+
+// TestTimestampsForDateAndTime tests the opt-in Timestamp mappings
+func TestTimestampsForDateAndTime(t *testing.T) {
+	conv := converter.New()
+	conv.SetTimestampTypes(true, true)
+	content := generateProto(t, dateTimeXSD, conv)
+
+	expected := []string{
+		"  google.protobuf.Timestamp day = 1;\n",
+		"  google.protobuf.Timestamp start = 2;\n",
+		"  google.protobuf.Timestamp created_at = 3;\n",
+	}
+	for _, part := range expected {
+		if !strings.Contains(content, part) {
+			t.Errorf("Expected %q in output:\n%s", part, content)
+		}
+	}
+}
+
+// TestTimestampsWithoutImport tests that no Timestamp import is added when only
+// xs:date and xs:time are used
+func TestTimestampsWithoutImport(t *testing.T) {
+	xsd := strings.Replace(dateTimeXSD, `      <xs:element name="createdAt" type="xs:dateTime"/>
+`, "", 1)
+	if content := generateProto(t, xsd, nil); strings.Contains(content, "timestamp.proto") {
+		t.Errorf("Expected no Timestamp import:\n%s", content)
+	}
+
+	conv := converter.New()
+	conv.SetTimestampTypes(true, false)
+	if content := generateProto(t, xsd, conv); !strings.Contains(content, `import "google/protobuf/timestamp.proto";`) {
+		t.Errorf("Expected the Timestamp import for xs:date:\n%s", content)
+	}
+}
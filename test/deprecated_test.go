@@ -0,0 +1,94 @@
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDeprecatedAnnotations tests that deprecated XSD annotations produce deprecated proto options
+func TestDeprecatedAnnotations(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/deprecated"
+           elementFormDefault="qualified">
+
+    <xs:complexType name="LegacyAccount">
+        <xs:annotation>
+            <xs:documentation>@deprecated Use Account instead</xs:documentation>
+        </xs:annotation>
+        <xs:sequence>
+            <xs:element name="login" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:complexType name="Account">
+        <xs:sequence>
+            <xs:element name="userName" type="xs:string"/>
+            <xs:element name="legacyId" type="xs:int">
+                <xs:annotation>
+                    <xs:appinfo source="deprecated">true</xs:appinfo>
+                </xs:annotation>
+            </xs:element>
+        </xs:sequence>
+        <xs:attribute name="code" type="xs:string">
+            <xs:annotation>
+                <xs:documentation>@deprecated</xs:documentation>
+            </xs:annotation>
+        </xs:attribute>
+    </xs:complexType>
+
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	expectedParts := []string{
+		"message LegacyAccount {\n  option deprecated = true;\n",
+		"string user_name = 1;\n",
+		"int32 legacy_id = 2 [deprecated = true];\n",
+		"optional string code = 3 [deprecated = true];\n",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(content, part) {
+			t.Errorf("Generated proto should contain: %q\nActual content:\n%s", part, content)
+		}
+	}
+
+	if strings.Contains(content, "message Account {\n  option deprecated") {
+		t.Error("Account message should not be deprecated")
+	}
+}
+
+// TestDeprecatedDetection tests the case-insensitive documentation and deprecated=true appinfo forms
+func TestDeprecatedDetection(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Invoice">
+        <xs:sequence>
+            <xs:element name="total" type="xs:double">
+                <xs:annotation>
+                    <xs:documentation>DEPRECATED: use amount</xs:documentation>
+                </xs:annotation>
+            </xs:element>
+            <xs:element name="amount" type="xs:double"/>
+            <xs:element name="fax" type="xs:string">
+                <xs:annotation>
+                    <xs:appinfo>deprecated=true</xs:appinfo>
+                </xs:annotation>
+            </xs:element>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	expectedParts := []string{
+		"double total = 1 [deprecated = true];\n",
+		"double amount = 2;\n",
+		"string fax = 3 [deprecated = true];\n",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(content, part) {
+			t.Errorf("Generated proto should contain: %q\nActual content:\n%s", part, content)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestGenerateDescriptor tests that the descriptor of the sample schema round-trips and is well-formed
+func TestGenerateDescriptor(t *testing.T) {
+	setupTest(t)
+	schema, err := parser.New().ParseFile("examples/001_simple/simple.xsd")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	descriptor, err := generator.GenerateDescriptor(protoFile)
+	if err != nil {
+		t.Fatalf("GenerateDescriptor failed: %v", err)
+	}
+	descriptor.Name = proto.String("simple.proto")
+
+	data, err := proto.Marshal(descriptor)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(decoded.GetMessageType()) != len(protoFile.Messages) {
+		t.Fatalf("Expected %d messages, got %d", len(protoFile.Messages), len(decoded.GetMessageType()))
+	}
+	for i, message := range decoded.GetMessageType() {
+		if message.GetName() != protoFile.Messages[i].Name {
+			t.Errorf("Expected message %s, got %s", protoFile.Messages[i].Name, message.GetName())
+		}
+	}
+
+	// protodesc applies the same checks as protoc to the descriptor
+	if _, err := protodesc.NewFile(&decoded, protoregistry.GlobalFiles); err != nil {
+		t.Errorf("Descriptor is not well-formed: %v", err)
+	}
+}
+
+// TestOutputDescriptor tests that DescriptorPath writes a descriptor named after the proto file
+func TestOutputDescriptor(t *testing.T) {
+	setupTest(t)
+	dir := t.TempDir()
+	descriptorPath := filepath.Join(dir, "simple.pb")
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = filepath.Join(dir, "simple.proto")
+	opts.DescriptorPath = descriptorPath
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	data, err := os.ReadFile(descriptorPath)
+	if err != nil {
+		t.Fatalf("Failed to read descriptor: %v", err)
+	}
+	var descriptor descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(data, &descriptor); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if descriptor.GetName() != "simple.proto" {
+		t.Errorf("Expected descriptor name simple.proto, got %s", descriptor.GetName())
+	}
+	if len(descriptor.GetMessageType()) == 0 {
+		t.Errorf("Expected messages in the descriptor")
+	}
+}
+
+// TestOutputDescriptorProto3Optional tests that the descriptor follows Proto3Optional
+// like the proto text: message fields lose the optional label, scalars keep it
+func TestOutputDescriptorProto3Optional(t *testing.T) {
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "holder.xsd")
+	protoPath := filepath.Join(dir, "holder.proto")
+	descriptorPath := filepath.Join(dir, "holder.pb")
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/holder"
+           targetNamespace="http://example.com/holder">
+  <xs:complexType name="Base">
+    <xs:sequence><xs:element name="id" type="xs:string"/></xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="Holder">
+    <xs:sequence>
+      <xs:element name="child" type="tns:Base" minOccurs="0"/>
+      <xs:element name="note" type="xs:string" minOccurs="0"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+	if err := os.WriteFile(xsdPath, []byte(xsd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = protoPath
+	opts.DescriptorPath = descriptorPath
+	opts.Proto3Optional = true
+	if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	content, err := os.ReadFile(protoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"  Base child = 1;\n", "  optional string note = 2;\n"} {
+		if !strings.Contains(string(content), line) {
+			t.Errorf("Expected %q in proto:\n%s", line, content)
+		}
+	}
+
+	data, err := os.ReadFile(descriptorPath)
+	if err != nil {
+		t.Fatalf("Failed to read descriptor: %v", err)
+	}
+	var descriptor descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(data, &descriptor); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	found := false
+	for _, message := range descriptor.GetMessageType() {
+		if message.GetName() != "Holder" {
+			continue
+		}
+		found = true
+		child, note := message.GetField()[0], message.GetField()[1]
+		if child.GetProto3Optional() || child.OneofIndex != nil {
+			t.Errorf("Expected child without proto3_optional, got %v", child)
+		}
+		if !note.GetProto3Optional() {
+			t.Errorf("Expected note with proto3_optional, got %v", note)
+		}
+		if len(message.GetOneofDecl()) != 1 {
+			t.Errorf("Expected only the synthetic oneof of note, got %v", message.GetOneofDecl())
+		}
+	}
+	if !found {
+		t.Fatalf("Expected a Holder message in the descriptor")
+	}
+	if _, err := protodesc.NewFile(&descriptor, protoregistry.GlobalFiles); err != nil {
+		t.Errorf("Descriptor is not well-formed: %v", err)
+	}
+}
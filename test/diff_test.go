@@ -0,0 +1,135 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/diff"
+)
+
+const diffXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/orders">
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string"/>
+      <xs:element name="total" type="xs:double"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// runDiff runs the CLI with --diff and returns its stdout and exit code
+func runDiff(t *testing.T, cli string, args ...string) (string, int) {
+	t.Helper()
+	output, err := exec.Command(cli, append([]string{"--diff"}, args...)...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(output), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatalf("Failed to run the CLI: %v", err)
+	}
+	return string(output), 0
+}
+
+// TestDiffMode tests that --diff prints the lines a changed schema adds to the
+// existing proto without writing it, and exits with 1 for changes and 0 without
+func TestDiffMode(t *testing.T) {
+	cli := buildCLI(t)
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "orders.xsd")
+	protoPath := filepath.Join(dir, "orders.proto")
+	if err := os.WriteFile(xsdPath, []byte(diffXSD), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+	if output, err := exec.Command(cli, "-o", protoPath, xsdPath).CombinedOutput(); err != nil {
+		t.Fatalf("Conversion failed: %v\n%s", err, output)
+	}
+	original, err := os.ReadFile(protoPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+
+	if output, code := runDiff(t, cli, "-o", protoPath, xsdPath); code != 0 || output != "" {
+		t.Errorf("Expected exit code 0 and no diff for an unchanged schema, got %d:\n%s", code, output)
+	}
+
+	changed := strings.Replace(diffXSD, `<xs:element name="total" type="xs:double"/>`,
+		`<xs:element name="total" type="xs:double"/>
+      <xs:element name="note" type="xs:string"/>`, 1)
+	if err := os.WriteFile(xsdPath, []byte(changed), 0644); err != nil {
+		t.Fatalf("Failed to update test XSD: %v", err)
+	}
+	output, code := runDiff(t, cli, "-o", protoPath, xsdPath)
+	if code != 1 {
+		t.Errorf("Expected exit code 1 for a changed schema, got %d", code)
+	}
+	if !strings.Contains(output, "\n+  string note = 3;\n") {
+		t.Errorf("Expected an added line for the new field:\n%s", output)
+	}
+	if !strings.HasPrefix(output, "--- "+protoPath+"\n+++ "+protoPath+"\n@@ ") {
+		t.Errorf("Expected the diff to name the output file:\n%s", output)
+	}
+
+	current, err := os.ReadFile(protoPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if string(current) != string(original) {
+		t.Errorf("Expected --diff to leave the proto file unchanged")
+	}
+
+	if _, code := runDiff(t, cli, "-o", protoPath, filepath.Join(dir, "missing.xsd")); code != 2 {
+		t.Errorf("Expected exit code 2 for an error, got %d", code)
+	}
+}
+
+// TestDiffModeOption tests that library callers get ErrProtoChanged for changes
+// and that streams are rejected
+func TestDiffModeOption(t *testing.T) {
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "orders.xsd")
+	if err := os.WriteFile(xsdPath, []byte(diffXSD), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = filepath.Join(dir, "orders.proto")
+	opts.Diff = true
+	if err := xsd2proto.ConvertFile(xsdPath, opts); !errors.Is(err, xsd2proto.ErrProtoChanged) {
+		t.Errorf("Expected ErrProtoChanged against a missing output file, got %v", err)
+	}
+	if _, err := os.Stat(opts.OutputPath); !os.IsNotExist(err) {
+		t.Errorf("Expected no output file to be written, got %v", err)
+	}
+
+	opts.Output = &strings.Builder{}
+	if err := xsd2proto.ConvertFile(xsdPath, opts); err == nil || !strings.Contains(err.Error(), "stream") {
+		t.Errorf("Expected a stream error, got %v", err)
+	}
+}
+
+// TestDiffProtoFiles tests the hunks of DiffProtoFiles
+func TestDiffProtoFiles(t *testing.T) {
+	oldContent := "message A {\n  string a = 1;\n}\n"
+	newContent := "message A {\n  string a = 1;\n  string b = 2;\n}\n"
+
+	if hunks, changed := diff.DiffProtoFiles(oldContent, oldContent); changed || hunks != "" {
+		t.Errorf("Expected no changes for equal contents, got %q", hunks)
+	}
+	hunks, changed := diff.DiffProtoFiles(oldContent, newContent)
+	expected := "@@ -1,3 +1,4 @@\n message A {\n   string a = 1;\n+  string b = 2;\n }\n"
+	if !changed || hunks != expected {
+		t.Errorf("Expected hunks:\n%s\ngot:\n%s", expected, hunks)
+	}
+
+	hunks, _ = diff.DiffProtoFiles("", "syntax = \"proto3\";\n")
+	if hunks != "@@ -0,0 +1 @@\n+syntax = \"proto3\";\n" {
+		t.Errorf("Expected a single added line against empty content, got %q", hunks)
+	}
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestGenerateDOT tests the dependency graph of generated messages
+func TestGenerateDOT(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/graph"
+           targetNamespace="http://example.com/graph">
+
+    <xs:simpleType name="Priority">
+        <xs:restriction base="xs:int">
+            <xs:enumeration value="1"/>
+            <xs:enumeration value="2"/>
+        </xs:restriction>
+    </xs:simpleType>
+
+    <xs:complexType name="Item">
+        <xs:sequence>
+            <xs:element name="name" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="priority" type="tns:Priority"/>
+            <xs:element name="items" type="tns:Item" maxOccurs="unbounded"/>
+        </xs:sequence>
+    </xs:complexType>
+
+</xs:schema>`
+
+	schema, err := parser.New().Parse(strings.NewReader(xsdContent))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+
+	dot := generator.GenerateDOT(protoFile)
+
+	expectedParts := []string{
+		"digraph proto {",
+		`"Priority" [label="Priority", shape=ellipse, fillcolor="lightyellow"];`,
+		`"Item" [label="Item", fillcolor="lightblue"];`,
+		`"Order" [label="Order", fillcolor="lightblue"];`,
+		`"Order" -> "Priority" [label="priority"];`,
+		`"Order" -> "Item" [label="items", style=dashed, arrowhead=crow];`,
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(dot, part) {
+			t.Errorf("DOT output should contain: %s\nActual content:\n%s", part, dot)
+		}
+	}
+
+	if strings.Contains(dot, `"string"`) {
+		t.Errorf("Scalar field types should not become nodes\nActual content:\n%s", dot)
+	}
+}
+
+// TestE2EEmitDot tests that --emit-dot writes the graph alongside the proto file
+func TestE2EEmitDot(t *testing.T) {
+	cli := buildCLI(t)
+
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "simple.proto")
+	dotFile := filepath.Join(dir, "simple.dot")
+
+	cmd := exec.Command(cli, "-o", outputFile, "--emit-dot", dotFile, "examples/001_simple/simple.xsd")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI conversion failed: %v\nOutput: %s", err, output)
+	}
+
+	content, err := os.ReadFile(dotFile)
+	if err != nil {
+		t.Fatalf("Failed to read DOT file: %v", err)
+	}
+	if !strings.Contains(string(content), `"Person" -> "Address" [label="address"];`) {
+		t.Errorf("DOT file should contain the Person -> Address edge\nActual content:\n%s", content)
+	}
+}
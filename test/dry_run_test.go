@@ -0,0 +1,57 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDryRun tests that --dry-run prints statistics without writing the proto file
+func TestDryRun(t *testing.T) {
+	binary := buildCLI(t)
+
+	content, err := os.ReadFile("examples/001_simple/simple.xsd")
+	if err != nil {
+		t.Fatalf("Failed to read sample XSD: %v", err)
+	}
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "simple.xsd")
+	if err := os.WriteFile(xsdPath, content, 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	output, err := exec.Command(binary, "--dry-run", "--emit-dot", filepath.Join(dir, "simple.dot"), xsdPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI failed: %v\nOutput: %s", err, output)
+	}
+
+	expected := "Dry run: 2 messages, 0 enums, 12 fields, 0 imports would be generated"
+	if !strings.Contains(string(output), expected) {
+		t.Errorf("Expected stats line %q, got:\n%s", expected, output)
+	}
+	if strings.Contains(string(output), "Successfully converted") {
+		t.Errorf("Dry run should not report a conversion, got:\n%s", output)
+	}
+
+	for _, name := range []string{"simple.proto", "simple.dot"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("Dry run should not write %s", name)
+		}
+	}
+}
+
+// TestDryRunReportsErrors tests that a dry run still fails on invalid input
+func TestDryRunReportsErrors(t *testing.T) {
+	binary := buildCLI(t)
+
+	xsdPath := filepath.Join(t.TempDir(), "broken.xsd")
+	if err := os.WriteFile(xsdPath, []byte("<xs:schema"), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	if err := exec.Command(binary, "--dry-run", xsdPath).Run(); err == nil {
+		t.Errorf("Expected a non-zero exit for an invalid XSD")
+	}
+}
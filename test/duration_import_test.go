@@ -60,8 +60,7 @@ func TestDurationImport(t *testing.T) {
 	}
 
 	// Generate proto content
-	gen := generator.New()
-	gen.SetHeaderOptions(false, "")
+	gen := generator.New(generator.WithHeader(false, ""))
 	content, err := gen.Generate(protoFile)
 	if err != nil {
 		t.Fatalf("Failed to generate proto: %v", err)
@@ -136,8 +135,7 @@ func TestOnlyTimestampImport(t *testing.T) {
 	}
 
 	// Generate proto content
-	gen := generator.New()
-	gen.SetHeaderOptions(false, "")
+	gen := generator.New(generator.WithHeader(false, ""))
 	content, err := gen.Generate(protoFile)
 	if err != nil {
 		t.Fatalf("Failed to generate proto: %v", err)
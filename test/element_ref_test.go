@@ -0,0 +1,60 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// TestElementHelpers tests the element classification helpers
+func TestElementHelpers(t *testing.T) {
+	ref := model.Element{Ref: "tns:Customer"}
+	typed := model.Element{Name: "id", Type: "xs:string"}
+	inline := model.Element{Name: "order", ComplexType: &model.ComplexType{}}
+	inlineSimple := model.Element{Name: "code", SimpleType: &model.SimpleType{}}
+
+	if !ref.IsRef() || typed.IsRef() || inline.IsRef() {
+		t.Error("IsRef should only be true for ref elements")
+	}
+	if ref.IsAnonymous() || typed.IsAnonymous() || !inline.IsAnonymous() || !inlineSimple.IsAnonymous() {
+		t.Error("IsAnonymous should only be true for elements with an inline type")
+	}
+	if !inline.HasInlineType() || inlineSimple.HasInlineType() || typed.HasInlineType() {
+		t.Error("HasInlineType should only be true for elements with an inline complex type")
+	}
+}
+
+// TestElementRefInSequence tests that ref elements resolve to the referenced declaration
+func TestElementRefInSequence(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/ref"
+           targetNamespace="http://example.com/ref">
+
+    <xs:element name="comment" type="xs:string"/>
+
+    <xs:element name="customer">
+        <xs:complexType>
+            <xs:sequence>
+                <xs:element name="name" type="xs:string"/>
+            </xs:sequence>
+        </xs:complexType>
+    </xs:element>
+
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element ref="tns:customer"/>
+            <xs:element ref="tns:comment" minOccurs="0" maxOccurs="unbounded"/>
+        </xs:sequence>
+    </xs:complexType>
+
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	expected := "message Order {\n  Customer customer = 1;\n  repeated string comment = 2;\n}\n"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+}
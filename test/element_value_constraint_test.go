@@ -0,0 +1,43 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+// TestElementDefaultAndFixedComments tests that default and fixed values are kept as comments
+func TestElementDefaultAndFixedComments(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:simpleType name="Color">
+        <xs:restriction base="xs:string">
+            <xs:enumeration value="RED"/>
+            <xs:enumeration value="BLUE"/>
+        </xs:restriction>
+    </xs:simpleType>
+    <xs:complexType name="Account">
+        <xs:sequence>
+            <xs:element name="status" type="xs:string" default="ACTIVE"/>
+            <xs:element name="version" type="xs:int" fixed="2"/>
+            <xs:element name="color" type="Color" default="RED"/>
+            <xs:element name="name" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	result := generateProto(t, xsd, converter.New())
+
+	expected := []string{
+		"string status = 1; // default: ACTIVE",
+		"int32 version = 2; // fixed: 2",
+		"string color = 3; // Valid values: \"RED\", \"BLUE\"; default: RED",
+		"string name = 4;\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+//go:build integration
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// ublBaseURL hosts the UBL 2.1 common library; the files below are the basic
+// components and the data type modules they import
+const ublBaseURL = "https://docs.oasis-open.org/ubl/os-UBL-2.1/xsd/common/"
+
+var ublFiles = []string{
+	"UBL-CommonBasicComponents-2.1.xsd",
+	"UBL-QualifiedDataTypes-2.1.xsd",
+	"UBL-UnqualifiedDataTypes-2.1.xsd",
+	"CCTS_CCT_SchemaModule-2.1.xsd",
+}
+
+// TestEnterpriseSchema tests the full pipeline against the UBL common basic components,
+// which span several files linked by imports. Run it with: go test -tags integration ./test
+func TestEnterpriseSchema(t *testing.T) {
+	dir := t.TempDir()
+	client := &http.Client{Timeout: 30 * time.Second}
+	for _, name := range ublFiles {
+		if err := download(client, ublBaseURL+name, filepath.Join(dir, name)); err != nil {
+			t.Skipf("UBL schemas are not available: %v", err)
+		}
+	}
+
+	schema, err := parser.New().ParseFileWithImports(filepath.Join(dir, ublFiles[0]))
+	if err != nil {
+		t.Fatalf("Failed to parse UBL schema: %v", err)
+	}
+
+	var trace bytes.Buffer
+	conv := converter.New()
+	conv.SetTraceWriter(&trace)
+	protoFile, err := conv.Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert UBL schema: %v", err)
+	}
+
+	content, err := generator.New(generator.WithHeader(false, "")).Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+
+	fieldCount := 0
+	for _, message := range protoFile.Messages {
+		fieldCount += len(message.Fields)
+	}
+	if fieldCount <= 50 {
+		t.Errorf("Expected more than 50 fields, got %d", fieldCount)
+	}
+
+	// References to complex types must keep the type name instead of falling back to string
+	complexTypes := make(map[string]bool)
+	collectComplexTypeNames(schema, complexTypes, make(map[*model.Schema]bool))
+	decoder := json.NewDecoder(&trace)
+	for {
+		var event converter.TraceEvent
+		if err := decoder.Decode(&event); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Failed to read trace: %v", err)
+		}
+		if event.Action != converter.TraceActionMapType || event.ProtoType != "string" {
+			continue
+		}
+		if name := cleanName(event.XSDType); complexTypes[name] {
+			t.Errorf("Complex type %s was mapped to string", event.XSDType)
+		}
+	}
+
+	if err := checkProtoReferences(protoFile); err != nil {
+		t.Errorf("Generated proto is invalid: %v", err)
+	}
+
+	if protoc, err := exec.LookPath("protoc"); err == nil {
+		protoPath := filepath.Join(dir, "ubl.proto")
+		if err := os.WriteFile(protoPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write proto: %v", err)
+		}
+		cmd := exec.Command(protoc, "--proto_path="+dir, "--descriptor_set_out="+filepath.Join(dir, "ubl.pb"), "ubl.proto")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("protoc rejected the generated proto: %v\nOutput: %s", err, output)
+		}
+	}
+}
+
+func download(client *http.Client, url, path string) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func collectComplexTypeNames(schema *model.Schema, names map[string]bool, visited map[*model.Schema]bool) {
+	if schema == nil || visited[schema] {
+		return
+	}
+	visited[schema] = true
+	for _, complexType := range schema.ComplexTypes {
+		names[complexType.Name] = true
+	}
+	for _, imported := range schema.ImportedSchemas {
+		collectComplexTypeNames(imported, names, visited)
+	}
+}
+
+func cleanName(typeName string) string {
+	if i := strings.LastIndex(typeName, ":"); i != -1 {
+		return typeName[i+1:]
+	}
+	return typeName
+}
+
+// checkProtoReferences verifies that every field type is a scalar, a well-known
+// type or a message or enum defined in the file, and that names are unique
+func checkProtoReferences(protoFile *model.ProtoFile) error {
+	scalars := map[string]bool{
+		"double": true, "float": true, "int32": true, "int64": true, "uint32": true,
+		"uint64": true, "sint32": true, "sint64": true, "fixed32": true, "fixed64": true,
+		"sfixed32": true, "sfixed64": true, "bool": true, "string": true, "bytes": true,
+	}
+	defined := make(map[string]bool)
+	for _, enum := range protoFile.Enums {
+		if defined[enum.Name] {
+			return fmt.Errorf("%s is defined twice", enum.Name)
+		}
+		defined[enum.Name] = true
+	}
+	for _, message := range protoFile.Messages {
+		if defined[message.Name] {
+			return fmt.Errorf("%s is defined twice", message.Name)
+		}
+		defined[message.Name] = true
+	}
+	for _, message := range protoFile.Messages {
+		numbers := make(map[int]bool)
+		for _, field := range message.Fields {
+			if numbers[field.Number] {
+				return fmt.Errorf("field number %d is used twice in %s", field.Number, message.Name)
+			}
+			numbers[field.Number] = true
+			if !scalars[field.Type] && !defined[field.Type] && !strings.HasPrefix(field.Type, "google.protobuf.") {
+				return fmt.Errorf("field %s.%s has undefined type %s", message.Name, field.Name, field.Type)
+			}
+		}
+	}
+	return nil
+}
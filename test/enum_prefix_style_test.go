@@ -0,0 +1,90 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const enumPrefixXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/prefix"
+           elementFormDefault="qualified">
+
+    <xs:simpleType name="FixtureType">
+        <xs:restriction base="xs:QName">
+            <xs:enumeration value="MATCH"/>
+            <xs:enumeration value="OUTRIGHT"/>
+        </xs:restriction>
+    </xs:simpleType>
+
+    <xs:simpleType name="PriorityLevel">
+        <xs:restriction base="xs:int">
+            <xs:enumeration value="1"/>
+            <xs:enumeration value="2"/>
+        </xs:restriction>
+    </xs:simpleType>
+
+</xs:schema>`
+
+// TestEnumValuePrefixStyles tests each enum value prefix strategy
+func TestEnumValuePrefixStyles(t *testing.T) {
+	tests := []struct {
+		style    converter.EnumValuePrefixStyle
+		expected []string
+	}{
+		{
+			style: converter.EnumValuePrefixEnumName,
+			expected: []string{
+				"FIXTURE_TYPE_UNSPECIFIED = 0;",
+				"FIXTURE_TYPE_MATCH = 1;",
+				"FIXTURE_TYPE_OUTRIGHT = 2;",
+				"PRIORITY_LEVEL_1 = 1;",
+			},
+		},
+		{
+			style: converter.EnumValuePrefixTypeName,
+			expected: []string{
+				"FIXTURE_UNSPECIFIED = 0;",
+				"FIXTURE_MATCH = 1;",
+				"FIXTURE_OUTRIGHT = 2;",
+				"PRIORITY_1 = 1;",
+			},
+		},
+		{
+			style: converter.EnumValuePrefixNone,
+			expected: []string{
+				"  UNSPECIFIED = 0;",
+				"  MATCH = 1;",
+				"  OUTRIGHT = 2;",
+				// Values starting with a digit keep the enum name prefix
+				"PRIORITY_LEVEL_1 = 1;",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			conv := converter.New()
+			if err := conv.SetEnumValuePrefixStyle(tt.style); err != nil {
+				t.Fatalf("SetEnumValuePrefixStyle failed: %v", err)
+			}
+
+			content := generateProto(t, enumPrefixXSD, conv)
+			for _, part := range tt.expected {
+				if !strings.Contains(content, part) {
+					t.Errorf("Generated proto should contain: %s\nActual content:\n%s", part, content)
+				}
+			}
+		})
+	}
+}
+
+// TestInvalidEnumValuePrefixStyle tests that unknown prefix styles are rejected
+func TestInvalidEnumValuePrefixStyle(t *testing.T) {
+	conv := converter.New()
+	if err := conv.SetEnumValuePrefixStyle("short"); err == nil {
+		t.Error("SetEnumValuePrefixStyle should reject unknown styles")
+	}
+}
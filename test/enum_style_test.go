@@ -62,8 +62,7 @@ func TestEnumValuePrefixStyle(t *testing.T) {
 	}
 
 	// Generate proto content
-	gen := generator.New()
-	gen.SetHeaderOptions(false, "")
+	gen := generator.New(generator.WithHeader(false, ""))
 	content, err := gen.Generate(protoFile)
 	if err != nil {
 		t.Fatalf("Failed to generate proto: %v", err)
@@ -148,8 +147,7 @@ func TestCamelCaseEnumPrefix(t *testing.T) {
 	}
 
 	// Generate proto content
-	gen := generator.New()
-	gen.SetHeaderOptions(false, "")
+	gen := generator.New(generator.WithHeader(false, ""))
 	content, err := gen.Generate(protoFile)
 	if err != nil {
 		t.Fatalf("Failed to generate proto: %v", err)
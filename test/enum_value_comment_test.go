@@ -0,0 +1,53 @@
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+const enumValueCommentXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/users"
+           targetNamespace="http://example.com/users">
+  <xs:complexType name="User">
+    <xs:sequence>
+      <xs:element name="status" type="tns:Status"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:simpleType name="Status">
+    <xs:restriction base="xs:QName">
+      <xs:enumeration value="ACTIVE">
+        <xs:annotation>
+          <xs:documentation>User is active</xs:documentation>
+        </xs:annotation>
+      </xs:enumeration>
+      <xs:enumeration value="SUSPENDED">
+        <xs:annotation>
+          <xs:documentation>
+            User is suspended
+            until reviewed
+          </xs:documentation>
+        </xs:annotation>
+      </xs:enumeration>
+      <xs:enumeration value="CLOSED"/>
+    </xs:restriction>
+  </xs:simpleType>
+</xs:schema>`
+
+// TestEnumValueComments tests that the documentation of enumeration values is
+// emitted as a comment before each value, and undocumented values get none
+func TestEnumValueComments(t *testing.T) {
+	content := generateProto(t, enumValueCommentXSD, nil)
+
+	expected := "enum Status {\n" +
+		"  STATUS_UNSPECIFIED = 0;\n" +
+		"  // User is active\n" +
+		"  STATUS_ACTIVE = 1;\n" +
+		"  // User is suspended until reviewed\n" +
+		"  STATUS_SUSPENDED = 2;\n" +
+		"  STATUS_CLOSED = 3;\n" +
+		"}"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Expected:\n%s\nin output:\n%s", expected, content)
+	}
+}
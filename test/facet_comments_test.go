@@ -0,0 +1,86 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+const facetCommentsXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="http://example.com/parcels"
+           targetNamespace="http://example.com/parcels">
+  <xs:simpleType name="TrackingCode">
+    <xs:restriction base="xs:string">
+      <xs:minLength value="2"/>
+      <xs:maxLength value="8"/>
+      <xs:pattern value="[A-Z]{2}[0-9]{5}"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:simpleType name="Note">
+    <xs:restriction base="xs:string">
+      <xs:maxLength value="200"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:complexType name="Parcel">
+    <xs:sequence>
+      <xs:element name="tracking" type="tns:TrackingCode"/>
+      <xs:element name="note" type="tns:Note" minOccurs="0"/>
+      <xs:element name="weight" type="xs:int"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// TestFacetComments tests that pattern and length facets are documented above the field
+func TestFacetComments(t *testing.T) {
+	content := generateProto(t, facetCommentsXSD, nil)
+
+	expected := []string{
+		"\n  // pattern: [A-Z]{2}[0-9]{5}\n  // length: min=2 max=8\n  string tracking = 1;\n",
+		"\n  // length: max=200\n  optional string note = 2;\n",
+		"\n  int32 weight = 3;\n",
+	}
+	for _, block := range expected {
+		if !strings.Contains(content, block) {
+			t.Errorf("Expected %q in output:\n%s", block, content)
+		}
+	}
+	if strings.Count(content, "// ") != 3 {
+		t.Errorf("Expected only the facet comments:\n%s", content)
+	}
+}
+
+// TestFacetCommentsWithFieldComment tests that a field comment stays after the facet block
+func TestFacetCommentsWithFieldComment(t *testing.T) {
+	protoFile := &model.ProtoFile{
+		Syntax: "proto3",
+		Messages: []model.ProtoMessage{{
+			Name: "Parcel",
+			Fields: []model.ProtoField{{
+				Name:        "note",
+				Type:        "string",
+				Number:      1,
+				Label:       model.FieldLabelRequired,
+				Comment:     "Free text",
+				Constraints: []model.FieldConstraint{{Facet: "minLength", Value: "1"}, {Facet: "fixed", Value: "x"}},
+			}},
+		}},
+	}
+
+	for _, tc := range []struct {
+		style    generator.CommentStyle
+		expected string
+	}{
+		{generator.CommentStyleTrailing, "\n  // length: min=1\n  string note = 1; // Free text\n"},
+		{generator.CommentStyleLeading, "\n  // length: min=1\n  // Free text\n  string note = 1;\n"},
+	} {
+		content, err := generator.New(generator.WithCommentStyle(tc.style)).Generate(protoFile)
+		if err != nil {
+			t.Fatalf("Failed to generate proto: %v", err)
+		}
+		if !strings.Contains(content, tc.expected) {
+			t.Errorf("Expected %q in output:\n%s", tc.expected, content)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const fieldDedupeXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/dedupe">
+  <xs:complexType name="Shipment">
+    <xs:sequence>
+      <xs:element name="status" type="xs:string"/>
+      <xs:element name="Status" type="xs:int"/>
+      <xs:element name="status_attr" type="xs:string"/>
+    </xs:sequence>
+    <xs:attribute name="status" type="xs:string"/>
+    <xs:attribute name="id" type="xs:string"/>
+  </xs:complexType>
+</xs:schema>`
+
+// TestFieldNameDeduplication tests that an attribute and an element with the same
+// name become distinct fields
+func TestFieldNameDeduplication(t *testing.T) {
+	content := generateProto(t, fieldDedupeXSD, nil)
+
+	expected := []string{
+		"string status = 1;",
+		"int32 status_elem = 2;",
+		"string status_attr = 3;",
+		"optional string status_2 = 4;",
+		"optional string id = 5;",
+	}
+	for _, line := range expected {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q in output:\n%s", line, content)
+		}
+	}
+
+	schema, err := parser.New().Parse(strings.NewReader(fieldDedupeXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	descriptor, err := generator.GenerateDescriptor(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to build descriptor: %v", err)
+	}
+	descriptor.Name = proto.String("dedupe.proto")
+	if _, err := protodesc.NewFile(descriptor, protoregistry.GlobalFiles); err != nil {
+		t.Errorf("Descriptor is not well-formed: %v", err)
+	}
+}
+
+// TestFieldNameDeduplicationAttributesFirst tests that the field converted second is renamed
+func TestFieldNameDeduplicationAttributesFirst(t *testing.T) {
+	conv := converter.New()
+	conv.SetAttributesFirst(true)
+	content := generateProto(t, fieldDedupeXSD, conv)
+
+	for _, line := range []string{"optional string status = 1;", "string status_elem = 3;"} {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q in output:\n%s", line, content)
+		}
+	}
+}
+
+// TestFieldNameDeduplicationCamelCase tests that renamed fields follow the naming style
+func TestFieldNameDeduplicationCamelCase(t *testing.T) {
+	conv := converter.New()
+	conv.SetFieldNamingStyle(true, false)
+	content := generateProto(t, fieldDedupeXSD, conv)
+
+	for _, line := range []string{"int32 statusElem = 2;", "optional string status2 = 4;"} {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q in output:\n%s", line, content)
+		}
+	}
+}
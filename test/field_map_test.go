@@ -0,0 +1,100 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+func fieldMapXSD(elements ...string) string {
+	var sequence strings.Builder
+	for _, name := range elements {
+		sequence.WriteString(`      <xs:element name="` + name + `" type="xs:string"/>` + "\n")
+	}
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/people">
+  <xs:complexType name="Person">
+    <xs:sequence>
+` + sequence.String() + `    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+}
+
+// TestFieldMapPersistence tests that field numbers survive removing and re-adding fields
+func TestFieldMapPersistence(t *testing.T) {
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "person.xsd")
+	protoPath := filepath.Join(dir, "person.proto")
+	fieldMapPath := filepath.Join(dir, "fields.json")
+
+	convert := func(elements ...string) string {
+		t.Helper()
+		if err := os.WriteFile(xsdPath, []byte(fieldMapXSD(elements...)), 0644); err != nil {
+			t.Fatalf("Failed to write XSD: %v", err)
+		}
+		opts := xsd2proto.DefaultConvertOptions()
+		opts.OutputPath = protoPath
+		opts.FieldMapPath = fieldMapPath
+		if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+			t.Fatalf("Conversion failed: %v", err)
+		}
+		content, err := os.ReadFile(protoPath)
+		if err != nil {
+			t.Fatalf("Failed to read proto: %v", err)
+		}
+		return string(content)
+	}
+
+	content := convert("name", "phone", "email")
+	if !strings.Contains(content, "string email = 3;") {
+		t.Fatalf("Expected email to be field 3\nActual content:\n%s", content)
+	}
+
+	// Removing phone keeps email at 3 and does not hand 2 to a new field
+	content = convert("name", "email", "nickname")
+	for _, expected := range []string{"string name = 1;", "string email = 3;", "string nickname = 4;"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q after removing phone\nActual content:\n%s", expected, content)
+		}
+	}
+
+	// Re-adding phone restores its original number
+	content = convert("phone", "name", "email", "nickname")
+	for _, expected := range []string{"string phone = 2;", "string name = 1;", "string email = 3;", "string nickname = 4;"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q after re-adding phone\nActual content:\n%s", expected, content)
+		}
+	}
+
+	fieldMap, err := xsd2proto.LoadFieldMap(fieldMapPath)
+	if err != nil {
+		t.Fatalf("Failed to load field map: %v", err)
+	}
+	if fieldMap["Person"]["phone"] != 2 || len(fieldMap["Person"]) != 4 {
+		t.Errorf("Unexpected field map: %v", fieldMap)
+	}
+}
+
+// TestFieldMapInvalidNumber tests that a field map number protobuf does not accept is rejected
+func TestFieldMapInvalidNumber(t *testing.T) {
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "person.xsd")
+	if err := os.WriteFile(xsdPath, []byte(fieldMapXSD("name")), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+	fieldMapPath := filepath.Join(dir, "fields.json")
+	if err := os.WriteFile(fieldMapPath, []byte(`{"Person": {"name": 19000}}`), 0644); err != nil {
+		t.Fatalf("Failed to write field map: %v", err)
+	}
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = filepath.Join(dir, "person.proto")
+	opts.FieldMapPath = fieldMapPath
+	err := xsd2proto.ConvertFile(xsdPath, opts)
+	if err == nil || !strings.Contains(err.Error(), "field map number of name: field number 19000 is in the range 19000 to 19999 reserved by protobuf") {
+		t.Errorf("Expected a reserved range error, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+// TestE2EFieldNamingFlags tests the camelCase and PascalCase flags and their short forms
+func TestE2EFieldNamingFlags(t *testing.T) {
+	cli := buildCLI(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"default", nil, "string first_name = 1;"},
+		{"camel-case", []string{"--camel-case"}, "string firstName = 1;"},
+		{"camel-case short", []string{"-C"}, "string firstName = 1;"},
+		{"pascal-case", []string{"--pascal-case"}, "string FirstName = 1;"},
+		{"pascal-case short", []string{"-P2"}, "string FirstName = 1;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append(append([]string{}, tt.args...), "--stdout", "examples/001_simple/simple.xsd")
+			output, err := exec.Command(cli, args...).CombinedOutput()
+			if err != nil {
+				t.Fatalf("CLI conversion failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(string(output), tt.expected) {
+				t.Errorf("Generated proto should contain: %s\nActual output:\n%s", tt.expected, output)
+			}
+		})
+	}
+}
+
+// TestE2EFieldNamingFlagsExclusive tests that camelCase and PascalCase cannot be combined
+func TestE2EFieldNamingFlagsExclusive(t *testing.T) {
+	cli := buildCLI(t)
+
+	output, err := exec.Command(cli, "-C", "-P2", "--stdout", "examples/001_simple/simple.xsd").CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected an error when combining -C and -P2\nOutput: %s", output)
+	}
+	if !strings.Contains(string(output), "Cannot use both") {
+		t.Errorf("Expected a mutually exclusive flags error\nActual output:\n%s", output)
+	}
+}
+
+// TestWithFieldNamingStyle tests the WithCamelCase and WithPascalCase library options
+func TestWithFieldNamingStyle(t *testing.T) {
+	setupTest(t)
+
+	tests := []struct {
+		name     string
+		opts     []xsd2proto.Option
+		expected string
+	}{
+		{"camel-case", []xsd2proto.Option{xsd2proto.WithCamelCase()}, "string firstName = 1;"},
+		{"pascal-case", []xsd2proto.Option{xsd2proto.WithPascalCase()}, "string FirstName = 1;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := xsd2proto.NewConvertOptions(tt.opts...)
+			opts.OutputPath = filepath.Join(t.TempDir(), "naming.proto")
+			if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err != nil {
+				t.Fatalf("ConvertFile failed: %v", err)
+			}
+			content, err := os.ReadFile(opts.OutputPath)
+			if err != nil {
+				t.Fatalf("Failed to read output file: %v", err)
+			}
+			if !strings.Contains(string(content), tt.expected) {
+				t.Errorf("Generated proto should contain: %s\nActual content:\n%s", tt.expected, content)
+			}
+		})
+	}
+
+	opts := xsd2proto.NewConvertOptions(xsd2proto.WithCamelCase(), xsd2proto.WithPascalCase())
+	opts.OutputPath = filepath.Join(t.TempDir(), "naming.proto")
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err == nil {
+		t.Error("ConvertFile should reject combining camelCase and PascalCase")
+	}
+}
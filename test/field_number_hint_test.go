@@ -0,0 +1,202 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestProtoFieldNumberAppInfo tests that proto-field-number appinfo pins a field number
+func TestProtoFieldNumberAppInfo(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string"/>
+            <xs:element name="total" type="xs:double">
+                <xs:annotation>
+                    <xs:appinfo source="proto-field-number">15</xs:appinfo>
+                </xs:annotation>
+            </xs:element>
+            <xs:element name="note" type="xs:string"/>
+            <xs:element name="code" type="xs:int">
+                <xs:annotation>
+                    <xs:appinfo source="proto-field-number">3</xs:appinfo>
+                </xs:annotation>
+            </xs:element>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	result := generateProto(t, xsd, converter.New())
+
+	expected := []string{
+		"string id = 1;\n",
+		"double total = 15;\n",
+		"string note = 2;\n",
+		"int32 code = 3;\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "appinfo") {
+		t.Errorf("Field number hint should not be kept as appinfo, got:\n%s", result)
+	}
+}
+
+// TestProtoFieldNumberAppInfoSkipsPinnedNumbers tests that automatic numbering skips pinned numbers
+func TestProtoFieldNumberAppInfoSkipsPinnedNumbers(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="total" type="xs:double">
+                <xs:annotation>
+                    <xs:appinfo source="proto-field-number">2</xs:appinfo>
+                </xs:annotation>
+            </xs:element>
+            <xs:element name="id" type="xs:string"/>
+            <xs:element name="note" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	result := generateProto(t, xsd, converter.New())
+
+	for _, want := range []string{"double total = 2;\n", "string id = 1;\n", "string note = 3;\n"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+}
+
+// TestProtoFieldNumberAppInfoPinnedAfterAutoNumbered tests that a pinned element
+// keeps its number when elements numbered automatically come before it, as when
+// a field is moved in the XSD and pinned to its old number
+func TestProtoFieldNumberAppInfoPinnedAfterAutoNumbered(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Person">
+        <xs:sequence>
+            <xs:element name="lastName" type="xs:string"/>
+            <xs:element name="firstName" type="xs:string">
+                <xs:annotation>
+                    <xs:appinfo source="proto-field-number">1</xs:appinfo>
+                </xs:annotation>
+            </xs:element>
+            <xs:element name="email" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	result := generateProto(t, xsd, converter.New())
+
+	for _, want := range []string{"string last_name = 2;\n", "string first_name = 1;\n", "string email = 3;\n"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+}
+
+// TestProtoFieldNumberAppInfoConflict tests that two elements pinning one number are reported
+func TestProtoFieldNumberAppInfoConflict(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string">
+                <xs:annotation>
+                    <xs:appinfo source="proto-field-number">1</xs:appinfo>
+                </xs:annotation>
+            </xs:element>
+            <xs:element name="total" type="xs:double">
+                <xs:annotation>
+                    <xs:appinfo source="proto-field-number">1</xs:appinfo>
+                </xs:annotation>
+            </xs:element>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	schema, err := parser.New().Parse(strings.NewReader(xsd))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	_, err = converter.New().Convert(schema)
+	if err == nil {
+		t.Fatal("Expected a field number conflict error")
+	}
+	if !strings.Contains(err.Error(), "field number 1 of total conflicts with field id") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// TestProtoFieldNumberAppInfoReservedConflict tests that a pin on a number of a
+// removed field is reported
+func TestProtoFieldNumberAppInfoReservedConflict(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string"/>
+            <xs:element name="total" type="xs:double">
+                <xs:annotation>
+                    <xs:appinfo source="proto-field-number">4</xs:appinfo>
+                </xs:annotation>
+            </xs:element>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	schema, err := parser.New().Parse(strings.NewReader(xsd))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	conv := converter.New()
+	conv.SetReservedMap(converter.ReservedMap{"Order": {{Number: 4, Name: "discount"}}})
+	_, err = conv.Convert(schema)
+	if err == nil || !strings.Contains(err.Error(), "field number 4 of total conflicts with field reserved discount") {
+		t.Errorf("Expected a conflict with the reserved field, got: %v", err)
+	}
+}
+
+// TestProtoFieldNumberAppInfoInvalid tests that a non-numeric hint is rejected by the parser
+func TestProtoFieldNumberAppInfoInvalid(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:element name="total" type="xs:double">
+        <xs:annotation>
+            <xs:appinfo source="proto-field-number">fifteen</xs:appinfo>
+        </xs:annotation>
+    </xs:element>
+</xs:schema>`
+
+	_, err := parser.New().Parse(strings.NewReader(xsd))
+	if err == nil || !strings.Contains(err.Error(), `invalid proto-field-number appinfo "fifteen"`) {
+		t.Errorf("Expected an invalid hint error, got: %v", err)
+	}
+}
+
+// TestProtoFieldNumberAppInfoReservedRange tests that a hint in the range reserved
+// by protobuf is rejected by the parser
+func TestProtoFieldNumberAppInfoReservedRange(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:element name="total" type="xs:double">
+        <xs:annotation>
+            <xs:appinfo source="proto-field-number">19500</xs:appinfo>
+        </xs:annotation>
+    </xs:element>
+</xs:schema>`
+
+	_, err := parser.New().Parse(strings.NewReader(xsd))
+	if err == nil || !strings.Contains(err.Error(), "field number 19500 is in the range 19000 to 19999 reserved by protobuf") {
+		t.Errorf("Expected a reserved range error, got: %v", err)
+	}
+}
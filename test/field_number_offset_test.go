@@ -0,0 +1,126 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+// TestE2EFieldNumberOffset tests that --field-number-offset shifts field numbering
+func TestE2EFieldNumberOffset(t *testing.T) {
+	cli := buildCLI(t)
+
+	outputFile := "test_field_offset.proto"
+	defer os.Remove(outputFile)
+
+	cmd := exec.Command(cli, "--field-number-offset", "10", "-o", outputFile, "examples/001_simple/simple.xsd")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI conversion failed: %v\nOutput: %s", err, output)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	protoContent := string(content)
+
+	expectedParts := []string{
+		`syntax = "proto3";`,
+		"string first_name = 10;",
+		"string last_name = 11;",
+		"int32 age = 12;",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(protoContent, part) {
+			t.Errorf("Generated proto should contain: %s\nActual content:\n%s", part, protoContent)
+		}
+	}
+
+	if strings.Contains(protoContent, " = 1;") {
+		t.Errorf("No field should be numbered 1 with offset 10\nActual content:\n%s", protoContent)
+	}
+}
+
+// TestWithFieldNumberOffset tests the library option for field number offsets
+func TestWithFieldNumberOffset(t *testing.T) {
+	setupTest(t)
+
+	outputFile := filepath.Join(t.TempDir(), "offset.proto")
+	opts := xsd2proto.NewConvertOptions(xsd2proto.WithFieldNumberOffset(100))
+	opts.OutputPath = outputFile
+
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(content), "string first_name = 100;") {
+		t.Errorf("First field should be numbered 100\nActual content:\n%s", content)
+	}
+
+	opts.FieldNumberOffset = 0
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err != nil {
+		t.Fatalf("ConvertFile should treat a zero offset as 1: %v", err)
+	}
+	content, err = os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(content), "string first_name = 1;") {
+		t.Errorf("First field should be numbered 1 with a zero offset\nActual content:\n%s", content)
+	}
+
+	opts.FieldNumberOffset = -1
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err == nil {
+		t.Error("ConvertFile should reject a negative field number offset")
+	}
+}
+
+// TestFieldNumberOffsetSkipsReservedRange tests that numbering skips the field
+// numbers 19000 to 19999 reserved by protobuf
+func TestFieldNumberOffsetSkipsReservedRange(t *testing.T) {
+	setupTest(t)
+
+	outputFile := filepath.Join(t.TempDir(), "offset.proto")
+	opts := xsd2proto.NewConvertOptions(xsd2proto.WithFieldNumberOffset(18999))
+	opts.OutputPath = outputFile
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	for _, part := range []string{"string first_name = 18999;", "string last_name = 20000;", "int32 age = 20001;"} {
+		if !strings.Contains(string(content), part) {
+			t.Errorf("Generated proto should contain: %s\nActual content:\n%s", part, content)
+		}
+	}
+}
+
+// TestFieldNumberOffsetMaximum tests that running out of field numbers above the
+// offset is an error
+func TestFieldNumberOffsetMaximum(t *testing.T) {
+	setupTest(t)
+
+	opts := xsd2proto.NewConvertOptions(xsd2proto.WithFieldNumberOffset(536870911))
+	opts.OutputPath = filepath.Join(t.TempDir(), "offset.proto")
+	err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts)
+	if err == nil || !strings.Contains(err.Error(), "no field number left, the maximum is 536870911") {
+		t.Errorf("Expected an error beyond the maximum field number, got %v", err)
+	}
+
+	opts.FieldNumberOffset = 536870912
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err == nil {
+		t.Error("ConvertFile should reject an offset above the maximum field number")
+	}
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/merger"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+func fileOptionsProtoFile() *model.ProtoFile {
+	return &model.ProtoFile{
+		Syntax:  "proto3",
+		Package: "options",
+		Options: []model.ProtoOption{
+			model.StringOption("go_package", "example.com/options"),
+			model.IdentOption("optimize_for", "SPEED"),
+			model.IdentOption("java_multiple_files", "true"),
+			model.IdentOption("cc_enable_arenas", "true"),
+		},
+		Messages: []model.ProtoMessage{{Name: "Order"}},
+	}
+}
+
+// TestGeneratorFileOptions tests that only string options are quoted
+func TestGeneratorFileOptions(t *testing.T) {
+	content, err := generator.New(generator.WithHeader(false, "")).Generate(fileOptionsProtoFile())
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+
+	expected := `option cc_enable_arenas = true;
+option go_package = "example.com/options";
+option java_multiple_files = true;
+option optimize_for = SPEED;
+`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Expected the options sorted by key:\n%s\nActual content:\n%s", expected, content)
+	}
+}
+
+// TestDescriptorFileOptions tests that unquoted options are set in the descriptor
+func TestDescriptorFileOptions(t *testing.T) {
+	descriptor, err := generator.GenerateDescriptor(fileOptionsProtoFile())
+	if err != nil {
+		t.Fatalf("Failed to build descriptor: %v", err)
+	}
+	options := descriptor.GetOptions()
+	if options.GetOptimizeFor() != descriptorpb.FileOptions_SPEED || !options.GetJavaMultipleFiles() ||
+		!options.GetCcEnableArenas() || options.GetGoPackage() != "example.com/options" {
+		t.Errorf("Unexpected file options: %v", options)
+	}
+
+	protoFile := fileOptionsProtoFile()
+	protoFile.SetOption(model.IdentOption("optimize_for", "FAST"))
+	if _, err := generator.GenerateDescriptor(protoFile); err == nil || !strings.Contains(err.Error(), "invalid value FAST for option optimize_for") {
+		t.Errorf("Expected an invalid value error, got %v", err)
+	}
+}
+
+// TestParseProtoFileOptions tests that unquoted file options are read back as they are written
+func TestParseProtoFileOptions(t *testing.T) {
+	content, err := generator.New().Generate(fileOptionsProtoFile())
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+	file, err := merger.ParseProtoFile(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseProtoFile failed: %v", err)
+	}
+	for _, option := range fileOptionsProtoFile().Options {
+		if parsed, ok := file.Option(option.Key); !ok || parsed != option {
+			t.Errorf("Expected option %+v, got %+v", option, parsed)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/model"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// The complex type item is renamed to Item2 because the Item enum takes its
+// name, and Container refers to it before it is declared
+const forwardReferenceXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/tree">
+
+    <xs:simpleType name="Item">
+        <xs:restriction base="xs:QName">
+            <xs:enumeration value="leaf"/>
+        </xs:restriction>
+    </xs:simpleType>
+
+    <xs:complexType name="Container">
+        <xs:sequence>
+            <xs:element name="entry" type="item" minOccurs="0" maxOccurs="unbounded"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:complexType name="item">
+        <xs:sequence>
+            <xs:element name="parent" type="Container" minOccurs="0"/>
+        </xs:sequence>
+    </xs:complexType>
+
+</xs:schema>`
+
+// TestMutuallyReferencingTypes tests that types referring to each other use their final message names
+func TestMutuallyReferencingTypes(t *testing.T) {
+	content := generateProto(t, forwardReferenceXSD, nil)
+
+	expectedParts := []string{
+		"message Container {\n  repeated Item2 entry = 1;\n}",
+		"message Item2 {\n  optional Container parent = 1;\n}",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(content, part) {
+			t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", part, content)
+		}
+	}
+}
+
+// TestMutuallyReferencingTypesMerged tests forward references when converting several schemas together
+func TestMutuallyReferencingTypesMerged(t *testing.T) {
+	schema, err := parser.New().ParseString(forwardReferenceXSD)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	protoFile, err := converter.New().ConvertMultiple([]*model.Schema{schema})
+	if err != nil {
+		t.Fatalf("Failed to convert schemas: %v", err)
+	}
+	for _, message := range protoFile.Messages {
+		if message.Name == "Container" && message.Fields[0].Type != "Item2" {
+			t.Errorf("Expected Container.entry to refer to Item2, got %s", message.Fields[0].Type)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// TestGeneratorOptions tests the functional options of the generator
+func TestGeneratorOptions(t *testing.T) {
+	protoFile := &model.ProtoFile{
+		Syntax:  "proto3",
+		Package: "options",
+		Messages: []model.ProtoMessage{
+			{
+				Name: "Zebra",
+				Fields: []model.ProtoField{
+					{Name: "stripes", Type: "int32", Number: 1, Comment: "Stripe count"},
+				},
+			},
+			{Name: "Antelope"},
+		},
+	}
+
+	gen := generator.New(generator.WithHeader(false, ""))
+	content, err := gen.Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+	if !strings.Contains(content, "  optional int32 stripes = 1; // Stripe count\n") {
+		t.Errorf("Default output should use two-space indentation and trailing comments\nActual content:\n%s", content)
+	}
+	if strings.Index(content, "message Zebra") > strings.Index(content, "message Antelope") {
+		t.Errorf("Default output should keep schema order\nActual content:\n%s", content)
+	}
+
+	gen = generator.New(
+		generator.WithHeader(false, ""),
+		generator.WithIndent("\t"),
+		generator.WithSortOutput(true),
+		generator.WithCommentStyle(generator.CommentStyleLeading),
+	)
+	content, err = gen.Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+	if !strings.Contains(content, "\t// Stripe count\n\toptional int32 stripes = 1;\n") {
+		t.Errorf("Expected tab indentation with the comment above the field\nActual content:\n%s", content)
+	}
+	if strings.Index(content, "message Antelope") > strings.Index(content, "message Zebra") {
+		t.Errorf("Sorted output should emit messages by name\nActual content:\n%s", content)
+	}
+	if strings.Contains(content, "This proto file was automatically generated") {
+		t.Errorf("Header should be omitted\nActual content:\n%s", content)
+	}
+}
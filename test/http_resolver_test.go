@@ -0,0 +1,243 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/model"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// newSchemaServer serves a remote schema including a sibling file and counts the requests
+func newSchemaServer(t *testing.T, requests *int32) *httptest.Server {
+	t.Helper()
+	schemas := map[string]string{
+		"/schemas/common.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/common">
+  <xs:include schemaLocation="address.xsd"/>
+  <xs:complexType name="Contact">
+    <xs:sequence><xs:element name="email" type="xs:string"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`,
+		"/schemas/address.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/common">
+  <xs:complexType name="Address">
+    <xs:sequence><xs:element name="city" type="xs:string"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		content, ok := schemas[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestHTTPResolverCachesSchemas tests that a schema is fetched once and then served from the cache
+func TestHTTPResolverCachesSchemas(t *testing.T) {
+	var requests int32
+	server := newSchemaServer(t, &requests)
+	cacheDir := t.TempDir()
+	location := server.URL + "/schemas/common.xsd"
+
+	resolver, err := parser.NewHTTPResolver(5*time.Second, cacheDir)
+	if err != nil {
+		t.Fatalf("Failed to create resolver: %v", err)
+	}
+	path, err := resolver.Resolve("", location, t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to fetch schema: %v", err)
+	}
+	sum := sha256.Sum256([]byte(location))
+	expected := filepath.Join(cacheDir, hex.EncodeToString(sum[:]), "common.xsd")
+	if path != expected {
+		t.Errorf("Expected schema cached at %s, got %s", expected, path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(content), `name="Contact"`) {
+		t.Errorf("Expected the fetched schema in the cache, got %q (%v)", content, err)
+	}
+
+	// A new resolver on the same cache, as in a later run, needs no network access
+	server.Close()
+	cached, err := parser.NewHTTPResolver(5*time.Second, cacheDir)
+	if err != nil {
+		t.Fatalf("Failed to create resolver: %v", err)
+	}
+	path, err = cached.Resolve("", location, t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected the schema from the cache, got error: %v", err)
+	}
+	if path != expected {
+		t.Errorf("Expected cached schema %s, got %s", expected, path)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+
+	if path, err := cached.Resolve("", "local.xsd", t.TempDir()); err != nil || path != "" {
+		t.Errorf("Expected local locations to be left to the parser, got %q (%v)", path, err)
+	}
+}
+
+// TestHTTPResolverImports tests that remote imports and their relative includes are parsed
+func TestHTTPResolverImports(t *testing.T) {
+	var requests int32
+	server := newSchemaServer(t, &requests)
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "main.xsd")
+	mainXSD := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:c="http://example.com/common"
+           targetNamespace="http://example.com/orders">
+  <xs:import namespace="http://example.com/common" schemaLocation="` + server.URL + `/schemas/common.xsd"/>
+  <xs:complexType name="Order">
+    <xs:sequence><xs:element name="contact" type="c:Contact"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+	if err := os.WriteFile(mainPath, []byte(mainXSD), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	resolver, err := parser.NewHTTPResolver(5*time.Second, t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create resolver: %v", err)
+	}
+	p := parser.New()
+	p.SetResolver(resolver)
+	schema, err := p.ParseFileWithImports(mainPath)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	if len(schema.ImportedSchemas) != 1 {
+		t.Fatalf("Expected one remote import, got %d", len(schema.ImportedSchemas))
+	}
+
+	var names []string
+	var collect func(s *model.Schema)
+	collect = func(s *model.Schema) {
+		for _, complexType := range s.ComplexTypes {
+			names = append(names, complexType.Name)
+		}
+		for _, imported := range s.ImportedSchemas {
+			collect(imported)
+		}
+	}
+	collect(schema.ImportedSchemas[0])
+	if got := strings.Join(names, ","); !strings.Contains(got, "Contact") || !strings.Contains(got, "Address") {
+		t.Errorf("Expected Contact and the included Address, got %v", names)
+	}
+}
+
+// TestHTTPResolverFetchError tests that a failed download is reported
+func TestHTTPResolverFetchError(t *testing.T) {
+	var requests int32
+	server := newSchemaServer(t, &requests)
+
+	resolver, err := parser.NewHTTPResolver(5*time.Second, t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create resolver: %v", err)
+	}
+	_, err = resolver.Resolve("", server.URL+"/missing.xsd", t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected a 404 error, got %v", err)
+	}
+}
+
+// TestHTTPResolverCloseRemovesTemporaryCache tests that Close removes the
+// temporary cache directory but keeps one that was given
+func TestHTTPResolverCloseRemovesTemporaryCache(t *testing.T) {
+	var requests int32
+	server := newSchemaServer(t, &requests)
+
+	temporary, err := parser.NewHTTPResolver(5*time.Second, "")
+	if err != nil {
+		t.Fatalf("Failed to create resolver: %v", err)
+	}
+	path, err := temporary.Resolve("", server.URL+"/schemas/common.xsd", t.TempDir())
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Expected the fetched schema in the cache: %v", err)
+	}
+	if err := temporary.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := os.Stat(temporary.CacheDir); !os.IsNotExist(err) {
+		t.Errorf("Expected the temporary cache to be removed, got %v", err)
+	}
+
+	cacheDir := t.TempDir()
+	kept, err := parser.NewHTTPResolver(5*time.Second, cacheDir)
+	if err != nil {
+		t.Fatalf("Failed to create resolver: %v", err)
+	}
+	path, err = kept.Resolve("", server.URL+"/schemas/common.xsd", t.TempDir())
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if err := kept.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the given cache to be kept: %v", err)
+	}
+}
+
+// TestConvertFetchRemoteRemovesTemporaryCache tests that a conversion with
+// --fetch-remote and no cache directory leaves no temporary directory behind
+func TestConvertFetchRemoteRemovesTemporaryCache(t *testing.T) {
+	var requests int32
+	server := newSchemaServer(t, &requests)
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "main.xsd")
+	mainXSD := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:c="http://example.com/common"
+           targetNamespace="http://example.com/orders">
+  <xs:import namespace="http://example.com/common" schemaLocation="` + server.URL + `/schemas/common.xsd"/>
+  <xs:complexType name="Order">
+    <xs:sequence><xs:element name="contact" type="c:Contact"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+	if err := os.WriteFile(mainPath, []byte(mainXSD), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = filepath.Join(dir, "main.proto")
+	opts.FetchRemote = true
+	if err := xsd2proto.ConvertFile(mainPath, opts); err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+	if requests == 0 {
+		t.Fatal("Expected the remote import to be fetched")
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected the temporary schema cache to be removed, found %v", entries)
+	}
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// TestImportsSortedAlphabetically tests that well-known type imports are emitted in a stable order
+func TestImportsSortedAlphabetically(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/imports"
+           elementFormDefault="qualified">
+
+    <xs:complexType name="Event">
+        <xs:sequence>
+            <xs:element name="startTime" type="xs:dateTime"/>
+            <xs:element name="length" type="xs:duration"/>
+        </xs:sequence>
+    </xs:complexType>
+
+</xs:schema>`
+
+	expected := "package imports;\n\n" +
+		"import \"google/protobuf/duration.proto\";\n" +
+		"import \"google/protobuf/timestamp.proto\";\n\n"
+
+	// Map iteration order is random, so repeat to catch unstable ordering
+	for i := 0; i < 20; i++ {
+		content := generateProto(t, xsdContent, nil)
+		if !strings.Contains(content, expected) {
+			t.Fatalf("Import section should be exactly:\n%s\nActual content:\n%s", expected, content)
+		}
+	}
+}
+
+// TestLocalImportsBeforeWellKnownTypes tests that project imports precede google/protobuf imports
+func TestLocalImportsBeforeWellKnownTypes(t *testing.T) {
+	protoFile := &model.ProtoFile{
+		Syntax:  "proto3",
+		Package: "imports",
+		Imports: []string{
+			"google/protobuf/timestamp.proto",
+			"common/types.proto",
+			"google/protobuf/any.proto",
+			"billing/invoice.proto",
+		},
+	}
+
+	gen := generator.New(generator.WithHeader(false, ""))
+	content, err := gen.Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+
+	expected := "import \"billing/invoice.proto\";\n" +
+		"import \"common/types.proto\";\n" +
+		"import \"google/protobuf/any.proto\";\n" +
+		"import \"google/protobuf/timestamp.proto\";\n\n"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Import section should be exactly:\n%s\nActual content:\n%s", expected, content)
+	}
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+const importSortXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/orders"
+           targetNamespace="http://example.com/orders">
+  <xs:complexType name="PostalAddress">
+    <xs:sequence>
+      <xs:element name="street" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="createdAt" type="xs:dateTime"/>
+      <xs:element name="address" type="tns:PostalAddress"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// importSortFile has a project import sorting after a well-known type
+func importSortFile() *model.ProtoFile {
+	return &model.ProtoFile{
+		Syntax:   "proto3",
+		Package:  "orders",
+		Imports:  []string{"google/protobuf/timestamp.proto", "orders/address.proto"},
+		Messages: []model.ProtoMessage{{Name: "Order"}},
+	}
+}
+
+// TestImportSortOrder tests the order of two imports under each mode
+func TestImportSortOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    generator.ImportSortOrder
+		expected string
+	}{
+		{"dependency first", generator.ImportSortDependencyFirst,
+			"import \"orders/address.proto\";\nimport \"google/protobuf/timestamp.proto\";\n"},
+		{"alphabetical", generator.ImportSortAlphabetical,
+			"import \"google/protobuf/timestamp.proto\";\nimport \"orders/address.proto\";\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := generator.New(generator.WithHeader(false, ""))
+			gen.SetImportSortOrder(tt.order)
+			content, err := gen.Generate(importSortFile())
+			if err != nil {
+				t.Fatalf("Failed to generate proto: %v", err)
+			}
+			if !strings.Contains(content, "\n\n"+tt.expected+"\n") {
+				t.Errorf("Expected imports:\n%s\ngot:\n%s", tt.expected, content)
+			}
+		})
+	}
+}
+
+// TestImportGroupSeparator tests that a blank line separates well-known imports
+// from project imports
+func TestImportGroupSeparator(t *testing.T) {
+	gen := generator.New(generator.WithHeader(false, ""))
+	gen.SetImportGroupSeparator(true)
+	content, err := gen.Generate(importSortFile())
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+	expected := "import \"orders/address.proto\";\n\nimport \"google/protobuf/timestamp.proto\";\n\nmessage Order"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Expected separated import groups:\n%s\ngot:\n%s", expected, content)
+	}
+}
+
+// TestImportSortOption tests the ImportSortOrder option and that unknown orders are rejected
+func TestImportSortOption(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "orders.xsd")
+	if err := os.WriteFile(inputPath, []byte(importSortXSD), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.SplitFiles = true
+	opts.OutputPath = dir
+	opts.ImportSortOrder = xsd2proto.ImportSortAlpha
+	if err := xsd2proto.ConvertFile(inputPath, opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "order.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	content := string(data)
+	wellKnown := strings.Index(content, `import "google/protobuf/timestamp.proto";`)
+	local := strings.Index(content, `import "postal_address.proto";`)
+	if wellKnown < 0 || local < 0 || local < wellKnown {
+		t.Errorf("Expected the imports sorted alphabetically:\n%s", content)
+	}
+
+	opts.ImportSortOrder = "random"
+	if err := xsd2proto.ConvertFile(inputPath, opts); err == nil || !strings.Contains(err.Error(), "import sort order") {
+		t.Errorf("Expected an import sort order error, got %v", err)
+	}
+}
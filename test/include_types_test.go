@@ -0,0 +1,78 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestIncludedTypesInSequence tests that a sequence can reference a type defined in an included file
+func TestIncludedTypesInSequence(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/shop"
+           targetNamespace="http://example.com/shop">
+    <xs:include schemaLocation="common.xsd"/>
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string"/>
+            <xs:element name="shippingAddress" type="tns:Address"/>
+            <xs:element name="billingAddress" type="tns:Address" minOccurs="0"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`,
+		"common.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/shop">
+    <xs:complexType name="Address">
+        <xs:sequence>
+            <xs:element name="street" type="xs:string"/>
+            <xs:element name="city" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	schema, err := parser.New().ParseFileWithImports(filepath.Join(dir, "main.xsd"))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+
+	gen := generator.New(generator.WithHeader(false, ""))
+	result, err := gen.Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+
+	expected := []string{
+		"message Order {",
+		"message Address {",
+		"Address shipping_address = 2;",
+		"Address billing_address = 3;",
+		"string street = 1;",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "string shipping_address") {
+		t.Errorf("Included type should not fall back to string, got:\n%s", result)
+	}
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+const indentXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/indent">
+  <xs:simpleType name="Status">
+    <xs:restriction base="xs:int">
+      <xs:enumeration value="1"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:complexType name="Payment">
+    <xs:choice>
+      <xs:element name="card" type="xs:string"/>
+      <xs:sequence>
+        <xs:element name="iban" type="xs:string"/>
+      </xs:sequence>
+    </xs:choice>
+  </xs:complexType>
+</xs:schema>`
+
+// indentedBlocks returns the message, nested message, oneof and enum lines of indentXSD indented with indent
+func indentedBlocks(indent string) []string {
+	return []string{
+		"\n" + indent + "STATUS_UNSPECIFIED = 0;\n",
+		"\n" + indent + "message Payment_1Choice {\n" + indent + indent + "string iban = 1;\n" + indent + "}\n",
+		"\n" + indent + "oneof choice {\n" + indent + indent + "string card = 1;\n",
+	}
+}
+
+// TestCLIIndent tests that --indent sets the indentation of every block
+func TestCLIIndent(t *testing.T) {
+	cli := buildCLI(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "indent.xsd")
+	if err := os.WriteFile(xsdPath, []byte(indentXSD), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		flag   string
+		indent string
+	}{
+		{`\t`, "\t"},
+		{"    ", "    "},
+	} {
+		output, err := exec.Command(cli, "--stdout", "--indent", tc.flag, xsdPath).CombinedOutput()
+		if err != nil {
+			t.Fatalf("CLI failed: %v\n%s", err, output)
+		}
+		for _, block := range indentedBlocks(tc.indent) {
+			if !strings.Contains(string(output), block) {
+				t.Errorf("Expected %q with --indent %q\nActual output:\n%s", block, tc.flag, output)
+			}
+		}
+	}
+
+	// Without the flag the output keeps two spaces
+	output, err := exec.Command(cli, "--stdout", xsdPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI failed: %v\n%s", err, output)
+	}
+	for _, block := range indentedBlocks("  ") {
+		if !strings.Contains(string(output), block) {
+			t.Errorf("Expected %q by default\nActual output:\n%s", block, output)
+		}
+	}
+}
+
+// TestConvertIndent tests the WithIndent option and the rejection of other characters
+func TestConvertIndent(t *testing.T) {
+	var output strings.Builder
+	opts := xsd2proto.NewConvertOptions(xsd2proto.WithIndent("\t"))
+	opts.Output = &output
+	if err := xsd2proto.ConvertReader(strings.NewReader(indentXSD), opts); err != nil {
+		t.Fatalf("ConvertReader failed: %v", err)
+	}
+	if !strings.Contains(output.String(), "\n\tSTATUS_UNSPECIFIED = 0;\n") {
+		t.Errorf("Expected tab-indented output, got:\n%s", output.String())
+	}
+
+	opts.Indent = "--"
+	if err := xsd2proto.ConvertReader(strings.NewReader(indentXSD), opts); err == nil || !strings.Contains(err.Error(), "indent must consist of spaces and tabs") {
+		t.Errorf("Expected an indent error, got %v", err)
+	}
+}
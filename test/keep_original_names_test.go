@@ -0,0 +1,92 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const keepOriginalNamesXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="http://example.com/names" targetNamespace="http://example.com/names">
+  <xs:simpleType name="order_STATUS">
+    <xs:restriction base="xs:int">
+      <xs:enumeration value="1"/>
+      <xs:enumeration value="2"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:complexType name="MY_TYPE">
+    <xs:sequence>
+      <xs:element name="orderId" type="xs:string"/>
+      <xs:element name="status" type="tns:order_STATUS"/>
+      <xs:element name="child" type="tns:MY_TYPE" minOccurs="0"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="my_type">
+    <xs:sequence>
+      <xs:element name="value" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// TestKeepOriginalNames tests that message and enum names are the XSD type names as written
+func TestKeepOriginalNames(t *testing.T) {
+	conv := converter.New()
+	conv.SetKeepOriginalNames(true)
+	content := generateProto(t, keepOriginalNamesXSD, conv)
+
+	expected := []string{
+		"message MY_TYPE {",
+		"enum order_STATUS {",
+		"string order_id = 1;",
+		"order_STATUS status = 2;",
+		"MY_TYPE child = 3;",
+		"message my_type {",
+	}
+	for _, line := range expected {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q in output:\n%s", line, content)
+		}
+	}
+	if strings.Contains(content, "MyType") {
+		t.Errorf("Expected no PascalCase message names:\n%s", content)
+	}
+}
+
+// TestKeepOriginalNamesCollision tests that taken names still get a numbered suffix
+func TestKeepOriginalNamesCollision(t *testing.T) {
+	const xsd = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:complexType name="MY_TYPE">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:simpleType name="MY_TYPE">
+    <xs:restriction base="xs:int">
+      <xs:enumeration value="1"/>
+    </xs:restriction>
+  </xs:simpleType>
+</xs:schema>`
+
+	conv := converter.New()
+	conv.SetKeepOriginalNames(true)
+	content := generateProto(t, xsd, conv)
+
+	for _, line := range []string{"enum MY_TYPE {", "message MY_TYPE2 {"} {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q in output:\n%s", line, content)
+		}
+	}
+}
+
+// TestKeepOriginalNamesDefault tests that type names are PascalCased by default
+func TestKeepOriginalNamesDefault(t *testing.T) {
+	content := generateProto(t, keepOriginalNamesXSD, nil)
+
+	for _, line := range []string{"message MyType {", "MyType child = 3;"} {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q in output:\n%s", line, content)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+// TestLanguageOptionFlags tests that the language option flags add file options in alphabetical order
+func TestLanguageOptionFlags(t *testing.T) {
+	cli := buildCLI(t)
+
+	outputFile := filepath.Join(t.TempDir(), "simple.proto")
+	cmd := exec.Command(cli,
+		"--java-package", "com.example",
+		"--java-outer-classname", "SimpleProto",
+		"--csharp-namespace", "Example.Simple",
+		"--objc-class-prefix", "EXS",
+		"--php-namespace", "Example\\Simple",
+		"--ruby-package", "Example::Simple",
+		"-p", "example.com/simple",
+		"-o", outputFile, "examples/001_simple/simple.xsd")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("CLI failed: %v\nOutput: %s", err, output)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+
+	expected := `option csharp_namespace = "Example.Simple";
+option go_package = "example.com/simple";
+option java_outer_classname = "SimpleProto";
+option java_package = "com.example";
+option objc_class_prefix = "EXS";
+option php_namespace = "Example\Simple";
+option ruby_package = "Example::Simple";
+`
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("Expected options block:\n%s\nGot:\n%s", expected, content)
+	}
+}
+
+// TestLanguageOptionFunctions tests the With* options for language-specific file options
+func TestLanguageOptionFunctions(t *testing.T) {
+	setupTest(t)
+
+	outputFile := filepath.Join(t.TempDir(), "simple.proto")
+	opts := xsd2proto.NewConvertOptions(
+		xsd2proto.WithJavaPackage("com.example"),
+		xsd2proto.WithCSharpNamespace("Example.Simple"),
+	)
+	opts.OutputPath = outputFile
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	for _, want := range []string{`option java_package = "com.example";`, `option csharp_namespace = "Example.Simple";`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected output to contain %s\nGot:\n%s", want, content)
+		}
+	}
+	if strings.Contains(string(content), "ruby_package") {
+		t.Errorf("Unset options should not be emitted\nGot:\n%s", content)
+	}
+}
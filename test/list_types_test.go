@@ -0,0 +1,40 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+// TestBuiltInListTypesAreRepeated tests that IDREFS, NMTOKENS and ENTITIES become repeated strings
+func TestBuiltInListTypesAreRepeated(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Document">
+        <xs:sequence>
+            <xs:element name="references" type="xs:IDREFS"/>
+            <xs:element name="keywords" type="xs:NMTOKENS" minOccurs="0"/>
+            <xs:element name="id" type="xs:IDREF"/>
+        </xs:sequence>
+        <xs:attribute name="attachments" type="xs:ENTITIES"/>
+    </xs:complexType>
+</xs:schema>`
+
+	result := generateProto(t, xsd, converter.New())
+
+	expected := []string{
+		"repeated string references = 1;",
+		"repeated string keywords = 2;",
+		"string id = 3;",
+		"repeated string attachments = 4;",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "repeated string id") {
+		t.Errorf("xs:IDREF is a single reference and should not be repeated\nGot:\n%s", result)
+	}
+}
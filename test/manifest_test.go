@@ -0,0 +1,136 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+// writeManifestSchemas writes count schemas named schema<N>.xsd into dir
+func writeManifestSchemas(t *testing.T, dir string, count int) {
+	t.Helper()
+	for i := 1; i <= count; i++ {
+		content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/schema%d">
+  <xs:complexType name="Type%d">
+    <xs:sequence><xs:element name="value" type="xs:string"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`, i, i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("schema%d.xsd", i)), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write schema%d.xsd: %v", i, err)
+		}
+	}
+}
+
+// TestProcessManifest tests that each file of a manifest is converted next to its input
+func TestProcessManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeManifestSchemas(t, dir, 3)
+	manifest := fmt.Sprintf("# Schemas\nschema1.xsd\n\n  schema2.xsd\n%s\n", filepath.Join(dir, "schema3.xsd"))
+	manifestPath := filepath.Join(dir, "schemas.txt")
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	written, errs := xsd2proto.ProcessManifest(manifestPath, xsd2proto.DefaultConvertOptions())
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if len(written) != 3 {
+		t.Fatalf("Expected 3 written files, got %v", written)
+	}
+	for i := 1; i <= 3; i++ {
+		protoPath := filepath.Join(dir, fmt.Sprintf("schema%d.proto", i))
+		if written[i-1] != protoPath {
+			t.Errorf("Expected %s to be written, got %s", protoPath, written[i-1])
+		}
+		content, err := os.ReadFile(protoPath)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", protoPath, err)
+		}
+		if !strings.Contains(string(content), fmt.Sprintf("message Type%d {", i)) {
+			t.Errorf("Expected message Type%d in %s:\n%s", i, protoPath, content)
+		}
+	}
+}
+
+// TestProcessManifestCollectsErrors tests that a failing file does not stop the
+// others and its error names the file
+func TestProcessManifestCollectsErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeManifestSchemas(t, dir, 2)
+	manifestPath := filepath.Join(dir, "schemas.txt")
+	if err := os.WriteFile(manifestPath, []byte("schema1.xsd\nmissing.xsd\nschema2.xsd\n"), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	written, errs := xsd2proto.ProcessManifest(manifestPath, xsd2proto.DefaultConvertOptions())
+	if len(written) != 2 {
+		t.Errorf("Expected the 2 other files to be written, got %v", written)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "missing.xsd") {
+		t.Errorf("Expected one error naming missing.xsd, got %v", errs)
+	}
+}
+
+// TestManifestRejectsReport tests that a report, which every file of the manifest
+// would overwrite, cannot be combined with a manifest
+func TestManifestRejectsReport(t *testing.T) {
+	cli := buildCLI(t)
+	dir := t.TempDir()
+	writeManifestSchemas(t, dir, 3)
+	manifestPath := filepath.Join(dir, "schemas.txt")
+	if err := os.WriteFile(manifestPath, []byte("schema1.xsd\nschema2.xsd\nschema3.xsd\n"), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+	reportPath := filepath.Join(dir, "report.json")
+
+	output, err := exec.Command(cli, "--manifest", manifestPath, "--report", reportPath).CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected --manifest with --report to fail\nOutput: %s", output)
+	}
+	if !strings.Contains(string(output), "cannot write a report file for a manifest; each file would overwrite "+reportPath) {
+		t.Errorf("Expected a report error, got:\n%s", output)
+	}
+	if _, err := os.Stat(reportPath); !os.IsNotExist(err) {
+		t.Errorf("Expected no report to be written, got %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("schema%d.proto", i))); !os.IsNotExist(err) {
+			t.Errorf("Expected schema%d.proto not to be written, got %v", i, err)
+		}
+	}
+}
+
+// TestProcessManifestRejectsSingleOutputs tests that every option naming one
+// output file is rejected for a manifest
+func TestProcessManifestRejectsSingleOutputs(t *testing.T) {
+	dir := t.TempDir()
+	writeManifestSchemas(t, dir, 2)
+	manifestPath := filepath.Join(dir, "schemas.txt")
+	if err := os.WriteFile(manifestPath, []byte("schema1.xsd\nschema2.xsd\n"), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	outputs := map[string]func(*xsd2proto.ConvertOptions){
+		"report":        func(o *xsd2proto.ConvertOptions) { o.ReportPath = filepath.Join(dir, "out") },
+		"trace":         func(o *xsd2proto.ConvertOptions) { o.TracePath = filepath.Join(dir, "out") },
+		"descriptor":    func(o *xsd2proto.ConvertOptions) { o.DescriptorPath = filepath.Join(dir, "out") },
+		"DOT graph":     func(o *xsd2proto.ConvertOptions) { o.DotOutputPath = filepath.Join(dir, "out") },
+		"Markdown docs": func(o *xsd2proto.ConvertOptions) { o.MarkdownDocsPath = filepath.Join(dir, "out") },
+		"type graph":    func(o *xsd2proto.ConvertOptions) { o.TypeGraphPath = filepath.Join(dir, "out") },
+	}
+	for name, set := range outputs {
+		opts := xsd2proto.DefaultConvertOptions()
+		set(&opts)
+		_, errs := xsd2proto.ProcessManifest(manifestPath, opts)
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "cannot write a "+name+" file for a manifest") {
+			t.Errorf("Expected a %s error, got %v", name, errs)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const mapFieldsXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/inventory"
+           targetNamespace="http://example.com/inventory">
+
+    <xs:complexType name="Entry">
+        <xs:sequence>
+            <xs:element name="key" type="xs:string"/>
+            <xs:element name="value" type="xs:int"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:complexType name="ArrayOfEntry">
+        <xs:sequence>
+            <xs:element name="entry" type="tns:Entry" maxOccurs="unbounded"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:complexType name="Location">
+        <xs:sequence>
+            <xs:element name="city" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:complexType name="LocationEntry">
+        <xs:sequence>
+            <xs:element name="key" type="xs:token"/>
+            <xs:element name="value" type="tns:Location"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:complexType name="Pair">
+        <xs:sequence>
+            <xs:element name="key" type="xs:int"/>
+            <xs:element name="value" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:complexType name="Inventory">
+        <xs:sequence>
+            <xs:element name="stock" type="tns:ArrayOfEntry"/>
+            <xs:element name="sites" type="tns:LocationEntry" maxOccurs="unbounded"/>
+            <xs:element name="pair" type="tns:Pair"/>
+        </xs:sequence>
+    </xs:complexType>
+
+</xs:schema>`
+
+// TestMapFields tests that key-value entry types become map fields instead of messages
+func TestMapFields(t *testing.T) {
+	content := generateProto(t, mapFieldsXSD, nil)
+
+	expected := `message Inventory {
+  map<string, int32> stock = 1;
+  map<string, Location> sites = 2;
+  Pair pair = 3;
+}`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+	for _, name := range []string{"message Entry ", "message ArrayOfEntry ", "message LocationEntry "} {
+		if strings.Contains(content, name) {
+			t.Errorf("Expected no %q for a map entry type\nActual content:\n%s", name, content)
+		}
+	}
+	// A non-string key is not a valid map entry
+	if !strings.Contains(content, "message Pair {") {
+		t.Errorf("Expected Pair with an int key to stay a message\nActual content:\n%s", content)
+	}
+}
+
+// TestMapFieldsInChoice tests that map fields are kept out of oneof groups
+func TestMapFieldsInChoice(t *testing.T) {
+	xsd := strings.Replace(mapFieldsXSD, `<xs:element name="stock" type="tns:ArrayOfEntry"/>`,
+		`<xs:choice><xs:element name="stock" type="tns:ArrayOfEntry"/><xs:element name="note" type="xs:string"/></xs:choice>`, 1)
+	content := generateProto(t, xsd, nil)
+
+	if !strings.Contains(content, "\n  map<string, int32> stock = 1;") {
+		t.Errorf("Expected a map field outside the oneof\nActual content:\n%s", content)
+	}
+	if strings.Contains(content, "    map<string, int32> stock") {
+		t.Errorf("Expected no map field inside a oneof\nActual content:\n%s", content)
+	}
+}
+
+// TestMapEntryNames tests that custom key and value element names are detected
+func TestMapEntryNames(t *testing.T) {
+	xsd := strings.NewReplacer(`name="key" type="xs:string"`, `name="name" type="xs:string"`,
+		`name="value" type="xs:int"`, `name="amount" type="xs:int"`).Replace(mapFieldsXSD)
+
+	content := generateProto(t, xsd, nil)
+	if !strings.Contains(content, "message Entry {") {
+		t.Errorf("Expected Entry to stay a message with the default names\nActual content:\n%s", content)
+	}
+
+	conv := converter.New()
+	conv.SetMapEntryNames("name", "amount")
+	content = generateProto(t, xsd, conv)
+	if !strings.Contains(content, "map<string, int32> stock = 1;") {
+		t.Errorf("Expected a map field for the custom names\nActual content:\n%s", content)
+	}
+}
+
+// TestMapEntryNameFlags tests the --map-key-name and --map-value-name flags end to end
+func TestMapEntryNameFlags(t *testing.T) {
+	cli := buildCLI(t)
+	xsd := strings.NewReplacer(`name="key" type="xs:string"`, `name="id" type="xs:string"`,
+		`name="value" type="xs:int"`, `name="count" type="xs:int"`).Replace(mapFieldsXSD)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "inventory.xsd")
+	if err := os.WriteFile(xsdPath, []byte(xsd), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	output, err := exec.Command(cli, "--map-key-name", "id", "--map-value-name", "count", "--stdout", xsdPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(string(output), "map<string, int32> stock = 1;") {
+		t.Errorf("Expected a map field for the flag names\nActual output:\n%s", output)
+	}
+}
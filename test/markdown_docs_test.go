@@ -0,0 +1,107 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const markdownDocsXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/orders"
+           targetNamespace="http://example.com/orders">
+  <xs:simpleType name="OrderStatus">
+    <xs:annotation><xs:documentation>Processing state of an order</xs:documentation></xs:annotation>
+    <xs:restriction base="xs:QName">
+      <xs:enumeration value="open">
+        <xs:annotation><xs:documentation>Not yet shipped</xs:documentation></xs:annotation>
+      </xs:enumeration>
+      <xs:enumeration value="closed"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:complexType name="Order">
+    <xs:annotation>
+      <xs:documentation>
+        A customer order
+      </xs:documentation>
+    </xs:annotation>
+    <xs:sequence>
+      <xs:element name="id" type="xs:string">
+        <xs:annotation><xs:documentation>Unique order id | reference</xs:documentation></xs:annotation>
+      </xs:element>
+      <xs:element name="status" type="tns:OrderStatus"/>
+      <xs:element name="lines" type="xs:string" maxOccurs="unbounded"/>
+    </xs:sequence>
+    <xs:attribute name="currency" type="xs:string">
+      <xs:annotation><xs:documentation>ISO 4217 code</xs:documentation></xs:annotation>
+    </xs:attribute>
+  </xs:complexType>
+</xs:schema>`
+
+// TestMarkdownDocs tests that the reference documents messages, fields and enums
+// with their XSD documentation
+func TestMarkdownDocs(t *testing.T) {
+	schema, err := parser.New().Parse(strings.NewReader(markdownDocsXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	content := generator.GenerateMarkdown(protoFile)
+
+	expected := []string{
+		"# orders\n",
+		"## Order\n\nA customer order\n\n| Name | Type | Label | Description |\n",
+		"| id | `string` | required | Unique order id \\| reference |\n",
+		"| status | `OrderStatus` | required |  |\n",
+		"| lines | `string` | repeated |  |\n",
+		"| currency | `string` | optional | ISO 4217 code |\n",
+		"## OrderStatus\n\nProcessing state of an order\n\n| Name | Number | Description |\n",
+		"| ORDER_STATUS_UNSPECIFIED | 0 |  |\n",
+		"| ORDER_STATUS_OPEN | 1 | Not yet shipped |\n",
+		"| ORDER_STATUS_CLOSED | 2 |  |\n",
+	}
+	for _, part := range expected {
+		if !strings.Contains(content, part) {
+			t.Errorf("Expected %q in Markdown:\n%s", part, content)
+		}
+	}
+}
+
+// TestMarkdownDocsOption tests that converting the sample XSD writes a reference
+// with an H2 heading per message, and leaves the proto without the documentation
+func TestMarkdownDocsOption(t *testing.T) {
+	setupTest(t)
+	dir := t.TempDir()
+	markdownPath := filepath.Join(dir, "simple.md")
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = filepath.Join(dir, "simple.proto")
+	opts.MarkdownDocsPath = markdownPath
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	data, err := os.ReadFile(markdownPath)
+	if err != nil {
+		t.Fatalf("Failed to read Markdown docs: %v", err)
+	}
+	for _, heading := range []string{"## Address\n", "## Person\n"} {
+		if !strings.Contains(string(data), heading) {
+			t.Errorf("Expected heading %q in Markdown:\n%s", heading, data)
+		}
+	}
+
+	content := generateProto(t, markdownDocsXSD, nil)
+	if strings.Contains(content, "A customer order") || strings.Contains(content, "ISO 4217") {
+		t.Errorf("Expected no type or field documentation in the proto:\n%s", content)
+	}
+}
@@ -0,0 +1,156 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+func addressMessage(fields ...string) model.ProtoMessage {
+	message := model.ProtoMessage{Name: "Address"}
+	for i, name := range fields {
+		message.Fields = append(message.Fields, model.ProtoField{Name: name, Type: "string", Number: i + 1})
+	}
+	return message
+}
+
+// TestMergeProtoFiles tests that shared types and imports are kept once
+func TestMergeProtoFiles(t *testing.T) {
+	orders := &model.ProtoFile{
+		Syntax:          "proto3",
+		Package:         "orders",
+		Imports:         []string{"google/protobuf/timestamp.proto"},
+		Options:         []model.ProtoOption{model.StringOption("go_package", "example.com/api")},
+		Messages:        []model.ProtoMessage{{Name: "Order"}, addressMessage("street", "city")},
+		RawDeclarations: []string{"service Orders {}"},
+	}
+	customers := &model.ProtoFile{
+		Syntax:          "proto3",
+		Package:         "customers",
+		Imports:         []string{"google/protobuf/duration.proto", "google/protobuf/timestamp.proto"},
+		Options:         []model.ProtoOption{model.StringOption("go_package", "example.com/api")},
+		Messages:        []model.ProtoMessage{{Name: "Customer"}, addressMessage("street", "city")},
+		Enums:           []model.ProtoEnum{{Name: "Tier", Values: []model.ProtoEnumValue{{Name: "TIER_UNSPECIFIED"}}}},
+		RawDeclarations: []string{"service Customers {}", "service Orders {}"},
+	}
+
+	merged, err := generator.MergeProtoFiles([]*model.ProtoFile{orders, customers})
+	if err != nil {
+		t.Fatalf("MergeProtoFiles failed: %v", err)
+	}
+
+	if merged.Package != "orders" {
+		t.Errorf("Expected package from the first file, got %s", merged.Package)
+	}
+	var names []string
+	for _, message := range merged.Messages {
+		names = append(names, message.Name)
+	}
+	if strings.Join(names, ",") != "Order,Address,Customer" {
+		t.Errorf("Unexpected messages: %v", names)
+	}
+	if len(merged.Enums) != 1 {
+		t.Errorf("Expected 1 enum, got %d", len(merged.Enums))
+	}
+	if strings.Join(merged.Imports, ",") != "google/protobuf/timestamp.proto,google/protobuf/duration.proto" {
+		t.Errorf("Unexpected imports: %v", merged.Imports)
+	}
+	if strings.Join(merged.RawDeclarations, ",") != "service Orders {},service Customers {}" {
+		t.Errorf("Unexpected raw declarations: %v", merged.RawDeclarations)
+	}
+	if option, _ := merged.Option("go_package"); option.Value != "example.com/api" {
+		t.Errorf("Expected go_package option to be kept, got %v", merged.Options)
+	}
+}
+
+// TestMergeProtoFilesConflicts tests that structurally different definitions are rejected
+func TestMergeProtoFilesConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []*model.ProtoFile
+		expected string
+	}{
+		{
+			name: "message",
+			files: []*model.ProtoFile{
+				{Messages: []model.ProtoMessage{addressMessage("street")}},
+				{Messages: []model.ProtoMessage{addressMessage("street", "zip")}},
+			},
+			expected: "conflicting definitions of message Address",
+		},
+		{
+			name: "message and enum",
+			files: []*model.ProtoFile{
+				{Messages: []model.ProtoMessage{addressMessage("street")}},
+				{Enums: []model.ProtoEnum{{Name: "Address"}}},
+			},
+			expected: "Address is defined both as a message and as an enum",
+		},
+		{
+			name: "option",
+			files: []*model.ProtoFile{
+				{Options: []model.ProtoOption{model.StringOption("go_package", "a")}},
+				{Options: []model.ProtoOption{model.StringOption("go_package", "b")}},
+			},
+			expected: "conflicting values for option go_package",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generator.MergeProtoFiles(tt.files)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expected, err)
+			}
+		})
+	}
+
+	if _, err := generator.MergeProtoFiles(nil); err == nil {
+		t.Errorf("Expected an error when merging no files")
+	}
+}
+
+// TestE2EMergeConflictingNamespaces tests that --merge rejects a type defined differently in two namespaces
+func TestE2EMergeConflictingNamespaces(t *testing.T) {
+	cli := buildCLI(t)
+
+	dir := t.TempDir()
+	schemas := map[string]string{
+		"a.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/a">
+    <xs:complexType name="Address">
+        <xs:sequence>
+            <xs:element name="street" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`,
+		"b.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/b">
+    <xs:complexType name="Address">
+        <xs:sequence>
+            <xs:element name="line1" type="xs:string"/>
+            <xs:element name="line2" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`,
+	}
+	for name, content := range schemas {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	cmd := exec.Command(cli, "--merge", "-o", filepath.Join(dir, "api.proto"), filepath.Join(dir, "a.xsd"), filepath.Join(dir, "b.xsd"))
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected the merge to fail, output: %s", output)
+	}
+	if !strings.Contains(string(output), "conflicting definitions of message Address") {
+		t.Errorf("Unexpected error output: %s", output)
+	}
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// writeMergeSchemas writes two schemas that both import a common schema
+func writeMergeSchemas(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"common.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/common">
+    <xs:complexType name="Address">
+        <xs:sequence>
+            <xs:element name="street" type="xs:string"/>
+            <xs:element name="city" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`,
+		"orders.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:cmn="http://example.com/common"
+           targetNamespace="http://example.com/orders">
+    <xs:import namespace="http://example.com/common" schemaLocation="common.xsd"/>
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string"/>
+            <xs:element name="shipTo" type="cmn:Address"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`,
+		"customers.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:cmn="http://example.com/common"
+           targetNamespace="http://example.com/customers">
+    <xs:import namespace="http://example.com/common" schemaLocation="common.xsd"/>
+    <xs:complexType name="Customer">
+        <xs:sequence>
+            <xs:element name="name" type="xs:string"/>
+            <xs:element name="home" type="cmn:Address"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	return filepath.Join(dir, "orders.xsd"), filepath.Join(dir, "customers.xsd")
+}
+
+// TestConvertMultipleSharedType tests that a type shared by two schemas is emitted once
+func TestConvertMultipleSharedType(t *testing.T) {
+	ordersPath, customersPath := writeMergeSchemas(t)
+
+	p := parser.New()
+	var schemas []*model.Schema
+	for _, path := range []string{ordersPath, customersPath} {
+		schema, err := p.ParseFileWithImports(path)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", path, err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	protoFile, err := converter.New().ConvertMultiple(schemas)
+	if err != nil {
+		t.Fatalf("ConvertMultiple failed: %v", err)
+	}
+
+	gen := generator.New(generator.WithHeader(false, ""))
+	content, err := gen.Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+
+	if count := strings.Count(content, "message Address {"); count != 1 {
+		t.Errorf("Address should appear exactly once, found %d times\nActual content:\n%s", count, content)
+	}
+	if strings.Contains(content, "Address2") {
+		t.Errorf("Shared Address type should not be renamed\nActual content:\n%s", content)
+	}
+
+	expectedParts := []string{
+		"package orders;",
+		"message Order {",
+		"Address ship_to = 2;",
+		"message Customer {",
+		"Address home = 2;",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(content, part) {
+			t.Errorf("Generated proto should contain: %s\nActual content:\n%s", part, content)
+		}
+	}
+}
+
+// TestE2EMergeFlag tests that --merge combines several inputs into a single output file
+func TestE2EMergeFlag(t *testing.T) {
+	cli := buildCLI(t)
+	ordersPath, customersPath := writeMergeSchemas(t)
+
+	outputFile := filepath.Join(t.TempDir(), "api.proto")
+	cmd := exec.Command(cli, "--merge", "-o", outputFile, ordersPath, customersPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI merge failed: %v\nOutput: %s", err, output)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	protoContent := string(content)
+
+	for _, part := range []string{"message Order {", "message Customer {", "message Address {"} {
+		if strings.Count(protoContent, part) != 1 {
+			t.Errorf("Merged proto should contain %s exactly once\nActual content:\n%s", part, protoContent)
+		}
+	}
+}
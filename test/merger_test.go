@@ -0,0 +1,271 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/merger"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// TestMergerMergeProtoFiles tests that the messages of both files are kept and imports deduplicated
+func TestMergerMergeProtoFiles(t *testing.T) {
+	a := &model.ProtoFile{
+		Syntax:   "proto3",
+		Package:  "orders",
+		Imports:  []string{"google/protobuf/timestamp.proto", "common.proto"},
+		Options:  []model.ProtoOption{model.StringOption("go_package", "example.com/orders"), model.StringOption("java_package", "com.example")},
+		Messages: []model.ProtoMessage{{Name: "Order"}, {Name: "OrderLine"}, {Name: "Address"}},
+	}
+	b := &model.ProtoFile{
+		Syntax:   "proto3",
+		Package:  "extras",
+		Imports:  []string{"common.proto", "google/protobuf/duration.proto"},
+		Options:  []model.ProtoOption{model.StringOption("go_package", "example.com/extras")},
+		Messages: []model.ProtoMessage{{Name: "GetOrderRequest"}, {Name: "GetOrderResponse"}, {Name: "ListOrdersRequest"}},
+	}
+
+	merged, err := merger.MergeProtoFiles(a, b)
+	if err != nil {
+		t.Fatalf("MergeProtoFiles failed: %v", err)
+	}
+
+	if len(merged.Messages) != 6 {
+		t.Errorf("Expected 6 messages, got %d", len(merged.Messages))
+	}
+	seen := make(map[string]bool)
+	for _, message := range merged.Messages {
+		if seen[message.Name] {
+			t.Errorf("Message %s is duplicated", message.Name)
+		}
+		seen[message.Name] = true
+	}
+
+	expectedImports := []string{"common.proto", "google/protobuf/duration.proto", "google/protobuf/timestamp.proto"}
+	if !reflect.DeepEqual(merged.Imports, expectedImports) {
+		t.Errorf("Expected imports %v, got %v", expectedImports, merged.Imports)
+	}
+	if merged.Package != "orders" || merged.Syntax != "proto3" {
+		t.Errorf("Expected the syntax and package of the first file, got %s %s", merged.Syntax, merged.Package)
+	}
+	if option, _ := merged.Option("go_package"); option.Value != "example.com/extras" {
+		t.Errorf("Expected the option of the second file to win, got %s", option.Value)
+	}
+	if option, _ := merged.Option("java_package"); option.Value != "com.example" {
+		t.Errorf("Expected options of the first file to be kept, got %v", merged.Options)
+	}
+}
+
+// TestMergerDuplicateName tests that a name defined in both files is an error
+func TestMergerDuplicateName(t *testing.T) {
+	a := &model.ProtoFile{Syntax: "proto3", Messages: []model.ProtoMessage{{Name: "Status"}}}
+	b := &model.ProtoFile{Syntax: "proto3", Enums: []model.ProtoEnum{{Name: "Status"}}}
+
+	_, err := merger.MergeProtoFiles(a, b)
+	if err == nil || !strings.Contains(err.Error(), "duplicate name Status") {
+		t.Errorf("Expected a duplicate name error, got %v", err)
+	}
+}
+
+// TestParseProtoFile tests reading the declarations of a hand-written proto file
+func TestParseProtoFile(t *testing.T) {
+	content := `// Hand-written messages
+syntax = "proto3";
+
+package orders.v1;
+
+import "google/protobuf/timestamp.proto";
+
+option go_package = "example.com/orders";
+
+/* Request for one order */
+message GetOrderRequest {
+  option deprecated = true;
+  reserved 3, 5 to 6;
+  reserved "legacy";
+
+  string order_id = 1 [deprecated = true, (validate.rules).string.min_len = 1];
+  optional google.protobuf.Timestamp as_of = 2;
+  repeated string fields = 4;
+  map<string, int32> counts = 7;
+  oneof lookup {
+    string code = 8;
+    int64 number = 9;
+  }
+
+  message Filter {
+    string name = 1;
+  }
+}
+
+enum Kind {
+  option allow_alias = true;
+  KIND_UNSPECIFIED = 0;
+  KIND_A = 1;
+  KIND_ALIAS = 1;
+}
+`
+	file, err := merger.ParseProtoFile(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseProtoFile failed: %v", err)
+	}
+
+	if goPackage, _ := file.Option("go_package"); file.Package != "orders.v1" || goPackage != model.StringOption("go_package", "example.com/orders") {
+		t.Errorf("Unexpected package or options: %s %v", file.Package, file.Options)
+	}
+	if !reflect.DeepEqual(file.Imports, []string{"google/protobuf/timestamp.proto"}) {
+		t.Errorf("Unexpected imports: %v", file.Imports)
+	}
+	if len(file.Messages) != 1 || len(file.Enums) != 1 {
+		t.Fatalf("Expected 1 message and 1 enum, got %d and %d", len(file.Messages), len(file.Enums))
+	}
+
+	message := file.Messages[0]
+	if !message.IsDeprecated {
+		t.Errorf("Expected the message to be deprecated")
+	}
+	if !reflect.DeepEqual(message.ReservedNumbers, []int{3, 5, 6}) || !reflect.DeepEqual(message.ReservedNames, []string{"legacy"}) {
+		t.Errorf("Unexpected reserved: %v %v", message.ReservedNumbers, message.ReservedNames)
+	}
+	if len(message.Messages) != 1 || message.Messages[0].Name != "Filter" {
+		t.Errorf("Expected nested message Filter, got %v", message.Messages)
+	}
+
+	if len(message.Fields) != 6 {
+		t.Fatalf("Expected 6 fields, got %d", len(message.Fields))
+	}
+	orderID := message.Fields[0]
+	if orderID.Label != model.FieldLabelRequired || !orderID.IsDeprecated ||
+		!reflect.DeepEqual(orderID.RawOptions, []string{"(validate.rules).string.min_len = 1"}) {
+		t.Errorf("Unexpected order_id field: %+v", orderID)
+	}
+	if asOf := message.Fields[1]; asOf.Label != model.FieldLabelOptional || asOf.Type != "google.protobuf.Timestamp" {
+		t.Errorf("Unexpected as_of field: %+v", asOf)
+	}
+	if counts := message.Fields[3]; !counts.IsMap || counts.KeyType != "string" || counts.Type != "int32" || counts.Number != 7 {
+		t.Errorf("Unexpected counts field: %+v", counts)
+	}
+	if code := message.Fields[4]; code.Oneof != "lookup" || code.Number != 8 {
+		t.Errorf("Unexpected code field: %+v", code)
+	}
+
+	enum := file.Enums[0]
+	if !enum.AllowAlias || len(enum.Values) != 3 || enum.Values[2].Name != "KIND_ALIAS" || enum.Values[2].Number != 1 {
+		t.Errorf("Unexpected enum: %+v", enum)
+	}
+}
+
+// TestParseProtoFileKeepsServices tests that services and extensions are kept as written
+func TestParseProtoFileKeepsServices(t *testing.T) {
+	input := `syntax = "proto3";
+import "google/protobuf/descriptor.proto";
+
+service Orders {
+  // Looks up an order
+  rpc GetOrder(GetOrderRequest) returns (Order) {
+    option (google.api.http) = { get: "/v1/orders/{id}" };
+  }
+}
+
+extend google.protobuf.FieldOptions {
+  string label = 50000;
+}
+
+message Order {}
+`
+	file, err := merger.ParseProtoFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseProtoFile failed: %v", err)
+	}
+
+	expected := []string{
+		"service Orders {\n  // Looks up an order\n  rpc GetOrder(GetOrderRequest) returns (Order) {\n    option (google.api.http) = { get: \"/v1/orders/{id}\" };\n  }\n}",
+		"extend google.protobuf.FieldOptions {\n  string label = 50000;\n}",
+	}
+	if len(file.RawDeclarations) != len(expected) {
+		t.Fatalf("Expected %d raw declarations, got %q", len(expected), file.RawDeclarations)
+	}
+	for i, declaration := range expected {
+		if file.RawDeclarations[i] != declaration {
+			t.Errorf("Raw declaration %d = %q, want %q", i, file.RawDeclarations[i], declaration)
+		}
+	}
+	if len(file.Messages) != 1 || file.Messages[0].Name != "Order" {
+		t.Errorf("Expected the Order message after the service, got %+v", file.Messages)
+	}
+
+	if _, err := merger.ParseProtoFile(strings.NewReader(`service Orders {`)); err == nil {
+		t.Error("Expected an error for an unterminated service")
+	}
+}
+
+// TestConvertMergeWith tests that an existing proto file is merged into the converted output
+func TestConvertMergeWith(t *testing.T) {
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "order.xsd")
+	extrasPath := filepath.Join(dir, "extras.proto")
+	outputPath := filepath.Join(dir, "order.proto")
+
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/orders">
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string"/>
+      <xs:element name="createdAt" type="xs:dateTime"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+	extras := `syntax = "proto3";
+package extras;
+import "google/protobuf/timestamp.proto";
+
+message GetOrderRequest {
+  string id = 1;
+}
+
+service OrderService {
+  rpc GetOrder(GetOrderRequest) returns (Order);
+}
+`
+	if err := os.WriteFile(xsdPath, []byte(xsd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(extrasPath, []byte(extras), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = outputPath
+	opts.MergeWithPath = extrasPath
+	if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(output)
+
+	for _, expected := range []string{"message Order {", "message GetOrderRequest {", "package orders;"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in output:\n%s", expected, content)
+		}
+	}
+	if count := strings.Count(content, `import "google/protobuf/timestamp.proto";`); count != 1 {
+		t.Errorf("Expected the timestamp import once, got %d:\n%s", count, content)
+	}
+	if !strings.HasSuffix(content, "}\n\nservice OrderService {\n  rpc GetOrder(GetOrderRequest) returns (Order);\n}\n\n") {
+		t.Errorf("Expected the service after the messages:\n%s", content)
+	}
+
+	// Merging again with the output itself redefines every message
+	opts.MergeWithPath = outputPath
+	if err := xsd2proto.ConvertFile(xsdPath, opts); err == nil || !strings.Contains(err.Error(), "duplicate name Order") {
+		t.Errorf("Expected a duplicate name error, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/merger"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+const messageOptionsXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/shapes">
+  <xs:complexType name="Shape" abstract="true">
+    <xs:sequence>
+      <xs:element name="color" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="Order">
+    <xs:choice>
+      <xs:element name="code" type="xs:string"/>
+      <xs:sequence>
+        <xs:element name="line" type="xs:string"/>
+      </xs:sequence>
+    </xs:choice>
+  </xs:complexType>
+</xs:schema>`
+
+// TestAddMessageOption tests that injected options are emitted inside the message braces
+func TestAddMessageOption(t *testing.T) {
+	conv := converter.New()
+	conv.AddMessageOption("Order", "(my.entity)", "orders", true)
+	conv.AddMessageOption("Order", "deprecated", "true", false)
+	conv.AddMessageOption("Order.Order_1Choice", "(my.internal)", "true", false)
+	content := generateProto(t, messageOptionsXSD, conv)
+
+	expected := []string{
+		"message Order {\n  option (my.entity) = \"orders\";\n  option deprecated = true;\n  message Order_1Choice {\n",
+		"  message Order_1Choice {\n    option (my.internal) = true;\n    string line = 1;\n  }\n",
+	}
+	for _, block := range expected {
+		if !strings.Contains(content, block) {
+			t.Errorf("Expected %q in output:\n%s", block, content)
+		}
+	}
+	if strings.Contains(content, "xsd_abstract") {
+		t.Errorf("Expected no abstract option without an import:\n%s", content)
+	}
+
+	// The options are read back from the output
+	file, err := merger.ParseProtoFile(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseProtoFile failed: %v", err)
+	}
+	for _, message := range file.Messages {
+		if message.Name != "Order" {
+			continue
+		}
+		if !message.IsDeprecated || len(message.Options) != 1 || message.Options[0] != model.StringOption("(my.entity)", "orders") {
+			t.Errorf("Unexpected options of Order: %v %+v", message.IsDeprecated, message.Options)
+		}
+	}
+}
+
+// TestDeprecatedMessageOptionOnce tests that a deprecated option added to a message
+// already deprecated by its documentation is written once
+func TestDeprecatedMessageOptionOnce(t *testing.T) {
+	xsd := strings.Replace(messageOptionsXSD, `<xs:complexType name="Order">`,
+		`<xs:complexType name="Order"><xs:annotation><xs:documentation>Deprecated, use Shape</xs:documentation></xs:annotation>`, 1)
+	conv := converter.New()
+	conv.AddMessageOption("Order", "deprecated", "true", false)
+	content := generateProto(t, xsd, conv)
+
+	if count := strings.Count(content, "option deprecated = true;"); count != 1 {
+		t.Errorf("Expected the deprecated option once, got %d:\n%s", count, content)
+	}
+}
+
+// TestAbstractMessageOption tests the xsd_abstract option of messages of abstract types
+func TestAbstractMessageOption(t *testing.T) {
+	conv := converter.New()
+	conv.SetAbstractOptionImport("xsd/options.proto")
+	content := generateProto(t, messageOptionsXSD, conv)
+
+	for _, expected := range []string{
+		`import "xsd/options.proto";`,
+		"message Shape {\n  option (xsd_abstract) = true;\n  string color = 1;\n}",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in output:\n%s", expected, content)
+		}
+	}
+	if strings.Count(content, "xsd_abstract") != 1 {
+		t.Errorf("Expected only Shape to be marked abstract:\n%s", content)
+	}
+}
+
+// TestDescriptorMessageOptions tests that a deprecated option marks the descriptor
+func TestDescriptorMessageOptions(t *testing.T) {
+	protoFile := &model.ProtoFile{
+		Syntax: "proto3",
+		Messages: []model.ProtoMessage{{
+			Name:    "Order",
+			Options: []model.ProtoOption{model.IdentOption("deprecated", "true"), model.IdentOption("(my.entity)", "true")},
+		}},
+	}
+	descriptor, err := generator.GenerateDescriptor(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to build descriptor: %v", err)
+	}
+	if !descriptor.GetMessageType()[0].GetOptions().GetDeprecated() {
+		t.Errorf("Expected a deprecated message, got %v", descriptor.GetMessageType()[0])
+	}
+}
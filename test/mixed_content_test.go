@@ -0,0 +1,75 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const mixedContentXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/docs">
+  <xs:complexType name="Paragraph" mixed="true">
+    <xs:sequence>
+      <xs:element name="emphasis" type="xs:string" minOccurs="0" maxOccurs="unbounded"/>
+      <xs:element name="link" type="xs:anyURI" minOccurs="0"/>
+    </xs:sequence>
+    <xs:attribute name="lang" type="xs:language"/>
+  </xs:complexType>
+  <xs:complexType name="Plain">
+    <xs:sequence>
+      <xs:element name="text" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// TestMixedContent tests that a mixed complex type gets a content field before its sequence fields
+func TestMixedContent(t *testing.T) {
+	content := generateProto(t, mixedContentXSD, nil)
+
+	expected := "message Paragraph {\n" +
+		"  string content = 1; // mixed content\n" +
+		"  repeated string emphasis = 2;\n" +
+		"  optional string link = 3;\n" +
+		"  optional string lang = 4;\n" +
+		"}"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Expected:\n%s\nActual content:\n%s", expected, content)
+	}
+	if strings.Contains(content, "message Plain {\n  string content") {
+		t.Errorf("Types without mixed content should not get a content field\nActual content:\n%s", content)
+	}
+}
+
+// TestMixedContentAsBytes tests the bytes content field
+func TestMixedContentAsBytes(t *testing.T) {
+	conv := converter.New()
+	conv.SetMixedAsBytes(true)
+	content := generateProto(t, mixedContentXSD, conv)
+
+	if !strings.Contains(content, "  bytes content = 1; // mixed content\n") {
+		t.Errorf("Expected a bytes content field\nActual content:\n%s", content)
+	}
+}
+
+// TestMixedAsBytesFlag tests the --mixed-as-bytes CLI flag
+func TestMixedAsBytesFlag(t *testing.T) {
+	cli := buildCLI(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "docs.xsd")
+	if err := os.WriteFile(xsdPath, []byte(mixedContentXSD), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command(cli, "--stdout", "--mixed-as-bytes", xsdPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "bytes content = 1;") {
+		t.Errorf("Expected a bytes content field\nActual output:\n%s", output)
+	}
+}
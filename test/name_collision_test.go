@@ -89,8 +89,7 @@ func TestNameCollisionHandling(t *testing.T) {
 	}
 
 	// Generate proto content
-	gen := generator.New()
-	gen.SetHeaderOptions(false, "")
+	gen := generator.New(generator.WithHeader(false, ""))
 	content, err := gen.Generate(protoFile)
 	if err != nil {
 		t.Fatalf("Failed to generate proto: %v", err)
@@ -191,8 +190,7 @@ func TestMessageEnumNameCollision(t *testing.T) {
 	}
 
 	// Generate proto content
-	gen := generator.New()
-	gen.SetHeaderOptions(false, "")
+	gen := generator.New(generator.WithHeader(false, ""))
 	content, err := gen.Generate(protoFile)
 	if err != nil {
 		t.Fatalf("Failed to generate proto: %v", err)
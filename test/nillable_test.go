@@ -0,0 +1,63 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const nillableTestXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Address">
+        <xs:sequence>
+            <xs:element name="city" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+    <xs:complexType name="Person">
+        <xs:sequence>
+            <xs:element name="name" type="xs:string"/>
+            <xs:element name="middleName" type="xs:string" nillable="true"/>
+            <xs:element name="age" type="xs:int" nillable="true"/>
+            <xs:element name="active" type="xs:boolean" nillable="true"/>
+            <xs:element name="score" type="xs:double" nillable="true"/>
+            <xs:element name="address" type="Address" nillable="true"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+// TestNillableElementsUseWrappers tests that nillable scalar elements map to wrapper types
+func TestNillableElementsUseWrappers(t *testing.T) {
+	result := generateProto(t, nillableTestXSD, converter.New())
+
+	expected := []string{
+		`import "google/protobuf/wrappers.proto";`,
+		"string name = 1;",
+		"google.protobuf.StringValue middle_name = 2;",
+		"google.protobuf.Int32Value age = 3;",
+		"google.protobuf.BoolValue active = 4;",
+		"google.protobuf.DoubleValue score = 5;",
+		"Address address = 6;",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+}
+
+// TestNillableElementsWithoutWrappers tests that wrappers can be turned off
+func TestNillableElementsWithoutWrappers(t *testing.T) {
+	conv := converter.New()
+	conv.SetUseWrappers(false)
+	result := generateProto(t, nillableTestXSD, conv)
+
+	if strings.Contains(result, "google.protobuf") {
+		t.Errorf("Expected no wrapper types or imports\nGot:\n%s", result)
+	}
+	for _, want := range []string{"string middle_name = 2;", "int32 age = 3;"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+}
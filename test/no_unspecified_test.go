@@ -0,0 +1,89 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const noUnspecifiedXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/status">
+
+    <xs:simpleType name="Status">
+        <xs:restriction base="xs:QName">
+            <xs:enumeration value="active"/>
+            <xs:enumeration value="closed"/>
+        </xs:restriction>
+    </xs:simpleType>
+
+</xs:schema>`
+
+// TestNoUnspecified tests that enumeration values are numbered from 0 without UNSPECIFIED
+func TestNoUnspecified(t *testing.T) {
+	conv := converter.New()
+	conv.SetNoUnspecified(true)
+	content := generateProto(t, noUnspecifiedXSD, conv)
+
+	expected := `enum Status {
+  STATUS_ACTIVE = 0;
+  STATUS_CLOSED = 1;
+}`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", expected, content)
+	}
+	if strings.Contains(content, "UNSPECIFIED") {
+		t.Errorf("Expected no UNSPECIFIED value\nActual content:\n%s", content)
+	}
+}
+
+// TestNoUnspecifiedDisabled tests that enums keep the UNSPECIFIED value by default
+func TestNoUnspecifiedDisabled(t *testing.T) {
+	content := generateProto(t, noUnspecifiedXSD, nil)
+
+	if !strings.Contains(content, "STATUS_UNSPECIFIED = 0;\n  STATUS_ACTIVE = 1;") {
+		t.Errorf("Expected UNSPECIFIED as the first value by default\nActual content:\n%s", content)
+	}
+}
+
+// TestNoUnspecifiedFlag tests the --no-unspecified flag end to end
+func TestNoUnspecifiedFlag(t *testing.T) {
+	cli := buildCLI(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "status.xsd")
+	if err := os.WriteFile(xsdPath, []byte(noUnspecifiedXSD), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	output, err := exec.Command(cli, "--no-unspecified", "--stdout", xsdPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(string(output), "STATUS_ACTIVE = 0;") || strings.Contains(string(output), "UNSPECIFIED") {
+		t.Errorf("Expected STATUS_ACTIVE = 0 and no UNSPECIFIED value\nActual output:\n%s", output)
+	}
+}
+
+// TestWithNoUnspecified tests the library option
+func TestWithNoUnspecified(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "status.proto")
+	opts := xsd2proto.NewConvertOptions(xsd2proto.WithNoUnspecified())
+	opts.OutputPath = outputFile
+
+	if err := xsd2proto.ConvertReader(strings.NewReader(noUnspecifiedXSD), opts); err != nil {
+		t.Fatalf("ConvertReader failed: %v", err)
+	}
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(content), "STATUS_ACTIVE = 0;") {
+		t.Errorf("Expected STATUS_ACTIVE = 0\nActual content:\n%s", content)
+	}
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const nestedInlineXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/order">
+
+    <xs:complexType name="customerType">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:element name="Order">
+        <xs:complexType>
+            <xs:sequence>
+                <xs:element name="customer">
+                    <xs:complexType>
+                        <xs:sequence>
+                            <xs:element name="name" type="xs:string"/>
+                            <xs:element name="address">
+                                <xs:complexType>
+                                    <xs:sequence>
+                                        <xs:element name="city" type="xs:string"/>
+                                    </xs:sequence>
+                                </xs:complexType>
+                            </xs:element>
+                        </xs:sequence>
+                    </xs:complexType>
+                </xs:element>
+                <xs:element name="priority">
+                    <xs:simpleType>
+                        <xs:restriction base="xs:int">
+                            <xs:minInclusive value="1"/>
+                        </xs:restriction>
+                    </xs:simpleType>
+                </xs:element>
+            </xs:sequence>
+        </xs:complexType>
+    </xs:element>
+
+</xs:schema>`
+
+// TestNormalizeSchema tests that two levels of nested inline types are promoted to named types
+func TestNormalizeSchema(t *testing.T) {
+	p := parser.New()
+	schema, err := p.ParseString(nestedInlineXSD)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	p.NormalizeSchema(schema)
+
+	var names []string
+	for _, complexType := range schema.ComplexTypes {
+		names = append(names, complexType.Name)
+	}
+	// customerType is taken, so the promoted type gets a number
+	if got := strings.Join(names, ","); got != "customerType,customerType2,addressType" {
+		t.Errorf("Expected promoted complex types customerType2 and addressType, got %s", got)
+	}
+	if len(schema.SimpleTypes) != 1 || schema.SimpleTypes[0].Name != "priorityType" {
+		t.Errorf("Expected promoted simple type priorityType, got %+v", schema.SimpleTypes)
+	}
+
+	order := schema.Elements[0]
+	if order.ComplexType == nil {
+		t.Fatal("Expected the top-level element to keep its inline type")
+	}
+	customer := order.ComplexType.Sequence.Children[0].Element
+	if customer.ComplexType != nil || customer.Type != "customerType2" {
+		t.Errorf("Expected customer to refer to customerType2, got type %q", customer.Type)
+	}
+	address := schema.ComplexTypes[1].Sequence.Children[1].Element
+	if address.ComplexType != nil || address.Type != "addressType" {
+		t.Errorf("Expected address to refer to addressType, got type %q", address.Type)
+	}
+	priority := order.ComplexType.Sequence.Children[1].Element
+	if priority.SimpleType != nil || priority.Type != "priorityType" {
+		t.Errorf("Expected priority to refer to priorityType, got type %q", priority.Type)
+	}
+}
+
+// TestNormalizedSchemaConversion tests that promoted types convert to valid messages
+func TestNormalizedSchemaConversion(t *testing.T) {
+	p := parser.New()
+	schema, err := p.ParseString(nestedInlineXSD)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	protoFile, err := converter.New().Convert(p.NormalizeSchema(schema))
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	content, err := generator.New(generator.WithHeader(false, "")).Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+
+	expectedParts := []string{
+		"message CustomerType2 {\n  string name = 1;\n  AddressType address = 2;\n}",
+		"message AddressType {\n  string city = 1;\n}",
+		"  CustomerType2 customer = 1;\n  int32 priority = 2;\n",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(content, part) {
+			t.Errorf("Generated proto should contain:\n%s\nActual content:\n%s", part, content)
+		}
+	}
+}
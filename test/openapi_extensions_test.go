@@ -0,0 +1,88 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+// TestEmitOpenAPIExtensions tests that the OpenAPI companion carries the XSD origin of each type
+func TestEmitOpenAPIExtensions(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="http://example.com/people" targetNamespace="http://example.com/people">
+  <xs:simpleType name="StatusCode">
+    <xs:restriction base="xs:string">
+      <xs:maxLength value="8"/>
+      <xs:enumeration value="ACTIVE">
+        <xs:annotation><xs:documentation>The person is active</xs:documentation></xs:annotation>
+      </xs:enumeration>
+      <xs:enumeration value="RETIRED"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:complexType name="Person">
+    <xs:annotation><xs:documentation>A registered person</xs:documentation></xs:annotation>
+    <xs:sequence>
+      <xs:element name="name" type="xs:string"/>
+      <xs:element name="status" type="tns:StatusCode"/>
+    </xs:sequence>
+    <xs:attribute name="id" type="xs:int"/>
+  </xs:complexType>
+</xs:schema>`
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "person.xsd")
+	if err := os.WriteFile(xsdPath, []byte(xsdContent), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = filepath.Join(dir, "person.proto")
+	opts.EmitOpenAPI = true
+	if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "person.openapi.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read OpenAPI companion: %v", err)
+	}
+
+	expected := []string{
+		"openapi: 3.0.3",
+		`x-xsd-type: "Person"`,
+		`x-xsd-namespace: "http://example.com/people"`,
+		`description: "A registered person"`,
+		"x-xsd-restriction:",
+		`maxLength: 8`,
+		"x-xsd-enum-descriptions:",
+		`"ACTIVE": "The person is active"`,
+		"#/components/schemas/StatusCode",
+		"x-xsd-attribute: true",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(string(content), exp) {
+			t.Errorf("Expected %q in OpenAPI companion\nActual content:\n%s", exp, content)
+		}
+	}
+}
+
+// TestEmitOpenAPIExtensionsDisabled tests that no companion is written by default
+func TestEmitOpenAPIExtensionsDisabled(t *testing.T) {
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "person.xsd")
+	if err := os.WriteFile(xsdPath, []byte(fieldMapXSD("name")), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = filepath.Join(dir, "person.proto")
+	if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "person.openapi.yaml")); !os.IsNotExist(err) {
+		t.Errorf("Expected no OpenAPI companion without EmitOpenAPI, got %v", err)
+	}
+}
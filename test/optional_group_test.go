@@ -0,0 +1,62 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const optionalGroupXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/orders">
+  <xs:complexType name="Order">
+    <xs:sequence minOccurs="0">
+      <xs:element name="street" type="xs:string"/>
+      <xs:element name="city" type="xs:string"/>
+      <xs:element name="line" type="xs:string" maxOccurs="unbounded"/>
+    </xs:sequence>
+    <xs:attribute name="id" type="xs:string" use="required"/>
+  </xs:complexType>
+</xs:schema>`
+
+// TestOptionalSequenceGroup tests that the elements of an optional sequence move
+// into a group message referenced by one optional field
+func TestOptionalSequenceGroup(t *testing.T) {
+	content := generateProto(t, optionalGroupXSD, nil)
+
+	expected := []string{
+		"message Order {\n  optional OrderGroup order_group = 1;\n  string id = 2;\n}",
+		"message OrderGroup {\n  string street = 1;\n  string city = 2;\n  repeated string line = 3;\n}",
+	}
+	for _, part := range expected {
+		if !strings.Contains(content, part) {
+			t.Errorf("Expected %q in output:\n%s", part, content)
+		}
+	}
+}
+
+// TestInlineOptionalGroups tests that with inline optional groups the elements
+// stay flat in the parent, each optional
+func TestInlineOptionalGroups(t *testing.T) {
+	conv := converter.New()
+	conv.SetInlineOptionalGroups(true)
+	content := generateProto(t, optionalGroupXSD, conv)
+
+	expected := "message Order {\n  optional string street = 1;\n  optional string city = 2;\n  repeated string line = 3;\n  string id = 4;\n}"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Expected %q in output:\n%s", expected, content)
+	}
+	if strings.Contains(content, "OrderGroup") {
+		t.Errorf("Expected no group message:\n%s", content)
+	}
+}
+
+// TestRequiredSequenceNotGrouped tests that a sequence without minOccurs="0" keeps
+// its elements in the parent
+func TestRequiredSequenceNotGrouped(t *testing.T) {
+	content := generateProto(t, strings.Replace(optionalGroupXSD, `<xs:sequence minOccurs="0">`, `<xs:sequence>`, 1), nil)
+	if strings.Contains(content, "OrderGroup") || !strings.Contains(content, "  string street = 1;") {
+		t.Errorf("Expected the elements of a required sequence in the parent:\n%s", content)
+	}
+}
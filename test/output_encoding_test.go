@@ -0,0 +1,85 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+const outputEncodingTestXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:simpleType name="Drink">
+        <xs:restriction base="xs:string">
+            <xs:enumeration value="tea"/>
+            <xs:enumeration value="café"/>
+        </xs:restriction>
+    </xs:simpleType>
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="drink" type="Drink"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+func convertWithEncoding(t *testing.T, xsd, encoding string) ([]byte, error) {
+	t.Helper()
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "order.xsd")
+	if err := os.WriteFile(xsdPath, []byte(xsd), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	opts := xsd2proto.NewConvertOptions()
+	opts.OutputPath = filepath.Join(dir, "order.proto")
+	opts.OutputEncoding = encoding
+	if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(opts.OutputPath)
+}
+
+// TestOutputEncodingUTF8BOM tests that utf-8-bom prepends a byte order mark
+func TestOutputEncodingUTF8BOM(t *testing.T) {
+	content, err := convertWithEncoding(t, outputEncodingTestXSD, xsd2proto.OutputEncodingUTF8BOM)
+	if err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte("\xEF\xBB\xBF")) {
+		t.Errorf("Expected output to start with a BOM, got %q", content[:8])
+	}
+
+	plain, err := convertWithEncoding(t, outputEncodingTestXSD, xsd2proto.OutputEncodingUTF8)
+	if err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+	if !bytes.Equal(content[3:], plain) {
+		t.Errorf("BOM output should otherwise match the UTF-8 output")
+	}
+}
+
+// TestOutputEncodingASCII tests that ascii output rejects non-ASCII content
+func TestOutputEncodingASCII(t *testing.T) {
+	_, err := convertWithEncoding(t, outputEncodingTestXSD, xsd2proto.OutputEncodingASCII)
+	if err == nil {
+		t.Fatal("Expected an error for non-ASCII output")
+	}
+	if !strings.Contains(err.Error(), "output is not ASCII: 'é' at line") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	asciiXSD := strings.Replace(outputEncodingTestXSD, "café", "coffee", 1)
+	if _, err := convertWithEncoding(t, asciiXSD, xsd2proto.OutputEncodingASCII); err != nil {
+		t.Errorf("ASCII-only output should be accepted, got: %v", err)
+	}
+}
+
+// TestOutputEncodingUnknown tests that an unknown encoding is rejected
+func TestOutputEncodingUnknown(t *testing.T) {
+	if _, err := convertWithEncoding(t, outputEncodingTestXSD, "latin-1"); err == nil || !strings.Contains(err.Error(), `unknown output encoding "latin-1"`) {
+		t.Errorf("Expected an unknown encoding error, got: %v", err)
+	}
+}
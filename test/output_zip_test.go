@@ -0,0 +1,175 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/merger"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+const outputZipXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/orders"
+           targetNamespace="http://example.com/orders">
+  <xs:complexType name="Address">
+    <xs:sequence>
+      <xs:element name="street" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="UserAccount">
+    <xs:sequence>
+      <xs:element name="createdAt" type="xs:dateTime"/>
+      <xs:element name="address" type="tns:Address"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// readZip returns the entries of the zip archive at path by name
+func readZip(t *testing.T, path string) map[string]string {
+	t.Helper()
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("Failed to open zip archive: %v", err)
+	}
+	defer archive.Close()
+
+	entries := make(map[string]string)
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file.Name, err)
+		}
+		entries[file.Name] = string(content)
+	}
+	return entries
+}
+
+// parseEntry checks that a zip entry is a proto file the proto parser accepts
+func parseEntry(t *testing.T, name, content string) *model.ProtoFile {
+	t.Helper()
+	protoFile, err := merger.ParseProtoFile(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("%s is not valid proto: %v\n%s", name, err, content)
+	}
+	return protoFile
+}
+
+// TestOutputZipSplitFiles tests that split files are zipped in the directory of their package
+func TestOutputZipSplitFiles(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "orders.xsd")
+	if err := os.WriteFile(inputPath, []byte(outputZipXSD), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+	zipPath := filepath.Join(dir, "out", "protos.zip")
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.SplitFiles = true
+	opts.OutputZipPath = zipPath
+	if err := xsd2proto.ConvertFile(inputPath, opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	entries := readZip(t, zipPath)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 files in the archive, got %d: %v", len(entries), entries)
+	}
+	account := parseEntry(t, "user_account.proto", entries["orders/user_account.proto"])
+	if len(account.Messages) != 1 || account.Messages[0].Name != "UserAccount" {
+		t.Errorf("Expected orders/user_account.proto to define UserAccount, got %+v", account.Messages)
+	}
+	if !strings.Contains(entries["orders/user_account.proto"], `import "orders/address.proto";`) {
+		t.Errorf("Expected the import of address.proto by its archive path:\n%s", entries["orders/user_account.proto"])
+	}
+	address := parseEntry(t, "address.proto", entries["orders/address.proto"])
+	if len(address.Messages) != 1 || address.Messages[0].Name != "Address" {
+		t.Errorf("Expected orders/address.proto to define Address, got %+v", address.Messages)
+	}
+
+	if entries, _ := os.ReadDir(filepath.Join(dir, "out")); len(entries) != 1 {
+		t.Errorf("Expected only the archive to be written, got %d files", len(entries))
+	}
+}
+
+// TestOutputZipSingleFile tests that without splitting the archive holds one file
+// named after the input
+func TestOutputZipSingleFile(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "orders.xsd")
+	if err := os.WriteFile(inputPath, []byte(outputZipXSD), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+	zipPath := filepath.Join(dir, "orders.zip")
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputZipPath = zipPath
+	if err := xsd2proto.ConvertFile(inputPath, opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	entries := readZip(t, zipPath)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 file in the archive, got %d", len(entries))
+	}
+	protoFile := parseEntry(t, "orders.proto", entries["orders.proto"])
+	if len(protoFile.Messages) != 2 {
+		t.Errorf("Expected 2 messages in orders.proto, got %d", len(protoFile.Messages))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "orders.proto")); !os.IsNotExist(err) {
+		t.Errorf("Expected no proto file next to the input, got %v", err)
+	}
+}
+
+// TestOutputZipRejectsStream tests that a zip archive cannot be combined with stream output
+func TestOutputZipRejectsStream(t *testing.T) {
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputZipPath = filepath.Join(t.TempDir(), "out.zip")
+	opts.Output = &bytes.Buffer{}
+	err := xsd2proto.ConvertReader(strings.NewReader(outputZipXSD), opts)
+	if err == nil || !strings.Contains(err.Error(), "zip archive") {
+		t.Errorf("Expected a zip archive error, got %v", err)
+	}
+}
+
+// TestGenerateZip tests that GenerateZip writes one entry per proto file
+func TestGenerateZip(t *testing.T) {
+	files := map[string]*model.ProtoFile{
+		"b/second.proto": {Syntax: "proto3", Package: "b", Messages: []model.ProtoMessage{{Name: "Second"}}},
+		"a/first.proto":  {Syntax: "proto3", Package: "a", Messages: []model.ProtoMessage{{Name: "First"}}},
+	}
+
+	var buf bytes.Buffer
+	if err := generator.New(generator.WithHeader(false, "")).GenerateZip(files, &buf); err != nil {
+		t.Fatalf("GenerateZip failed: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read zip archive: %v", err)
+	}
+	if len(archive.File) != 2 || archive.File[0].Name != "a/first.proto" || archive.File[1].Name != "b/second.proto" {
+		t.Fatalf("Expected a/first.proto and b/second.proto in order, got %d entries", len(archive.File))
+	}
+	r, err := archive.File[0].Open()
+	if err != nil {
+		t.Fatalf("Failed to open a/first.proto: %v", err)
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read a/first.proto: %v", err)
+	}
+	parseEntry(t, "a/first.proto", string(content))
+}
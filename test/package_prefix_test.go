@@ -0,0 +1,69 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const packagePrefixXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/billing">
+  <xs:complexType name="Invoice">
+    <xs:sequence>
+      <xs:element name="total" type="xs:double"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// TestPackagePrefixCLI tests that --package-prefix prepends the prefix to the
+// package derived from the namespace
+func TestPackagePrefixCLI(t *testing.T) {
+	cli := buildCLI(t)
+	xsdPath := filepath.Join(t.TempDir(), "billing.xsd")
+	if err := os.WriteFile(xsdPath, []byte(packagePrefixXSD), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+
+	output, err := exec.Command(cli, "--stdout", "--package-prefix", "myorg", xsdPath).Output()
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if !strings.Contains(string(output), "package myorg.billing;") {
+		t.Errorf("Expected package myorg.billing:\n%s", output)
+	}
+}
+
+// TestPackagePrefix tests that a prefix the derived package already starts with
+// is not doubled and that an explicit package is used as it is
+func TestPackagePrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		prefix    string
+		pkg       string
+		expected  string
+	}{
+		{"prepended", "http://example.com/billing", "myorg", "", "myorg.billing"},
+		{"nested prefix", "http://example.com/billing", "myorg.api.", "", "myorg.api.billing"},
+		{"already prefixed", "urn:myorg.billing", "myorg", "", "myorg.billing"},
+		{"same as prefix", "urn:myorg", "myorg", "", "myorg"},
+		{"prefix of a word", "urn:myorganization.billing", "myorg", "", "myorg.myorganization.billing"},
+		{"explicit package", "http://example.com/billing", "myorg", "custom.billing", "custom.billing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv := converter.New()
+			conv.SetPackagePrefix(tt.prefix)
+			conv.SetPackageName(tt.pkg)
+			xsd := strings.Replace(packagePrefixXSD, "http://example.com/billing", tt.namespace, 1)
+			if content := generateProto(t, xsd, conv); !strings.Contains(content, "package "+tt.expected+";") {
+				t.Errorf("Expected package %s:\n%s", tt.expected, content)
+			}
+		})
+	}
+}
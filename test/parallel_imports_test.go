@@ -0,0 +1,61 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestParallelImportsMatchSequential tests that parallel import parsing builds the same
+// schema tree as sequential parsing, including imports shared by several schemas
+func TestParallelImportsMatchSequential(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema := func(name, namespace, imports string) {
+		t.Helper()
+		content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="%s">
+%s  <xs:complexType name="%sType">
+    <xs:sequence><xs:element name="value" type="xs:string"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`, namespace, imports, name)
+		if err := os.WriteFile(filepath.Join(dir, name+".xsd"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	importOf := func(names ...string) string {
+		var imports string
+		for _, name := range names {
+			imports += fmt.Sprintf(`  <xs:import namespace="http://example.com/%s" schemaLocation="%s.xsd"/>`+"\n", name, name)
+		}
+		return imports
+	}
+
+	// common is imported by both a and c, so the first one in document order must own it
+	writeSchema("common", "http://example.com/common", "")
+	writeSchema("a", "http://example.com/a", importOf("common"))
+	writeSchema("b", "http://example.com/b", "")
+	writeSchema("c", "http://example.com/c", importOf("common", "b"))
+	writeSchema("root", "http://example.com/root", importOf("a", "b", "c"))
+
+	parse := func(maxParallel int) interface{} {
+		t.Helper()
+		p := parser.New()
+		p.SetMaxParallelImports(maxParallel)
+		schema, err := p.ParseFileWithImports(filepath.Join(dir, "root.xsd"))
+		if err != nil {
+			t.Fatalf("Parse with %d parallel imports failed: %v", maxParallel, err)
+		}
+		return schema
+	}
+
+	sequential := parse(1)
+	for i := 0; i < 10; i++ {
+		if parallel := parse(4); !reflect.DeepEqual(sequential, parallel) {
+			t.Fatalf("Parallel parsing built a different schema tree than sequential parsing")
+		}
+	}
+}
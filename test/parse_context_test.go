@@ -0,0 +1,59 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestParseFileWithImportsContextTimeout tests that an expired context aborts parsing
+func TestParseFileWithImportsContextTimeout(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/ctx">
+    <xs:include schemaLocation="common.xsd"/>
+</xs:schema>`,
+		"common.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/ctx">
+    <xs:complexType name="Common">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	mainPath := filepath.Join(dir, "main.xsd")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+
+	start := time.Now()
+	_, err := parser.New().ParseFileWithImportsContext(ctx, mainPath)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Parsing should abort promptly, took %v", elapsed)
+	}
+
+	// The wrapper without a context still parses the includes
+	schema, err := parser.New().ParseFileWithImports(mainPath)
+	if err != nil {
+		t.Fatalf("ParseFileWithImports failed: %v", err)
+	}
+	if len(schema.ImportedSchemas) != 1 {
+		t.Errorf("Expected 1 included schema, got %d", len(schema.ImportedSchemas))
+	}
+}
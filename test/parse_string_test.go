@@ -0,0 +1,72 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const inMemoryXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/memory">
+    <xs:complexType name="Note">
+        <xs:sequence>
+            <xs:element name="text" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+// TestParseStringAndBytes tests parsing XSD content held in memory
+func TestParseStringAndBytes(t *testing.T) {
+	fromString, err := xsd2proto.ParseString(inMemoryXSD)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	fromBytes, err := xsd2proto.ParseBytes([]byte(inMemoryXSD))
+	if err != nil {
+		t.Fatalf("ParseBytes failed: %v", err)
+	}
+
+	if fromString.TargetNamespace != "http://example.com/memory" || len(fromString.ComplexTypes) != 1 {
+		t.Errorf("ParseString returned unexpected schema: %+v", fromString)
+	}
+	if fromBytes.TargetNamespace != fromString.TargetNamespace || len(fromBytes.ComplexTypes) != 1 {
+		t.Errorf("ParseBytes returned unexpected schema: %+v", fromBytes)
+	}
+}
+
+// TestParseStringMalformedXML tests that in-memory parse errors match the file-based path
+func TestParseStringMalformedXML(t *testing.T) {
+	malformed := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Broken">
+        <xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	tmpFile := filepath.Join(t.TempDir(), "broken.xsd")
+	if err := os.WriteFile(tmpFile, []byte(malformed), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+
+	p := parser.New()
+	_, fileErr := p.ParseFile(tmpFile)
+	_, stringErr := p.ParseString(malformed)
+	_, bytesErr := p.ParseBytes([]byte(malformed))
+
+	if fileErr == nil || stringErr == nil || bytesErr == nil {
+		t.Fatalf("All parse paths should fail: file=%v string=%v bytes=%v", fileErr, stringErr, bytesErr)
+	}
+	// The file-based error is additionally prefixed with the file name
+	fileMsg := strings.TrimPrefix(fileErr.Error(), tmpFile+":")
+	if stringErr.Error() != fileMsg || bytesErr.Error() != fileMsg {
+		t.Errorf("In-memory errors should match the file-based error\nfile:   %v\nstring: %v\nbytes:  %v", fileErr, stringErr, bytesErr)
+	}
+	if !strings.HasPrefix(stringErr.Error(), "5:") {
+		t.Errorf("Error should report the offending line, got: %v", stringErr)
+	}
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const proto3OptionalTestXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:simpleType name="Level">
+        <xs:restriction base="xs:int">
+            <xs:enumeration value="1"/>
+            <xs:enumeration value="2"/>
+        </xs:restriction>
+    </xs:simpleType>
+    <xs:complexType name="Address">
+        <xs:sequence>
+            <xs:element name="city" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+    <xs:complexType name="Person">
+        <xs:sequence>
+            <xs:element name="name" type="xs:string"/>
+            <xs:element name="nickname" type="xs:string" minOccurs="0"/>
+            <xs:element name="address" type="Address" minOccurs="0"/>
+            <xs:element name="level" type="Level" minOccurs="0"/>
+            <xs:element name="born" type="xs:dateTime" minOccurs="0"/>
+        </xs:sequence>
+        <xs:attribute name="age" type="xs:int"/>
+    </xs:complexType>
+</xs:schema>`
+
+func convertProto3Optional(t *testing.T, args ...string) string {
+	t.Helper()
+	cli := buildCLI(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "person.xsd")
+	if err := os.WriteFile(xsdPath, []byte(proto3OptionalTestXSD), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	args = append(args, "--no-header", "--stdout", xsdPath)
+	output, err := exec.Command(cli, args...).Output()
+	if err != nil {
+		t.Fatalf("CLI failed: %v", err)
+	}
+	return string(output)
+}
+
+// TestProto3OptionalScalarFields tests that --proto3-optional keeps optional only on scalar and enum fields
+func TestProto3OptionalScalarFields(t *testing.T) {
+	result := convertProto3Optional(t, "--proto3-optional")
+
+	expected := []string{
+		"  string name = 1;\n",
+		"  optional string nickname = 2;\n",
+		"  Address address = 3;\n",
+		"  optional Level level = 4;\n",
+		"  google.protobuf.Timestamp born = 5;\n",
+		"  optional int32 age = 6;\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+}
+
+// TestProto3OptionalDefault tests that every optional field is labelled optional by default
+func TestProto3OptionalDefault(t *testing.T) {
+	result := convertProto3Optional(t)
+
+	for _, want := range []string{"optional string nickname = 2;", "optional Address address = 3;", "optional google.protobuf.Timestamp born = 5;"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+}
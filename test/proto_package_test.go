@@ -0,0 +1,62 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestProtoPackageFlag tests that -P and --proto-package override the namespace-derived package
+func TestProtoPackageFlag(t *testing.T) {
+	cli := buildCLI(t)
+
+	for _, flag := range []string{"-P", "--proto-package"} {
+		t.Run(flag, func(t *testing.T) {
+			outputFile := filepath.Join(t.TempDir(), "simple.proto")
+			cmd := exec.Command(cli, flag, "mycompany.billing.v2", "-p", "example.com/simple",
+				"-o", outputFile, "examples/001_simple/simple.xsd")
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("CLI failed: %v\nOutput: %s", err, output)
+			}
+
+			content, err := os.ReadFile(outputFile)
+			if err != nil {
+				t.Fatalf("Failed to read output file: %v", err)
+			}
+			if !strings.Contains(string(content), "package mycompany.billing.v2;") {
+				t.Errorf("Expected the overridden package\nActual content:\n%s", content)
+			}
+			if !strings.Contains(string(content), `option go_package = "example.com/simple";`) {
+				t.Errorf("Expected go_package to stay independent of the proto package\nActual content:\n%s", content)
+			}
+		})
+	}
+}
+
+// TestSetPackageName tests that the converter applies the package before namespace derivation
+func TestSetPackageName(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:company:domain:v2">
+  <xs:element name="Invoice" type="xs:string"/>
+</xs:schema>`
+
+	schema, err := parser.New().Parse(strings.NewReader(xsdContent))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	conv := converter.New()
+	conv.SetPackageName("mycompany.billing.v2")
+	protoFile, err := conv.Convert(schema)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if protoFile.Package != "mycompany.billing.v2" {
+		t.Errorf("Expected package mycompany.billing.v2, got %s", protoFile.Package)
+	}
+}
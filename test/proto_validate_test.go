@@ -0,0 +1,114 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// TestProtoFileValidateReportsAllIssues tests that every inconsistency of a proto file is returned
+func TestProtoFileValidateReportsAllIssues(t *testing.T) {
+	protoFile := &model.ProtoFile{
+		Syntax:  "proto3",
+		Package: "invalid",
+		Enums: []model.ProtoEnum{{
+			Name:   "Status",
+			Values: []model.ProtoEnumValue{{Name: "STATUS_ACTIVE", Number: 1}},
+		}},
+		Messages: []model.ProtoMessage{
+			{
+				Name: "Order",
+				Fields: []model.ProtoField{
+					{Name: "id", Type: "string", Number: 1},
+					{Name: "code", Type: "string", Number: 1},
+					{Name: "status", Type: "Status", Number: 2},
+					{Name: "customer", Type: "Customer", Number: 3},
+					{Name: "created", Type: "google.protobuf.Timestamp", Number: 4},
+					{Name: "line", Type: "Line", Number: 5},
+					{Name: "internal", Type: "string", Number: 19000},
+					{Name: "huge", Type: "string", Number: 536870912},
+				},
+				Messages: []model.ProtoMessage{{
+					Name:   "Line",
+					Fields: []model.ProtoField{{Name: "sku", Type: "string", Number: 1}},
+				}},
+			},
+			{Name: ""},
+		},
+	}
+
+	errs := protoFile.Validate()
+	expected := []string{
+		"enum Status: first value STATUS_ACTIVE is 1, proto3 requires 0",
+		"message Order: fields id and code share number 1",
+		"message Order: field customer has undefined type Customer",
+		"message Order: field internal: field number 19000 is in the range 19000 to 19999 reserved by protobuf",
+		"message Order: field huge: field number 536870912 exceeds the maximum 536870911",
+		"message with empty name found",
+	}
+	if len(errs) != len(expected) {
+		t.Errorf("Expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for _, exp := range expected {
+		found := false
+		for _, err := range errs {
+			if strings.Contains(err.Error(), exp) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected an error containing %q, got %v", exp, errs)
+		}
+	}
+}
+
+// TestProtoFileValidateImportedTypes tests that unqualified types may come from project imports
+func TestProtoFileValidateImportedTypes(t *testing.T) {
+	protoFile := &model.ProtoFile{
+		Syntax:  "proto3",
+		Imports: []string{"customer.proto"},
+		Messages: []model.ProtoMessage{{
+			Name:   "Order",
+			Fields: []model.ProtoField{{Name: "customer", Type: "Customer", Number: 1}},
+		}},
+	}
+	if errs := protoFile.Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
+
+// TestGenerateRejectsInvalidProtoFile tests that the generator validates before rendering
+func TestGenerateRejectsInvalidProtoFile(t *testing.T) {
+	protoFile := &model.ProtoFile{
+		Syntax: "proto3",
+		Messages: []model.ProtoMessage{{
+			Name: "Order",
+			Fields: []model.ProtoField{
+				{Name: "id", Type: "string", Number: 1},
+				{Name: "code", Type: "string", Number: 1},
+			},
+		}},
+	}
+	_, err := generator.New().Generate(protoFile)
+	if err == nil || !strings.Contains(err.Error(), "share number 1") {
+		t.Errorf("Expected a duplicate field number error, got %v", err)
+	}
+}
+
+// TestGenerateDescriptorRejectsReservedFieldNumber tests that a descriptor is not
+// built for a field number protobuf reserves
+func TestGenerateDescriptorRejectsReservedFieldNumber(t *testing.T) {
+	protoFile := &model.ProtoFile{
+		Syntax: "proto3",
+		Messages: []model.ProtoMessage{{
+			Name:   "Order",
+			Fields: []model.ProtoField{{Name: "id", Type: "string", Number: 19500}},
+		}},
+	}
+	_, err := generator.GenerateDescriptor(protoFile)
+	if err == nil || !strings.Contains(err.Error(), "reserved by protobuf") {
+		t.Errorf("Expected a reserved field number error, got %v", err)
+	}
+}
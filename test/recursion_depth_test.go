@@ -0,0 +1,84 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// writeImportChain writes level0.xsd importing level1.xsd and so on down to
+// level<levels>.xsd, and returns the path of level0.xsd
+func writeImportChain(t *testing.T, levels int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i := 0; i <= levels; i++ {
+		var imports string
+		if i < levels {
+			imports = fmt.Sprintf(`  <xs:import namespace="http://example.com/level%d" schemaLocation="level%d.xsd"/>`+"\n", i+1, i+1)
+		}
+		content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/level%d">
+%s  <xs:complexType name="Level%d">
+    <xs:sequence><xs:element name="value" type="xs:string"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`, i, imports, i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("level%d.xsd", i)), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write level%d.xsd: %v", i, err)
+		}
+	}
+	return filepath.Join(dir, "level0.xsd")
+}
+
+// TestMaxRecursionDepth tests that an import chain within the limit is parsed and
+// one nested deeper fails with a MaxRecursionDepthError
+func TestMaxRecursionDepth(t *testing.T) {
+	root := writeImportChain(t, 3)
+
+	p := parser.New()
+	p.SetMaxRecursionDepth(10)
+	schema, err := p.ParseFileWithImports(root)
+	if err != nil {
+		t.Fatalf("Parsing a chain of 3 imports with depth 10 failed: %v", err)
+	}
+	depth := 0
+	for s := schema; len(s.ImportedSchemas) > 0; s = s.ImportedSchemas[0] {
+		depth++
+	}
+	if depth != 3 {
+		t.Errorf("Expected 3 levels of imported schemas, got %d", depth)
+	}
+
+	p.SetMaxRecursionDepth(2)
+	_, err = p.ParseFileWithImports(root)
+	var depthErr *xsd2proto.MaxRecursionDepthError
+	if !errors.As(err, &depthErr) {
+		t.Fatalf("Expected a MaxRecursionDepthError with depth 2, got %v", err)
+	}
+	if depthErr.Depth != 3 || filepath.Base(depthErr.File) != "level3.xsd" {
+		t.Errorf("Expected level3.xsd at depth 3, got %s at depth %d", depthErr.File, depthErr.Depth)
+	}
+}
+
+// TestMaxRecursionDepthOption tests that the conversion options limit the depth
+func TestMaxRecursionDepthOption(t *testing.T) {
+	root := writeImportChain(t, 3)
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = filepath.Join(t.TempDir(), "out.proto")
+	opts.MaxRecursionDepth = 1
+	err := xsd2proto.ConvertFile(root, opts)
+	var depthErr *xsd2proto.MaxRecursionDepthError
+	if !errors.As(err, &depthErr) || depthErr.Depth != 2 {
+		t.Fatalf("Expected a MaxRecursionDepthError at depth 2, got %v", err)
+	}
+
+	opts.MaxRecursionDepth = 0
+	if err := xsd2proto.ConvertFile(root, opts); err != nil {
+		t.Errorf("Expected the default depth to allow 3 levels, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRecursiveComplexType tests that self-referential types convert without hanging
+func TestRecursiveComplexType(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/tree"
+           targetNamespace="http://example.com/tree">
+  <xs:complexType name="TreeNode">
+    <xs:sequence>
+      <xs:element name="value" type="xs:string"/>
+      <xs:element name="parent" type="tns:TreeNode" minOccurs="0"/>
+      <xs:element name="children" type="tns:TreeNode" minOccurs="0" maxOccurs="unbounded"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	expected := []string{
+		"message TreeNode {",
+		"optional TreeNode parent = 2;",
+		"repeated TreeNode children = 3;",
+	}
+	for _, line := range expected {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q\nActual content:\n%s", line, content)
+		}
+	}
+	if strings.Count(content, "message TreeNode") != 1 {
+		t.Errorf("TreeNode should be emitted once\nActual content:\n%s", content)
+	}
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestRedefineOverridesIncludedType tests that a redefined type derived from its
+// original replaces the included one under the original name, extensions adding
+// fields after the original ones and restrictions narrowing its facets
+func TestRedefineOverridesIncludedType(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/base">
+    <xs:complexType name="BaseType">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+    <xs:simpleType name="Code">
+        <xs:restriction base="xs:string">
+            <xs:enumeration value="A"/>
+            <xs:enumeration value="B"/>
+            <xs:enumeration value="C"/>
+        </xs:restriction>
+    </xs:simpleType>
+    <xs:complexType name="Untouched">
+        <xs:sequence>
+            <xs:element name="note" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`,
+		"main.xsd": `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/base"
+           targetNamespace="http://example.com/base">
+    <xs:redefine schemaLocation="base.xsd">
+        <xs:complexType name="BaseType">
+            <xs:complexContent>
+                <xs:extension base="tns:BaseType">
+                    <xs:sequence>
+                        <xs:element name="extra" type="xs:int"/>
+                    </xs:sequence>
+                </xs:extension>
+            </xs:complexContent>
+        </xs:complexType>
+        <xs:simpleType name="Code">
+            <xs:restriction base="tns:Code">
+                <xs:enumeration value="A"/>
+                <xs:enumeration value="B"/>
+            </xs:restriction>
+        </xs:simpleType>
+    </xs:redefine>
+    <xs:complexType name="Holder">
+        <xs:sequence>
+            <xs:element name="base" type="tns:BaseType"/>
+            <xs:element name="c" type="tns:Code"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	schema, err := parser.New().ParseFileWithImports(filepath.Join(dir, "main.xsd"))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	result, err := generator.New(generator.WithHeader(false, "")).Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+
+	expected := []string{
+		"message BaseType {\n  string id = 1;\n  int32 extra = 2;\n}",
+		"message Untouched {",
+		"BaseType base = 1;",
+		`string c = 2; // Valid values: "A", "B"` + "\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+	if strings.Count(result, "message BaseType") != 1 || strings.Contains(result, "BaseType1") {
+		t.Errorf("Expected a single BaseType message, got:\n%s", result)
+	}
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+// TestReportFlag tests that --report writes a JSON summary of the conversion
+func TestReportFlag(t *testing.T) {
+	cli := buildCLI(t)
+
+	dir := t.TempDir()
+	reportPath := filepath.Join(dir, "report.json")
+	outputPath := filepath.Join(dir, "main.proto")
+
+	output, err := exec.Command(cli, "--report", reportPath, "-o", outputPath, "examples/003_multifile/main.xsd").CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI failed: %v\n%s", err, output)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("Report was not written: %v", err)
+	}
+	var report xsd2proto.ConversionReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Report is not valid JSON: %v\n%s", err, data)
+	}
+
+	if report.Proto.Messages == 0 {
+		t.Errorf("Expected a non-zero message count, got report:\n%s", data)
+	}
+	if report.Schema.ComplexTypes == 0 || report.Proto.Fields == 0 {
+		t.Errorf("Expected schema and field counts, got report:\n%s", data)
+	}
+	if !reflect.DeepEqual(report.InputPaths, []string{"examples/003_multifile/main.xsd"}) {
+		t.Errorf("Unexpected input paths: %v", report.InputPaths)
+	}
+	if !reflect.DeepEqual(report.OutputPaths, []string{outputPath}) {
+		t.Errorf("Unexpected output paths: %v", report.OutputPaths)
+	}
+	if len(report.ResolvedImports) != 1 || !strings.HasSuffix(report.ResolvedImports[0], "multi_file.xsd") {
+		t.Errorf("Expected the imported schema to be listed, got %v", report.ResolvedImports)
+	}
+	if report.DurationMs < 0 {
+		t.Errorf("Unexpected duration: %d", report.DurationMs)
+	}
+}
+
+// TestLastConversionReport tests the report of library conversions, including
+// renamed types and unsupported constructs
+func TestLastConversionReport(t *testing.T) {
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "report.xsd")
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/report">
+  <xs:annotation><xs:documentation>Uses <xs:group/> in prose only</xs:documentation></xs:annotation>
+  <xs:group name="Common">
+    <xs:sequence>
+      <xs:element name="note" type="xs:string"/>
+    </xs:sequence>
+  </xs:group>
+  <xs:complexType name="order">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string"/>
+      <xs:any minOccurs="0"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="code" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+	if err := os.WriteFile(xsdPath, []byte(xsd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.Output = &strings.Builder{}
+	if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+
+	report := xsd2proto.LastConversionReport()
+	if report == nil {
+		t.Fatal("Expected a report after a successful conversion")
+	}
+	if report.Proto.Messages != 2 {
+		t.Errorf("Expected 2 messages, got %d", report.Proto.Messages)
+	}
+	if len(report.OutputPaths) != 0 {
+		t.Errorf("Expected no output paths when writing to a stream, got %v", report.OutputPaths)
+	}
+	expectedRenames := []xsd2proto.TypeRename{{Original: "Order", Renamed: "Order2"}}
+	if !reflect.DeepEqual(report.Renames, expectedRenames) {
+		t.Errorf("Expected renames %v, got %v", expectedRenames, report.Renames)
+	}
+	expectedUnsupported := []string{"xs:any", "xs:group"}
+	if !reflect.DeepEqual(report.Unsupported, expectedUnsupported) {
+		t.Errorf("Expected unsupported features %v, got %v", expectedUnsupported, report.Unsupported)
+	}
+}
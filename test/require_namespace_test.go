@@ -0,0 +1,48 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestE2ERequireTargetNamespace tests that --require-target-namespace rejects XSDs without a namespace
+func TestE2ERequireTargetNamespace(t *testing.T) {
+	cli := buildCLI(t)
+
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:complexType name="Note">
+        <xs:sequence>
+            <xs:element name="text" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "no_namespace.xsd")
+	outputFile := filepath.Join(dir, "no_namespace.proto")
+	if err := os.WriteFile(inputFile, []byte(xsdContent), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+
+	cmd := exec.Command(cli, "--require-target-namespace", inputFile)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("CLI should fail when the target namespace is missing")
+	}
+	if !strings.Contains(string(output), "target namespace is missing") {
+		t.Errorf("Error message should say the target namespace is missing, got: %s", output)
+	}
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		t.Error("No output file should be written when the target namespace is missing")
+	}
+
+	// Without the flag the same file converts fine
+	cmd = exec.Command(cli, inputFile)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("CLI conversion without the flag failed: %v\nOutput: %s", err, output)
+	}
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// TestGenerateReservedFields tests the reserved statements of a message
+func TestGenerateReservedFields(t *testing.T) {
+	protoFile := &model.ProtoFile{
+		Syntax:  "proto3",
+		Package: "people",
+		Messages: []model.ProtoMessage{{
+			Name:            "Person",
+			Fields:          []model.ProtoField{{Name: "name", Type: "string", Number: 1}},
+			ReservedNumbers: []int{3, 7},
+			ReservedNames:   []string{"fax", "phone"},
+		}},
+	}
+
+	content, err := generator.New().Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := `message Person {
+  reserved 3, 7;
+  reserved "fax", "phone";
+  optional string name = 1;
+}`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Expected reserved statements:\n%s\nActual content:\n%s", expected, content)
+	}
+}
+
+// TestReservedMap tests that removed fields are reserved and their numbers not reused
+func TestReservedMap(t *testing.T) {
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "person.xsd")
+	reservedPath := filepath.Join(dir, "reserved.json")
+	if err := os.WriteFile(xsdPath, []byte(fieldMapXSD("name", "email", "nickname")), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+	reserved := `{"Person": [{"number": 2, "name": "phone"}, {"number": 4, "name": "fax"}]}`
+	if err := os.WriteFile(reservedPath, []byte(reserved), 0644); err != nil {
+		t.Fatalf("Failed to write reserved map: %v", err)
+	}
+
+	var output strings.Builder
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.Output = &output
+	opts.ReservedMapPath = reservedPath
+	if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	content := output.String()
+	for _, expected := range []string{
+		"reserved 2, 4;",
+		`reserved "fax", "phone";`,
+		"string name = 1;",
+		"string email = 3;",
+		"string nickname = 5;",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q\nActual content:\n%s", expected, content)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const reservedWordsXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/reserved"
+           targetNamespace="http://example.com/reserved">
+  <xs:complexType name="optional">
+    <xs:sequence>
+      <xs:element name="value" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="message">
+    <xs:sequence>
+      <xs:element name="option" type="xs:string"/>
+      <xs:element name="map" type="xs:int"/>
+      <xs:element name="message" type="tns:optional"/>
+      <xs:element name="max" type="xs:string"/>
+    </xs:sequence>
+    <xs:attribute name="package" type="xs:string"/>
+  </xs:complexType>
+</xs:schema>`
+
+// TestReservedWordNames tests that keywords are kept as field names, which protoc
+// accepts, while type names protoc would read as a keyword are renamed
+func TestReservedWordNames(t *testing.T) {
+	conv := converter.New()
+	conv.SetKeepOriginalNames(true)
+	content := generateProto(t, reservedWordsXSD, conv)
+
+	expected := []string{
+		"message optional_ {\n  string value = 1;\n}",
+		"message message_ {",
+		"string option = 1;",
+		"int32 map = 2;",
+		"optional_ message = 3;",
+		"string max = 4;",
+		"optional string package = 5;",
+	}
+	for _, line := range expected {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q\nActual content:\n%s", line, content)
+		}
+	}
+
+	// PascalCase type names are not keywords
+	content = generateProto(t, reservedWordsXSD, nil)
+	for _, line := range []string{"message Optional {", "message Message {", "Optional message = 3;"} {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q\nActual content:\n%s", line, content)
+		}
+	}
+
+	schema, err := parser.New().Parse(strings.NewReader(reservedWordsXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := conv.Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	descriptor, err := generator.GenerateDescriptor(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to build descriptor: %v", err)
+	}
+	descriptor.Name = proto.String("reserved_words.proto")
+	if _, err := protodesc.NewFile(descriptor, protoregistry.GlobalFiles); err != nil {
+		t.Errorf("Descriptor is not well-formed: %v", err)
+	}
+}
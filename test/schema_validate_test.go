@@ -0,0 +1,64 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestSchemaValidateReportsAllIssues tests that validation collects every issue
+func TestSchemaValidateReportsAllIssues(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/invalid">
+  <xs:element name="order" type="xs:string"/>
+  <xs:element name="order" type="xs:int"/>
+  <xs:complexType>
+    <xs:sequence>
+      <xs:element name="id" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="Address"/>
+  <xs:complexType name="Address"/>
+  <xs:simpleType name="Status">
+    <xs:restriction base="xs:string"/>
+  </xs:simpleType>
+  <xs:complexType name="Status"/>
+  <xs:simpleType>
+    <xs:restriction base="xs:string"/>
+  </xs:simpleType>
+</xs:schema>`
+
+	p := parser.New()
+	schema, err := p.ParseString(xsdContent)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	_, err = p.Validate(schema)
+	if err == nil {
+		t.Fatal("Expected validation to fail")
+	}
+	expected := []string{
+		"duplicate element name order",
+		"complexType with empty name found",
+		"duplicate type name Address",
+		"simpleType with empty name found",
+	}
+	for _, message := range expected {
+		if !strings.Contains(err.Error(), message) {
+			t.Errorf("Expected error to contain %q, got:\n%v", message, err)
+		}
+	}
+	if lines := strings.Split(err.Error(), "\n"); len(lines) != len(expected) {
+		t.Errorf("Expected %d issues, got %d:\n%v", len(expected), len(lines), err)
+	}
+
+	valid, err := p.ParseString(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="a" type="xs:string"/></xs:schema>`)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected valid schema, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const smallIntegerXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/ints">
+  <xs:complexType name="Reading">
+    <xs:sequence>
+      <xs:element name="offset" type="xs:byte"/>
+      <xs:element name="level" type="xs:unsignedByte"/>
+      <xs:element name="delta" type="xs:short"/>
+      <xs:element name="port" type="xs:unsignedShort"/>
+      <xs:element name="count" type="xs:int"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// TestSmallIntegerComments tests commenting small integer fields with their XSD type
+func TestSmallIntegerComments(t *testing.T) {
+	conv := converter.New()
+	conv.SetSmallIntegerComments(true)
+	content := generateProto(t, smallIntegerXSD, conv)
+
+	expected := []string{
+		"int32 offset = 1; // xs:byte",
+		"int32 level = 2; // xs:unsignedByte",
+		"int32 delta = 3; // xs:short",
+		"uint32 port = 4; // xs:unsignedShort",
+		"int32 count = 5;\n",
+	}
+	for _, line := range expected {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q\nActual content:\n%s", line, content)
+		}
+	}
+}
+
+// TestExactIntegerTypes tests mapping small integer types to zigzag and unsigned types
+func TestExactIntegerTypes(t *testing.T) {
+	content := generateProto(t, smallIntegerXSD, nil)
+	if !strings.Contains(content, "int32 offset = 1;\n") {
+		t.Errorf("xs:byte should map to int32 by default\nActual content:\n%s", content)
+	}
+
+	conv := converter.New()
+	conv.SetExactIntegerTypes(true)
+	content = generateProto(t, smallIntegerXSD, conv)
+
+	expected := []string{
+		"sint32 offset = 1;",
+		"uint32 level = 2;",
+		"sint32 delta = 3;",
+		"uint32 port = 4;",
+		" int32 count = 5;",
+	}
+	for _, line := range expected {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q\nActual content:\n%s", line, content)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const sourceInfoXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/orders"
+           targetNamespace="http://example.com/orders">
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string"/>
+      <xs:element ref="tns:note"/>
+    </xs:sequence>
+    <xs:attribute name="currency" type="xs:string"/>
+  </xs:complexType>
+  <xs:element name="note" type="xs:string"/>
+  <xs:element name="Invoice">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="total" type="xs:double"/>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`
+
+// TestSourceInfo tests that messages and fields are commented with the file and line
+// of their XSD declaration, and that the report lists the same locations
+func TestSourceInfo(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "orders.xsd")
+	if err := os.WriteFile(inputPath, []byte(sourceInfoXSD), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+	outputPath := filepath.Join(dir, "orders.proto")
+	reportPath := filepath.Join(dir, "report.json")
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = outputPath
+	opts.ReportPath = reportPath
+	opts.SourceInfo = true
+	if err := xsd2proto.ConvertFile(inputPath, opts); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	content := string(data)
+	expected := []string{
+		fmt.Sprintf("// source: %s:5\nmessage Order {", inputPath),
+		fmt.Sprintf("  // source: %s:7\n  string id = 1;", inputPath),
+		fmt.Sprintf("  // source: %s:8\n  string note = 2;", inputPath),
+		fmt.Sprintf("  // source: %s:10\n  optional string currency = 3;", inputPath),
+		fmt.Sprintf("// source: %s:13\nmessage Invoice {", inputPath),
+		fmt.Sprintf("  // source: %s:16\n  double total = 1;", inputPath),
+	}
+	for _, part := range expected {
+		if !strings.Contains(content, part) {
+			t.Errorf("Expected %q in output:\n%s", part, content)
+		}
+	}
+
+	data, err = os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	var report xsd2proto.ConversionReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	lines := make(map[string]int)
+	for _, source := range report.Sources {
+		if source.File != inputPath {
+			t.Errorf("Expected source file %s, got %s", inputPath, source.File)
+		}
+		lines[source.Message+"."+source.Field] = source.Line
+	}
+	for key, line := range map[string]int{"Order.": 5, "Order.id": 7, "Invoice.": 13, "Invoice.total": 16} {
+		if lines[key] != line {
+			t.Errorf("Expected %s at line %d in the report, got %d", key, line, lines[key])
+		}
+	}
+}
+
+// TestSourceInfoDisabled tests that no source comments are emitted by default
+func TestSourceInfoDisabled(t *testing.T) {
+	content := generateProto(t, sourceInfoXSD, nil)
+	if strings.Contains(content, "// source:") {
+		t.Errorf("Expected no source comments by default:\n%s", content)
+	}
+}
+
+// TestSourceInfoFromStream tests that top-level messages parsed without a file
+// carry their line and are commented with it alone
+func TestSourceInfoFromStream(t *testing.T) {
+	schema, err := parser.New().Parse(strings.NewReader(sourceInfoXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	for _, message := range protoFile.Messages {
+		if message.SourceLine == 0 || message.SourceFile != "" {
+			t.Errorf("Expected message %s to have a line and no file, got %q:%d", message.Name, message.SourceFile, message.SourceLine)
+		}
+	}
+
+	content, err := generator.New(generator.WithHeader(false, ""), generator.WithSourceInfo(true)).Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+	if !strings.Contains(content, "// source: line 5\nmessage Order {") {
+		t.Errorf("Expected a line-only source comment:\n%s", content)
+	}
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+const specialCharsXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/names">
+  <xs:complexType name="person-record">
+    <xs:sequence>
+      <xs:element name="first-name" type="xs:string"/>
+      <xs:element name="person.age" type="xs:int"/>
+      <xs:element name="HTTP2Status" type="xs:int"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// TestSpecialCharacterNames tests field renaming for names with hyphens, dots and digits
+func TestSpecialCharacterNames(t *testing.T) {
+	content := generateProto(t, specialCharsXSD, nil)
+
+	expected := []string{
+		"message PersonRecord {",
+		"string first_name = 1;",
+		"int32 person_age = 2;",
+		// Acronyms stay one word, and digits stay with the word before them
+		"int32 http2_status = 3;",
+	}
+	for _, line := range expected {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q in snake_case output\nActual content:\n%s", line, content)
+		}
+	}
+
+	conv := converter.New()
+	conv.SetFieldNamingStyle(true, false)
+	content = generateProto(t, specialCharsXSD, conv)
+	for _, line := range []string{"string firstName = 1;", "int32 personAge = 2;"} {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q in camelCase output\nActual content:\n%s", line, content)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestE2ESplitFiles tests that --split-files writes one proto file per message with correct imports
+func TestE2ESplitFiles(t *testing.T) {
+	cli := buildCLI(t)
+
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/split"
+           targetNamespace="http://example.com/split">
+
+    <xs:complexType name="Address">
+        <xs:sequence>
+            <xs:element name="street" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+
+    <xs:complexType name="UserAccount">
+        <xs:sequence>
+            <xs:element name="createdAt" type="xs:dateTime"/>
+            <xs:element name="address" type="tns:Address"/>
+        </xs:sequence>
+    </xs:complexType>
+
+</xs:schema>`
+
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "split.xsd")
+	if err := os.WriteFile(inputFile, []byte(xsdContent), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+	outputDir := filepath.Join(dir, "out")
+
+	cmd := exec.Command(cli, "--split-files", "--no-header", "-o", outputDir, inputFile)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("CLI conversion failed: %v\nOutput: %s", err, output)
+	}
+
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatalf("Failed to read output directory: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 proto files, got %d", len(entries))
+	}
+
+	address, err := os.ReadFile(filepath.Join(outputDir, "address.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read address.proto: %v", err)
+	}
+	addressContent := string(address)
+	if !strings.Contains(addressContent, "message Address {") {
+		t.Errorf("address.proto should contain the Address message\nActual content:\n%s", addressContent)
+	}
+	if strings.Contains(addressContent, "import ") || strings.Contains(addressContent, "message UserAccount") {
+		t.Errorf("address.proto should contain only Address without imports\nActual content:\n%s", addressContent)
+	}
+
+	account, err := os.ReadFile(filepath.Join(outputDir, "user_account.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read user_account.proto: %v", err)
+	}
+	accountContent := string(account)
+	expectedParts := []string{
+		"package split;\n\nimport \"address.proto\";\nimport \"google/protobuf/timestamp.proto\";\n",
+		"message UserAccount {",
+		"Address address = 2;",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(accountContent, part) {
+			t.Errorf("user_account.proto should contain: %q\nActual content:\n%s", part, accountContent)
+		}
+	}
+	if strings.Contains(accountContent, "message Address {") {
+		t.Errorf("user_account.proto should not redefine Address\nActual content:\n%s", accountContent)
+	}
+}
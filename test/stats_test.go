@@ -0,0 +1,69 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/parser"
+	"github.com/i-icc/xsd2proto/internal/stats"
+)
+
+// TestComputeStats tests the statistics of the sample schema
+func TestComputeStats(t *testing.T) {
+	setupTest(t)
+	schema, err := parser.New().ParseFile("examples/001_simple/simple.xsd")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	s := stats.ComputeStats(schema, protoFile)
+	if s.Messages == 0 || s.Fields == 0 {
+		t.Errorf("Expected non-zero message and field counts, got %+v", s)
+	}
+	if s.Messages != len(protoFile.Messages) {
+		t.Errorf("Expected %d messages, got %d", len(protoFile.Messages), s.Messages)
+	}
+	if s.Elements == 0 || s.ComplexTypes == 0 {
+		t.Errorf("Expected non-zero element and complex type counts, got %+v", s)
+	}
+
+	var output strings.Builder
+	if err := s.Write(&output); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if !strings.Contains(output.String(), "Messages generated:") {
+		t.Errorf("Expected the messages row\nActual output:\n%s", output.String())
+	}
+}
+
+// TestComputeStatsNameCollisions tests that type names mapping to the same proto name are reported
+func TestComputeStatsNameCollisions(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/people">
+  <xs:complexType name="Person">
+    <xs:sequence><xs:element name="name" type="xs:string"/></xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="person">
+    <xs:sequence><xs:element name="id" type="xs:int"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+	schema, err := parser.New().Parse(strings.NewReader(xsdContent))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	s := stats.ComputeStats(schema, protoFile)
+	if len(s.Warnings) != 1 || !strings.Contains(s.Warnings[0], `"Person", "person"`) {
+		t.Errorf("Expected one collision warning for Person and person, got %v", s.Warnings)
+	}
+}
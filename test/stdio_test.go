@@ -0,0 +1,90 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const stdioTestXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/pipe">
+    <xs:include schemaLocation="missing.xsd"/>
+    <xs:complexType name="Item">
+        <xs:sequence>
+            <xs:element name="sku" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+// TestStdinStdout tests that the CLI can read the XSD from stdin and write the proto to stdout
+func TestStdinStdout(t *testing.T) {
+	binary := buildCLI(t)
+
+	cmd := exec.Command(binary, "--stdin", "--stdout", "--no-header")
+	cmd.Stdin = strings.NewReader(stdioTestXSD)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("CLI failed: %v\nStderr: %s", err, stderr.String())
+	}
+
+	result := stdout.String()
+	if !strings.HasPrefix(result, `syntax = "proto3";`) {
+		t.Errorf("Expected stdout to contain only the proto, got:\n%s", result)
+	}
+	if !strings.Contains(result, "message Item {") {
+		t.Errorf("Expected message Item in output, got:\n%s", result)
+	}
+	if strings.Contains(result, "Successfully converted") {
+		t.Errorf("Success message should be suppressed with --stdout, got:\n%s", result)
+	}
+	if !strings.Contains(stderr.String(), "Warning: skipping 1 import(s)/include(s)") {
+		t.Errorf("Expected a warning about the skipped include, got stderr:\n%s", stderr.String())
+	}
+}
+
+// TestStdoutWithInputFile tests --stdout on its own, which still takes an input file
+func TestStdoutWithInputFile(t *testing.T) {
+	binary := buildCLI(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "item.xsd")
+	xsd := strings.Replace(stdioTestXSD, `<xs:include schemaLocation="missing.xsd"/>`, "", 1)
+	if err := os.WriteFile(xsdPath, []byte(xsd), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	output, err := exec.Command(binary, "--stdout", "-v", xsdPath).Output()
+	if err != nil {
+		t.Fatalf("CLI failed: %v", err)
+	}
+	if !strings.Contains(string(output), "message Item {") || strings.Contains(string(output), "Converting") {
+		t.Errorf("Expected only the proto on stdout, got:\n%s", output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "item.proto")); !os.IsNotExist(err) {
+		t.Errorf("No output file should be written with --stdout")
+	}
+
+	if err := exec.Command(binary, "--stdout").Run(); err == nil {
+		t.Errorf("Expected --stdout without an input file to fail")
+	}
+}
+
+// TestStdinRequiresOutput tests that --stdin needs somewhere to write the proto
+func TestStdinRequiresOutput(t *testing.T) {
+	binary := buildCLI(t)
+
+	cmd := exec.Command(binary, "--stdin")
+	cmd.Stdin = strings.NewReader(stdioTestXSD)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected --stdin without -o or --stdout to fail")
+	}
+	if !strings.Contains(string(output), "--stdin requires -o or --stdout") {
+		t.Errorf("Unexpected error output: %s", output)
+	}
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	xsderrors "github.com/i-icc/xsd2proto/internal/errors"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+// TestParseErrorLocation tests that parse errors carry the file and position
+func TestParseErrorLocation(t *testing.T) {
+	invalid := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string">
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+	path := filepath.Join(t.TempDir(), "invalid.xsd")
+	if err := os.WriteFile(path, []byte(invalid), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	_, err := parser.New().ParseFile(path)
+	var parseErr *xsderrors.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected a ParseError, got %T: %v", err, err)
+	}
+	if parseErr.Line != 6 || parseErr.Column == 0 {
+		t.Errorf("Expected the error on line 6 with a column, got line %d column %d", parseErr.Line, parseErr.Column)
+	}
+	if parseErr.File != path {
+		t.Errorf("Expected file %s, got %s", path, parseErr.File)
+	}
+	if !strings.HasPrefix(err.Error(), path+":6:") {
+		t.Errorf("Expected a file:line:col message, got %v", err)
+	}
+}
+
+// TestConvertErrorType tests that converter failures name the failing type
+func TestConvertErrorType(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string">
+        <xs:annotation><xs:appinfo source="proto-field-number">1</xs:appinfo></xs:annotation>
+      </xs:element>
+      <xs:element name="code" type="xs:string">
+        <xs:annotation><xs:appinfo source="proto-field-number">1</xs:appinfo></xs:annotation>
+      </xs:element>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+	schema, err := parser.New().ParseString(xsdContent)
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	_, err = converter.New().Convert(schema)
+	var convertErr *xsderrors.ConvertError
+	if !errors.As(err, &convertErr) {
+		t.Fatalf("Expected a ConvertError, got %T: %v", err, err)
+	}
+	if convertErr.TypeName != "Order" {
+		t.Errorf("Expected the error to name Order, got %s", convertErr.TypeName)
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSubstitutionGroup tests that a substitution group becomes a union message with a oneof
+func TestSubstitutionGroup(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/shapes"
+           targetNamespace="http://example.com/shapes">
+  <xs:complexType name="ShapeType">
+    <xs:sequence>
+      <xs:element name="color" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="CircleType">
+    <xs:sequence>
+      <xs:element name="radius" type="xs:double"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="SquareType">
+    <xs:sequence>
+      <xs:element name="side" type="xs:double"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="Drawing">
+    <xs:sequence>
+      <xs:element name="title" type="xs:string"/>
+      <xs:element ref="tns:shape" maxOccurs="unbounded"/>
+    </xs:sequence>
+  </xs:complexType>
+
+  <xs:element name="shape" type="tns:ShapeType"/>
+  <xs:element name="circle" type="tns:CircleType" substitutionGroup="tns:shape"/>
+  <xs:element name="square" type="tns:SquareType" substitutionGroup="tns:shape"/>
+  <xs:element name="label" type="xs:string" substitutionGroup="tns:shape"/>
+</xs:schema>`
+
+	content := generateProto(t, xsdContent, nil)
+
+	expected := `message ShapeGroup {
+  oneof value {
+    CircleType circle = 1;
+    SquareType square = 2;
+    string label = 3;
+  }
+}`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Expected union message:\n%s\nActual content:\n%s", expected, content)
+	}
+	if !strings.Contains(content, "repeated ShapeGroup shape = 2;") {
+		t.Errorf("References to the head element should use the union message\nActual content:\n%s", content)
+	}
+}
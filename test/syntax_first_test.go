@@ -0,0 +1,36 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// TestSyntaxPlacement tests the syntax line placement relative to the header comment
+func TestSyntaxPlacement(t *testing.T) {
+	protoFile := &model.ProtoFile{
+		Syntax:  "proto3",
+		Package: "placement",
+	}
+
+	gen := generator.New(generator.WithHeader(true, "1.2.3"))
+	content, err := gen.Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+	if !strings.HasPrefix(content, "syntax = \"proto3\";\n\n// This proto file was automatically generated") {
+		t.Errorf("Syntax should come before the header by default\nActual content:\n%s", content)
+	}
+
+	gen = generator.New(generator.WithHeader(true, "1.2.3"), generator.WithSyntaxFirst(false))
+	content, err = gen.Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+	if !strings.HasPrefix(content, "// This proto file was automatically generated") ||
+		!strings.Contains(content, "// Generated by xsd2proto version 1.2.3\n\nsyntax = \"proto3\";\n\npackage placement;") {
+		t.Errorf("Header should come before the syntax line\nActual content:\n%s", content)
+	}
+}
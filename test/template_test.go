@@ -0,0 +1,128 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+const messageNamesTemplate = "{{range .Messages}}{{.Name}}\n{{end}}"
+
+func templateProtoFile() *model.ProtoFile {
+	return &model.ProtoFile{
+		Syntax:  "proto3",
+		Package: "templates",
+		Options: []model.ProtoOption{model.StringOption("go_package", "example.com/templates")},
+		Messages: []model.ProtoMessage{
+			{Name: "Order", Fields: []model.ProtoField{{Name: "id", Type: "string", Number: 1, Label: model.FieldLabelRequired}}},
+			{Name: "Customer"},
+			{Name: "Address"},
+		},
+		Enums: []model.ProtoEnum{{Name: "Status", Values: []model.ProtoEnumValue{{Name: "STATUS_UNSPECIFIED"}}}},
+	}
+}
+
+// TestGeneratorSetTemplate tests that a custom template replaces the proto layout
+func TestGeneratorSetTemplate(t *testing.T) {
+	gen := generator.New()
+	gen.SetTemplate(template.Must(template.New("names").Parse(messageNamesTemplate)))
+
+	content, err := gen.Generate(templateProtoFile())
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if content != "Order\nCustomer\nAddress\n" {
+		t.Errorf("Expected only the message names, got:\n%s", content)
+	}
+
+	// Sorting applies to the template data too
+	gen = generator.New(generator.WithSortOutput(true))
+	gen.SetTemplate(template.Must(template.New("names").Parse(messageNamesTemplate)))
+	content, err = gen.Generate(templateProtoFile())
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if content != "Address\nCustomer\nOrder\n" {
+		t.Errorf("Expected sorted message names, got:\n%s", content)
+	}
+
+	// A nil template restores the built-in layout
+	gen.SetTemplate(nil)
+	content, err = gen.Generate(templateProtoFile())
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if !strings.Contains(content, "message Order {\n  string id = 1;\n}") {
+		t.Errorf("Expected the proto layout, got:\n%s", content)
+	}
+}
+
+// TestGeneratorTemplateData tests the rendered definitions and options given to templates
+func TestGeneratorTemplateData(t *testing.T) {
+	gen := generator.New(generator.WithHeader(false, ""))
+	gen.SetTemplate(template.Must(template.New("data").Parse(
+		"{{range .Options}}{{.Key}}={{.Value}}\n{{end}}{{range .Enums}}{{.Proto}}{{end}}{{len .Header}}")))
+
+	content, err := gen.Generate(templateProtoFile())
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	expected := "go_package=example.com/templates\nenum Status {\n  STATUS_UNSPECIFIED = 0;\n}\n0"
+	if content != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, content)
+	}
+}
+
+// TestGeneratorTemplateError tests that a failing template is reported
+func TestGeneratorTemplateError(t *testing.T) {
+	gen := generator.New()
+	gen.SetTemplate(template.Must(template.New("broken").Parse("{{.Missing}}")))
+
+	if _, err := gen.Generate(templateProtoFile()); err == nil || !strings.Contains(err.Error(), "failed to execute template broken") {
+		t.Errorf("Expected a template error, got %v", err)
+	}
+}
+
+// TestConvertTemplate tests the TemplatePath option with a schema file
+func TestConvertTemplate(t *testing.T) {
+	setupTest(t)
+
+	dir := t.TempDir()
+	templatePath := filepath.Join(dir, "names.tmpl")
+	outputPath := filepath.Join(dir, "names.txt")
+	if err := os.WriteFile(templatePath, []byte(messageNamesTemplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := xsd2proto.DefaultConvertOptions()
+	opts.OutputPath = outputPath
+	opts.TemplatePath = templatePath
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err != nil {
+		t.Fatalf("ConvertFile failed: %v", err)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" || strings.ContainsAny(line, " {;") {
+			t.Errorf("Expected one message name per line, got:\n%s", output)
+			break
+		}
+	}
+	if !strings.Contains(string(output), "Address\n") {
+		t.Errorf("Expected Address in output:\n%s", output)
+	}
+
+	opts.TemplatePath = filepath.Join(dir, "missing.tmpl")
+	if err := xsd2proto.ConvertFile("examples/001_simple/simple.xsd", opts); err == nil || !strings.Contains(err.Error(), "failed to load template") {
+		t.Errorf("Expected a template load error, got %v", err)
+	}
+}
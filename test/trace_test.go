@@ -0,0 +1,81 @@
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+// TestTraceFlag tests that --trace writes one JSON object per conversion decision
+func TestTraceFlag(t *testing.T) {
+	binary := buildCLI(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "person.xsd")
+	tracePath := filepath.Join(dir, "trace.jsonl")
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:simpleType name="Level">
+        <xs:restriction base="xs:int">
+            <xs:enumeration value="1"/>
+            <xs:enumeration value="2"/>
+        </xs:restriction>
+    </xs:simpleType>
+    <xs:complexType name="Person">
+        <xs:sequence>
+            <xs:element name="firstName" type="xs:string"/>
+            <xs:element name="level" type="Level"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+	if err := os.WriteFile(xsdPath, []byte(xsd), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	cmd := exec.Command(binary, "--trace", tracePath, "-o", filepath.Join(dir, "person.proto"), xsdPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("CLI failed: %v\nOutput: %s", err, output)
+	}
+
+	file, err := os.Open(tracePath)
+	if err != nil {
+		t.Fatalf("Failed to open trace: %v", err)
+	}
+	defer file.Close()
+
+	var events []converter.TraceEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event converter.TraceEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Trace line is not valid JSON: %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+
+	expected := []converter.TraceEvent{
+		{Action: converter.TraceActionEmitEnum, Enum: "Level"},
+		{Action: converter.TraceActionMapType, XSDType: "xs:string", ProtoType: "string"},
+		{Action: converter.TraceActionMapType, XSDType: "Level", ProtoType: "Level"},
+		{Action: converter.TraceActionEmitMessage, Message: "Person"},
+		{Action: converter.TraceActionEmitField, Message: "Person", Field: "first_name", Type: "string", Number: 1},
+		{Action: converter.TraceActionEmitField, Message: "Person", Field: "level", Type: "Level", Number: 2},
+	}
+	for _, want := range expected {
+		found := false
+		for _, event := range events {
+			if event == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected trace event %+v, got %+v", want, events)
+		}
+	}
+}
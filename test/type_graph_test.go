@@ -0,0 +1,97 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const typeGraphXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/orders"
+           targetNamespace="http://example.com/orders">
+  <xs:simpleType name="orderStatus">
+    <xs:restriction base="xs:int">
+      <xs:enumeration value="1"/>
+      <xs:enumeration value="2"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:complexType name="customer">
+    <xs:sequence>
+      <xs:element name="name" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="order">
+    <xs:sequence>
+      <xs:element name="buyer" type="tns:customer"/>
+      <xs:element name="id" type="xs:string"/>
+    </xs:sequence>
+    <xs:attribute name="orderStatus" type="tns:orderStatus"/>
+  </xs:complexType>
+</xs:schema>`
+
+// TestTypeDependencyGraph tests the DOT graph of XSD type references
+func TestTypeDependencyGraph(t *testing.T) {
+	schema, err := parser.New().Parse(strings.NewReader(typeGraphXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	graph := converter.BuildTypeDependencyGraph(schema)
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 {
+		t.Fatalf("Expected 3 nodes and 2 edges, got %+v", graph)
+	}
+
+	dot := graph.DOT()
+	expected := []string{
+		`"OrderStatus" [label="OrderStatus", shape=ellipse];`,
+		`"Customer" [label="Customer", shape=box];`,
+		`"Order" [label="Order", shape=box];`,
+		`"Order" -> "Customer" [label="buyer"];`,
+		`"Order" -> "OrderStatus" [label="order_status"];`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(dot, line) {
+			t.Errorf("Expected DOT output to contain %q\nActual content:\n%s", line, dot)
+		}
+	}
+	if strings.Contains(dot, `"Customer" ->`) {
+		t.Errorf("Built-in types should not produce edges\nActual content:\n%s", dot)
+	}
+}
+
+// TestDumpTypeGraphCLI tests writing the type graph from the CLI
+func TestDumpTypeGraphCLI(t *testing.T) {
+	binary := buildCLI(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "orders.xsd")
+	if err := os.WriteFile(xsdPath, []byte(typeGraphXSD), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	output, err := exec.Command(binary, "--dump-type-graph=-", "-o", filepath.Join(dir, "orders.proto"), xsdPath).Output()
+	if err != nil {
+		t.Fatalf("CLI failed: %v", err)
+	}
+	if !strings.HasPrefix(string(output), "digraph types {") || strings.Contains(string(output), "Successfully converted") {
+		t.Errorf("Expected only the DOT graph on stdout, got:\n%s", output)
+	}
+
+	graphPath := filepath.Join(dir, "orders.dot")
+	if output, err := exec.Command(binary, "--dump-type-graph", graphPath, xsdPath).CombinedOutput(); err != nil {
+		t.Fatalf("CLI failed: %v\nOutput: %s", err, output)
+	}
+	content, err := os.ReadFile(graphPath)
+	if err != nil {
+		t.Fatalf("Failed to read type graph: %v", err)
+	}
+	if !strings.Contains(string(content), `"Order" -> "Customer" [label="buyer"];`) {
+		t.Errorf("Type graph file is missing the buyer edge:\n%s", content)
+	}
+}
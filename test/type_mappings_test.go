@@ -0,0 +1,41 @@
+package test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+)
+
+// TestDumpTypeMappings tests that --dump-type-mappings prints the active mappings as JSON
+func TestDumpTypeMappings(t *testing.T) {
+	cli := buildCLI(t)
+
+	output, err := exec.Command(cli, "--dump-type-mappings", "--use-exact-integer-types").Output()
+	if err != nil {
+		t.Fatalf("CLI failed: %v\nOutput: %s", err, output)
+	}
+	var mappings map[string]string
+	if err := json.Unmarshal(output, &mappings); err != nil {
+		t.Fatalf("Output is not a JSON object: %v\n%s", err, output)
+	}
+	for xsdType, expected := range map[string]string{
+		"string":   "string",
+		"dateTime": "google.protobuf.Timestamp",
+		"byte":     "sint32",
+		"short":    "sint32",
+	} {
+		if mappings[xsdType] != expected {
+			t.Errorf("Mapping of %s = %q, expected %q", xsdType, mappings[xsdType], expected)
+		}
+	}
+
+	defaults, err := xsd2proto.TypeMappings(xsd2proto.DefaultConvertOptions())
+	if err != nil {
+		t.Fatalf("TypeMappings failed: %v", err)
+	}
+	if len(defaults) != len(mappings) || defaults["byte"] != "int32" {
+		t.Errorf("Expected the default mappings to map byte to int32, got %d mappings with %q", len(defaults), defaults["byte"])
+	}
+}
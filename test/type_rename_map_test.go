@@ -0,0 +1,65 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+)
+
+// TestTypeRenameMap tests inspecting how XSD type names were mapped to proto names
+func TestTypeRenameMap(t *testing.T) {
+	xsdContent := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/rename">
+  <xs:simpleType name="Level">
+    <xs:restriction base="xs:int">
+      <xs:enumeration value="1"/>
+      <xs:enumeration value="2"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:complexType name="level">
+    <xs:sequence>
+      <xs:element name="value" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:complexType name="Person">
+    <xs:sequence>
+      <xs:element name="name" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+  <xs:element name="person">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="firstName" type="xs:string"/>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`
+
+	conv := converter.New()
+	generateProto(t, xsdContent, conv)
+
+	renames := conv.GetTypeRenameMap()
+	expected := map[string]string{
+		"Level":  "Level",
+		"level":  "Level2",
+		"Person": "Person",
+		"person": "Person2",
+	}
+	if !reflect.DeepEqual(renames, expected) {
+		t.Errorf("Expected rename map %v, got %v", expected, renames)
+	}
+
+	// The returned map is a copy
+	renames["level"] = "Changed"
+	if conv.GetTypeRenameMap()["level"] != "Level2" {
+		t.Error("Modifying the returned map should not affect the converter")
+	}
+
+	if names := conv.GetUsedMessageNames(); !reflect.DeepEqual(names, []string{"Level2", "Person", "Person2"}) {
+		t.Errorf("Unexpected message names: %v", names)
+	}
+	if names := conv.GetUsedEnumNames(); !reflect.DeepEqual(names, []string{"Level"}) {
+		t.Errorf("Unexpected enum names: %v", names)
+	}
+}
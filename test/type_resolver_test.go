@@ -0,0 +1,96 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const typeResolverTestXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="http://example.com/orders"
+           xmlns:money="http://example.com/money"
+           targetNamespace="http://example.com/orders">
+    <xs:complexType name="Customer">
+        <xs:sequence>
+            <xs:element name="name" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+    <xs:complexType name="Order">
+        <xs:sequence>
+            <xs:element name="id" type="xs:string"/>
+            <xs:element name="total" type="money:Amount"/>
+            <xs:element name="customer" type="tns:Customer"/>
+        </xs:sequence>
+        <xs:attribute name="currency" type="money:Currency"/>
+    </xs:complexType>
+</xs:schema>`
+
+// TestTypeResolver tests that non built-in types are resolved through the resolver
+func TestTypeResolver(t *testing.T) {
+	type call struct{ namespace, localName string }
+	var calls []call
+
+	conv := converter.New()
+	conv.SetTypeResolver(func(namespace, localName string) (string, string, error) {
+		calls = append(calls, call{namespace, localName})
+		if namespace != "http://example.com/money" {
+			return "", "", nil
+		}
+		return "money.v1." + localName, "money/v1/money.proto", nil
+	})
+
+	result := generateProto(t, typeResolverTestXSD, conv)
+
+	expected := []string{
+		`import "money/v1/money.proto";`,
+		"money.v1.Amount total = 2;",
+		"Customer customer = 3;",
+		"money.v1.Currency currency = 4;",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+	if strings.Count(result, "import ") != 1 {
+		t.Errorf("Expected the resolver import once, got:\n%s", result)
+	}
+
+	for _, c := range calls {
+		if c.localName == "string" {
+			t.Errorf("Built-in types should not be passed to the resolver")
+		}
+	}
+	found := false
+	for _, c := range calls {
+		if c == (call{"http://example.com/orders", "Customer"}) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the resolver to be asked for tns:Customer, calls: %v", calls)
+	}
+}
+
+// TestTypeResolverError tests that resolver errors fail the conversion
+func TestTypeResolverError(t *testing.T) {
+	schema, err := parser.New().Parse(strings.NewReader(typeResolverTestXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	registryDown := errors.New("registry unavailable")
+	conv := converter.New()
+	conv.SetTypeResolver(func(namespace, localName string) (string, string, error) {
+		return "", "", registryDown
+	})
+
+	_, err = conv.Convert(schema)
+	if !errors.Is(err, registryDown) {
+		t.Errorf("Expected the resolver error, got: %v", err)
+	}
+}
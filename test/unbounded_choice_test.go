@@ -0,0 +1,112 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const unboundedChoiceXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/orders">
+  <xs:complexType name="Order">
+    <xs:choice maxOccurs="unbounded">
+      <xs:element name="product" type="xs:string"/>
+      <xs:element name="subscription" type="xs:int"/>
+      <xs:element name="discount" type="xs:decimal"/>
+    </xs:choice>
+    <xs:attribute name="id" type="xs:string"/>
+  </xs:complexType>
+</xs:schema>`
+
+// TestUnboundedChoice tests that a repeated choice becomes a repeated wrapper message with a oneof
+func TestUnboundedChoice(t *testing.T) {
+	content := generateProto(t, unboundedChoiceXSD, nil)
+
+	expected := []string{
+		"message Order {\n  repeated OrderItem items = 1;\n  optional string id = 2;\n}",
+		"message OrderItem {\n  oneof choice {\n    string product = 1;\n    int32 subscription = 2;\n    double discount = 3;\n  }\n}",
+	}
+	for _, block := range expected {
+		if !strings.Contains(content, block) {
+			t.Errorf("Expected %q in output:\n%s", block, content)
+		}
+	}
+
+	schema, err := parser.New().Parse(strings.NewReader(unboundedChoiceXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	descriptor, err := generator.GenerateDescriptor(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to build descriptor: %v", err)
+	}
+	descriptor.Name = proto.String("unbounded_choice.proto")
+	if _, err := protodesc.NewFile(descriptor, protoregistry.GlobalFiles); err != nil {
+		t.Errorf("Descriptor is not well-formed: %v", err)
+	}
+}
+
+// TestUnboundedChoiceSuffix tests the wrapper name suffix and numbering around taken names
+func TestUnboundedChoiceSuffix(t *testing.T) {
+	conv := converter.New()
+	conv.SetUnboundedChoiceSuffix("Entry")
+	content := generateProto(t, unboundedChoiceXSD, conv)
+	if !strings.Contains(content, "repeated OrderEntry items = 1;") || !strings.Contains(content, "message OrderEntry {") {
+		t.Errorf("Expected the OrderEntry wrapper in output:\n%s", content)
+	}
+
+	// A type already named OrderItem keeps its name
+	xsd := strings.Replace(unboundedChoiceXSD, "</xs:schema>",
+		`<xs:complexType name="OrderItem"><xs:sequence><xs:element name="sku" type="xs:string"/></xs:sequence></xs:complexType></xs:schema>`, 1)
+	content = generateProto(t, xsd, nil)
+	for _, line := range []string{"repeated OrderItem2 items = 1;", "message OrderItem2 {", "message OrderItem {\n  string sku = 1;\n}"} {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q in output:\n%s", line, content)
+		}
+	}
+}
+
+// TestUnboundedChoiceInSequence tests that repeated choices nested in a sequence get
+// the wrapper messages of a repeated content choice, one per choice
+func TestUnboundedChoiceInSequence(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/orders">
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string"/>
+      <xs:choice maxOccurs="unbounded">
+        <xs:element name="product" type="xs:string"/>
+        <xs:element name="subscription" type="xs:int"/>
+      </xs:choice>
+      <xs:element name="note" type="xs:string"/>
+      <xs:choice maxOccurs="unbounded">
+        <xs:element name="coupon" type="xs:string"/>
+        <xs:element name="voucher" type="xs:string"/>
+      </xs:choice>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+	content := generateProto(t, xsd, nil)
+
+	expected := []string{
+		"message Order {\n  string id = 1;\n  repeated OrderItem items = 2;\n  string note = 3;\n  repeated OrderItem2 items_1 = 4;\n}",
+		"message OrderItem {\n  oneof choice {\n    string product = 1;\n    int32 subscription = 2;\n  }\n}",
+		"message OrderItem2 {\n  oneof choice {\n    string coupon = 1;\n    string voucher = 2;\n  }\n}",
+	}
+	for _, block := range expected {
+		if !strings.Contains(content, block) {
+			t.Errorf("Expected %q in output:\n%s", block, content)
+		}
+	}
+}
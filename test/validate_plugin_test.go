@@ -0,0 +1,125 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto"
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const validatePluginTestXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+    <xs:simpleType name="Code">
+        <xs:restriction base="xs:string">
+            <xs:minLength value="2"/>
+            <xs:maxLength value="8"/>
+            <xs:pattern value="[A-Z]+\d*"/>
+        </xs:restriction>
+    </xs:simpleType>
+    <xs:complexType name="Product">
+        <xs:sequence>
+            <xs:element name="code" type="Code"/>
+            <xs:element name="aliases" type="Code" maxOccurs="unbounded"/>
+            <xs:element name="name" type="xs:string"/>
+        </xs:sequence>
+    </xs:complexType>
+</xs:schema>`
+
+// TestFacetConstraints tests that length and pattern facets are recorded on fields
+func TestFacetConstraints(t *testing.T) {
+	schema, err := parser.New().Parse(strings.NewReader(validatePluginTestXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+
+	fields := protoFile.Messages[0].Fields
+	expected := []model.FieldConstraint{
+		{Facet: "minLength", Value: "2"},
+		{Facet: "maxLength", Value: "8"},
+		{Facet: "pattern", Value: `[A-Z]+\d*`},
+	}
+	if len(fields[0].Constraints) != len(expected) {
+		t.Fatalf("Expected constraints %v, got %v", expected, fields[0].Constraints)
+	}
+	for i, constraint := range expected {
+		if fields[0].Constraints[i] != constraint {
+			t.Errorf("Expected constraint %v, got %v", constraint, fields[0].Constraints[i])
+		}
+	}
+	if len(fields[2].Constraints) != 0 {
+		t.Errorf("Built-in typed field should have no constraints, got %v", fields[2].Constraints)
+	}
+}
+
+// TestValidatePlugin tests that the plugin emits protoc-gen-validate rules as field options
+func TestValidatePlugin(t *testing.T) {
+	schema, err := parser.New().Parse(strings.NewReader(validatePluginTestXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	protoFile, err := converter.New().Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+
+	if err := generator.ApplyPlugins(protoFile, []generator.ConverterPlugin{generator.NewValidatePlugin()}); err != nil {
+		t.Fatalf("ApplyPlugins failed: %v", err)
+	}
+
+	gen := generator.New(generator.WithHeader(false, ""))
+	result, err := gen.Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+
+	expected := []string{
+		`import "validate/validate.proto";`,
+		`string code = 1 [(validate.rules).string.max_len = 8, (validate.rules).string.min_len = 2, (validate.rules).string.pattern = "[A-Z]+\\d*"];`,
+		`repeated string aliases = 2 [(validate.rules).repeated.items.string.max_len = 8, (validate.rules).repeated.items.string.min_len = 2, (validate.rules).repeated.items.string.pattern = "[A-Z]+\\d*"];`,
+		"string name = 3;\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected output to contain %q\nGot:\n%s", want, result)
+		}
+	}
+}
+
+// TestValidatePluginIsOptional tests that no rules are emitted unless the plugin is enabled
+func TestValidatePluginIsOptional(t *testing.T) {
+	setupTest(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "product.xsd")
+	if err := os.WriteFile(xsdPath, []byte(validatePluginTestXSD), 0644); err != nil {
+		t.Fatalf("Failed to write XSD: %v", err)
+	}
+
+	for _, withPlugin := range []bool{false, true} {
+		opts := xsd2proto.NewConvertOptions()
+		if withPlugin {
+			opts = xsd2proto.NewConvertOptions(xsd2proto.WithPlugins(xsd2proto.NewValidatePlugin()))
+		}
+		opts.OutputPath = filepath.Join(dir, "product.proto")
+		if err := xsd2proto.ConvertFile(xsdPath, opts); err != nil {
+			t.Fatalf("ConvertFile failed: %v", err)
+		}
+		content, err := os.ReadFile(opts.OutputPath)
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		if got := strings.Contains(string(content), "validate.rules"); got != withPlugin {
+			t.Errorf("With plugin %v: validate rules present = %v\nGot:\n%s", withPlugin, got, content)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/parser"
+)
+
+const droppedFeaturesXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/catalog">
+  <xs:element name="Catalog">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="product" type="Product" maxOccurs="unbounded" block="extension"/>
+      </xs:sequence>
+    </xs:complexType>
+    <xs:key name="productKey">
+      <xs:selector xpath="product"/>
+      <xs:field xpath="@sku"/>
+    </xs:key>
+    <xs:unique name="uniqueName">
+      <xs:selector xpath="product"/>
+      <xs:field xpath="name"/>
+    </xs:unique>
+  </xs:element>
+  <xs:complexType name="Product" final="restriction">
+    <xs:sequence>
+      <xs:element name="name" type="xs:string"/>
+    </xs:sequence>
+    <xs:attribute name="sku" type="xs:string"/>
+  </xs:complexType>
+</xs:schema>`
+
+// TestConverterWarnings tests that dropped XSD features are reported as warnings
+func TestConverterWarnings(t *testing.T) {
+	schema, err := parser.New().Parse(strings.NewReader(droppedFeaturesXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	var output bytes.Buffer
+	conv := converter.New()
+	conv.SetWarningWriter(&output)
+	if _, err := conv.Convert(schema); err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+
+	expected := []string{
+		`complexType Product: final="restriction" is not converted`,
+		`element Catalog: xs:key "productKey" is not converted`,
+		`element Catalog: xs:unique "uniqueName" is not converted`,
+		`element product: block="extension" is not converted`,
+	}
+	warnings := conv.GetWarnings()
+	if len(warnings) != len(expected) {
+		t.Fatalf("Expected %d warnings, got %v", len(expected), warnings)
+	}
+	for _, warning := range expected {
+		if !strings.Contains(strings.Join(warnings, "\n"), warning) {
+			t.Errorf("Expected warning %q, got %v", warning, warnings)
+		}
+		if !strings.Contains(output.String(), "Warning: "+warning+"\n") {
+			t.Errorf("Expected warning %q to be printed, got:\n%s", warning, output.String())
+		}
+	}
+
+	// Each conversion starts over, and field renames are warnings too
+	schema, err = parser.New().Parse(strings.NewReader(fieldDedupeXSD))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+	if _, err := conv.Convert(schema); err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+	expected = []string{
+		`field "status" is already used in message Shipment, emitting it as "status_elem"`,
+		`field "status" is already used in message Shipment, emitting it as "status_2"`,
+	}
+	if warnings := conv.GetWarnings(); strings.Join(warnings, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected the field rename warnings only, got %v", warnings)
+	}
+}
+
+// TestReservedWordWarnings tests that type names renamed because they are proto
+// keywords are reported through the converter's warnings in every conversion
+func TestReservedWordWarnings(t *testing.T) {
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="http://example.com/reserved">
+  <xs:complexType name="option">
+    <xs:sequence>
+      <xs:element name="message" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+	conv := converter.New()
+	conv.SetKeepOriginalNames(true)
+	for i := 0; i < 2; i++ {
+		schema, err := parser.New().Parse(strings.NewReader(xsd))
+		if err != nil {
+			t.Fatalf("Failed to parse XSD: %v", err)
+		}
+		if _, err := conv.Convert(schema); err != nil {
+			t.Fatalf("Failed to convert schema: %v", err)
+		}
+		warnings := conv.GetWarnings()
+		if len(warnings) != 1 || warnings[0] != `type name "option" is a proto keyword, emitting it as "option_"` {
+			t.Errorf("Conversion %d: expected the reserved word warning, got %v", i+1, warnings)
+		}
+	}
+}
+
+// TestCLIWarnings tests that the CLI prints warnings to stderr without --verbose
+func TestCLIWarnings(t *testing.T) {
+	cli := buildCLI(t)
+
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "catalog.xsd")
+	if err := os.WriteFile(xsdPath, []byte(droppedFeaturesXSD), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(cli, "--stdout", xsdPath)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("CLI failed: %v\n%s", err, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), `Warning: element Catalog: xs:key "productKey" is not converted`) {
+		t.Errorf("Expected the xs:key warning on stderr, got:\n%s", stderr.String())
+	}
+	if strings.Contains(stdout.String(), "Warning") {
+		t.Errorf("Expected no warnings in the proto output:\n%s", stdout.String())
+	}
+}
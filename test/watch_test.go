@@ -0,0 +1,177 @@
+package test
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const watchXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           targetNamespace="http://example.com/orders">
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="id" type="xs:string"/>
+    </xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+
+// TestWatchMode tests that --watch reconverts the input when it is written and
+// exits cleanly on interrupt
+func TestWatchMode(t *testing.T) {
+	cli := buildCLI(t)
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "orders.xsd")
+	protoPath := filepath.Join(dir, "orders.proto")
+	if err := os.WriteFile(xsdPath, []byte(watchXSD), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+
+	cmd := exec.Command(cli, "--watch", "-o", protoPath, xsdPath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Failed to open stdout: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start watch mode: %v", err)
+	}
+	defer cmd.Process.Kill()
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	waitForLine(t, lines, "Watching ")
+
+	// Watch the output in the test as well to see the reconversion land
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("Failed to start file watcher: %v", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(dir); err != nil {
+		t.Fatalf("Failed to watch %s: %v", dir, err)
+	}
+	reconverted := make(chan struct{})
+	go func() {
+		for event := range watcher.Events {
+			if event.Name != protoPath || !event.Has(fsnotify.Write) {
+				continue
+			}
+			if content, err := os.ReadFile(protoPath); err == nil && strings.Contains(string(content), "total = 2;") {
+				close(reconverted)
+				return
+			}
+		}
+	}()
+
+	changed := strings.Replace(watchXSD, `<xs:element name="id" type="xs:string"/>`,
+		`<xs:element name="id" type="xs:string"/>
+      <xs:element name="total" type="xs:double"/>`, 1)
+	if err := os.WriteFile(xsdPath, []byte(changed), 0644); err != nil {
+		t.Fatalf("Failed to update test XSD: %v", err)
+	}
+
+	select {
+	case <-reconverted:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Expected the proto to be reconverted after the XSD changed")
+	}
+	waitForLine(t, lines, "orders.xsd: converted successfully")
+
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("Failed to interrupt watch mode: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("Expected watch mode to exit cleanly on interrupt, got %v", err)
+	}
+}
+
+// TestWatchFetchRemote tests that --watch --fetch-remote without a cache directory
+// keeps watching after the first conversion and removes the cache on exit
+func TestWatchFetchRemote(t *testing.T) {
+	cli := buildCLI(t)
+	var requests int32
+	server := newSchemaServer(t, &requests)
+	dir := t.TempDir()
+	xsdPath := filepath.Join(dir, "main.xsd")
+	xsd := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:c="http://example.com/common"
+           targetNamespace="http://example.com/orders">
+  <xs:import namespace="http://example.com/common" schemaLocation="` + server.URL + `/schemas/common.xsd"/>
+  <xs:complexType name="Order">
+    <xs:sequence><xs:element name="contact" type="c:Contact"/></xs:sequence>
+  </xs:complexType>
+</xs:schema>`
+	if err := os.WriteFile(xsdPath, []byte(xsd), 0644); err != nil {
+		t.Fatalf("Failed to write test XSD: %v", err)
+	}
+	tmpDir := t.TempDir()
+
+	cmd := exec.Command(cli, "--watch", "--fetch-remote", "-o", filepath.Join(dir, "main.proto"), xsdPath)
+	cmd.Env = append(os.Environ(), "TMPDIR="+tmpDir)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Failed to open stdout: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start watch mode: %v", err)
+	}
+	defer cmd.Process.Kill()
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	waitForLine(t, lines, "Watching ")
+
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("Failed to interrupt watch mode: %v", err)
+	}
+	for range lines {
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("Expected watch mode to exit cleanly on interrupt, got %v", err)
+	}
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected the schema cache to be removed on exit, found %v", entries)
+	}
+}
+
+// waitForLine reads lines until one contains text
+func waitForLine(t *testing.T, lines <-chan string, text string) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("Output ended before a line containing %q", text)
+			}
+			if strings.Contains(line, text) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Timed out waiting for a line containing %q", text)
+		}
+	}
+}
@@ -5,6 +5,10 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+
+	"github.com/i-icc/xsd2proto/internal/converter"
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/parser"
 )
 
 // setupTest changes to the project root directory for each test
@@ -23,12 +27,53 @@ func setupTest(t *testing.T) {
 	}
 }
 
+// buildCLI builds the CLI tool into the project root and removes it when the test ends
+func buildCLI(t *testing.T) string {
+	t.Helper()
+	setupTest(t)
+
+	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "./cmd/xsd2proto")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI tool: %v", err)
+	}
+	t.Cleanup(func() { os.Remove("xsd2proto_test") })
+
+	return "./xsd2proto_test"
+}
+
+// generateProto parses, converts and generates proto content from an in-memory XSD
+func generateProto(t *testing.T, xsdContent string, conv *converter.Converter) string {
+	t.Helper()
+
+	p := parser.New()
+	schema, err := p.Parse(strings.NewReader(xsdContent))
+	if err != nil {
+		t.Fatalf("Failed to parse XSD: %v", err)
+	}
+
+	if conv == nil {
+		conv = converter.New()
+	}
+	protoFile, err := conv.Convert(schema)
+	if err != nil {
+		t.Fatalf("Failed to convert schema: %v", err)
+	}
+
+	gen := generator.New(generator.WithHeader(false, ""))
+	content, err := gen.Generate(protoFile)
+	if err != nil {
+		t.Fatalf("Failed to generate proto: %v", err)
+	}
+
+	return content
+}
+
 // TestE2EBasicConversion tests end-to-end conversion of the sample XSD file
 func TestE2EBasicConversion(t *testing.T) {
 	setupTest(t)
 
 	// Build the CLI tool first
-	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "cmd/xsd2proto/main.go")
+	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "./cmd/xsd2proto")
 	if err := cmd.Run(); err != nil {
 		t.Fatalf("Failed to build CLI tool: %v", err)
 	}
@@ -78,7 +123,7 @@ func TestE2EBasicConversion(t *testing.T) {
 // TestE2EVerboseMode tests CLI with verbose output
 func TestE2EVerboseMode(t *testing.T) {
 	setupTest(t)
-	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "cmd/xsd2proto/main.go")
+	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "./cmd/xsd2proto")
 	if err := cmd.Run(); err != nil {
 		t.Fatalf("Failed to build CLI tool: %v", err)
 	}
@@ -106,7 +151,7 @@ func TestE2EVerboseMode(t *testing.T) {
 // TestE2EGoPackageOption tests CLI with go_package option
 func TestE2EGoPackageOption(t *testing.T) {
 	setupTest(t)
-	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "cmd/xsd2proto/main.go")
+	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "./cmd/xsd2proto")
 	if err := cmd.Run(); err != nil {
 		t.Fatalf("Failed to build CLI tool: %v", err)
 	}
@@ -135,7 +180,7 @@ func TestE2EGoPackageOption(t *testing.T) {
 // TestE2EInvalidFile tests CLI with non-existent file
 func TestE2EInvalidFile(t *testing.T) {
 	setupTest(t)
-	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "cmd/xsd2proto/main.go")
+	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "./cmd/xsd2proto")
 	if err := cmd.Run(); err != nil {
 		t.Fatalf("Failed to build CLI tool: %v", err)
 	}
@@ -156,7 +201,7 @@ func TestE2EInvalidFile(t *testing.T) {
 // TestE2EVersionFlag tests CLI version flag
 func TestE2EVersionFlag(t *testing.T) {
 	setupTest(t)
-	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "cmd/xsd2proto/main.go")
+	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "./cmd/xsd2proto")
 	if err := cmd.Run(); err != nil {
 		t.Fatalf("Failed to build CLI tool: %v", err)
 	}
@@ -177,7 +222,7 @@ func TestE2EVersionFlag(t *testing.T) {
 // TestE2EHelpFlag tests CLI help flag
 func TestE2EHelpFlag(t *testing.T) {
 	setupTest(t)
-	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "cmd/xsd2proto/main.go")
+	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "./cmd/xsd2proto")
 	if err := cmd.Run(); err != nil {
 		t.Fatalf("Failed to build CLI tool: %v", err)
 	}
@@ -207,7 +252,7 @@ func TestE2EHelpFlag(t *testing.T) {
 // TestE2ENoArguments tests CLI with no arguments
 func TestE2ENoArguments(t *testing.T) {
 	setupTest(t)
-	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "cmd/xsd2proto/main.go")
+	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "./cmd/xsd2proto")
 	if err := cmd.Run(); err != nil {
 		t.Fatalf("Failed to build CLI tool: %v", err)
 	}
@@ -228,7 +273,7 @@ func TestE2ENoArguments(t *testing.T) {
 // TestE2EDefaultOutputPath tests CLI with default output path generation
 func TestE2EDefaultOutputPath(t *testing.T) {
 	setupTest(t)
-	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "cmd/xsd2proto/main.go")
+	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "./cmd/xsd2proto")
 	if err := cmd.Run(); err != nil {
 		t.Fatalf("Failed to build CLI tool: %v", err)
 	}
@@ -264,7 +309,7 @@ func TestE2EDefaultOutputPath(t *testing.T) {
 // TestE2EComplexSchema tests conversion with a more complex XSD
 func TestE2EComplexSchema(t *testing.T) {
 	setupTest(t)
-	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "cmd/xsd2proto/main.go")
+	cmd := exec.Command("go", "build", "-o", "xsd2proto_test", "./cmd/xsd2proto")
 	if err := cmd.Run(); err != nil {
 		t.Fatalf("Failed to build CLI tool: %v", err)
 	}
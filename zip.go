@@ -0,0 +1,60 @@
+package xsd2proto
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"github.com/i-icc/xsd2proto/internal/generator"
+	"github.com/i-icc/xsd2proto/internal/model"
+)
+
+// validateZipOutput rejects the options that cannot be combined with OutputZipPath
+func validateZipOutput(opts ConvertOptions) error {
+	switch {
+	case opts.Output != nil:
+		return fmt.Errorf("cannot write a zip archive when writing to a stream")
+	case opts.DescriptorPath != "":
+		return fmt.Errorf("cannot write a descriptor when writing a zip archive")
+	case opts.BufScaffold:
+		return fmt.Errorf("cannot write a buf scaffold when writing a zip archive")
+	case opts.OutputEncoding != "" && opts.OutputEncoding != OutputEncodingUTF8:
+		return fmt.Errorf("zip archives are written in %s, got output encoding %q", OutputEncodingUTF8, opts.OutputEncoding)
+	}
+	return nil
+}
+
+// writeZipArchive writes the proto files into the zip archive at opts.OutputZipPath
+// and returns its path. Split files are placed in the directory of their package;
+// a single file is named after the input, or after the archive for a stream.
+func writeZipArchive(gen *generator.Generator, protoFile *model.ProtoFile, inputPath string, opts ConvertOptions) ([]string, error) {
+	var files map[string]*model.ProtoFile
+	if opts.SplitFiles {
+		files = generator.SplitProtoFile(protoFile)
+		for _, file := range files {
+			if err := generator.ApplyPlugins(file, opts.Plugins); err != nil {
+				return nil, err
+			}
+		}
+		files = generator.ZipSplitFiles(files, protoFile.Package)
+	} else {
+		name := inputPath
+		if name == "" {
+			name = opts.OutputZipPath
+		}
+		files = map[string]*model.ProtoFile{filepath.Base(DefaultOutputPath(name)): protoFile}
+	}
+
+	var archive bytes.Buffer
+	if err := gen.GenerateZip(files, &archive); err != nil {
+		return nil, err
+	}
+	if err := writeToFile(opts.OutputZipPath, archive.String()); err != nil {
+		return nil, fmt.Errorf("failed to write zip archive: %w", err)
+	}
+
+	if opts.Verbose {
+		opts.logf("Successfully generated %s\n", opts.OutputZipPath)
+	}
+	return []string{opts.OutputZipPath}, nil
+}